@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"coscli/util"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -11,32 +12,53 @@ var abortCmd = &cobra.Command{
 	Short: "Abort parts",
 	Long: `Abort parts
 
+Uploads initiated within --older-than are kept, so that uploads still running
+are not aborted. The number and size of parts of the matched uploads are
+summed by storage class, use --dry-run to only print the report.
+
 Format:
   ./coscli abort cos://<bucket-name>[/<prefix>] [flags]
 
 Example:
-  ./coscli abort cos://examplebucket/test/`,
+  ./coscli abort cos://examplebucket/test/
+  ./coscli abort cos://examplebucket/test/ --older-than 24h --dry-run
+  ./coscli abort cos://examplebucket/test/ --older-than 7d --exclude-upload-id <upload-id>`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		include, _ := cmd.Flags().GetString("include")
 		exclude, _ := cmd.Flags().GetString("exclude")
 		failOutput, _ := cmd.Flags().GetBool("fail-output")
 		failOutputPath, _ := cmd.Flags().GetString("fail-output-path")
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		excludeUploadIds, _ := cmd.Flags().GetStringSlice("exclude-upload-id")
+
+		var olderThan time.Duration
+		var err error
+		if olderThanStr != "" {
+			if olderThan, err = util.ParseAge(olderThanStr); err != nil {
+				return err
+			}
+		}
 
 		_, filters := util.GetFilter(include, exclude)
 
 		fo := &util.FileOperations{
 			Operation: util.Operation{
-				FailOutput:     failOutput,
-				FailOutputPath: failOutputPath,
-				Filters:        filters,
+				FailOutput:       failOutput,
+				FailOutputPath:   failOutputPath,
+				Filters:          filters,
+				OlderThan:        olderThan,
+				DryRun:           dryRun,
+				ExcludeUploadIds: excludeUploadIds,
 			},
 			Config:    &config,
 			Param:     &param,
 			ErrOutput: &util.ErrOutput{},
+			Ctx:       commandContext(),
 		}
 
-		err := util.AbortUploads(args, fo)
+		err = util.AbortUploads(args, fo)
 		return err
 	},
 }
@@ -48,4 +70,7 @@ func init() {
 	abortCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
 	abortCmd.Flags().Bool("fail-output", true, "This option determines whether the error output for failed file uploads or downloads is enabled. If enabled, the error messages for any failed file transfers will be recorded in a file within the specified directory (if not specified, the default is coscli_output). If disabled, only the number of error files will be output to the console.")
 	abortCmd.Flags().String("fail-output-path", "coscli_output", "This option specifies the designated error output folder where the error messages for failed file uploads or downloads will be recorded. By providing a custom folder path, you can control the location and name of the error output folder. If this option is not set, the default error log folder (coscli_output) will be used.")
+	abortCmd.Flags().String("older-than", "", "Only abort uploads initiated before the specified age, e.g. 24h or 7d")
+	abortCmd.Flags().Bool("dry-run", false, "Only print the uploads to be aborted and the size of their parts")
+	abortCmd.Flags().StringSlice("exclude-upload-id", nil, "Upload IDs which should not be aborted, separated by commas")
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/tencentyun/cos-go-sdk-v5"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAbortCmd(t *testing.T) {
@@ -109,6 +110,61 @@ func TestAbortCmd(t *testing.T) {
 				e := cmd.Execute()
 				So(e, ShouldBeNil)
 			})
+			Convey("older than, exclude upload id and dry run", func() {
+				clearCmd()
+				cmd := rootCmd
+				oldTime := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+				newTime := time.Now().UTC().Format(time.RFC3339)
+				patches := ApplyFunc(util.GetUploadsListForLs, func(c *cos.Client, cosUrl util.StorageUrl, uploadIDMarker, keyMarker string, limit int, recursive bool) (err error, uploads []struct {
+					Key          string
+					UploadID     string `xml:"UploadId"`
+					StorageClass string
+					Initiator    *cos.Initiator
+					Owner        *cos.Owner
+					Initiated    string
+				}, isTruncated bool, nextUploadIDMarker, nextKeyMarker string) {
+					tmp := []struct {
+						Key          string
+						UploadID     string `xml:"UploadId"`
+						StorageClass string
+						Initiator    *cos.Initiator
+						Owner        *cos.Owner
+						Initiated    string
+					}{
+						{Key: "old", UploadID: "1", Initiated: oldTime},
+						{Key: "new", UploadID: "2", Initiated: newTime},
+						{Key: "excluded", UploadID: "3", Initiated: oldTime},
+						{Key: "invalid", UploadID: "4", Initiated: ""},
+					}
+					return nil, tmp, false, "", ""
+				})
+				defer patches.Reset()
+				patches.ApplyFunc(util.GetPartsListForLs, func(c *cos.Client, cosUrl util.StorageUrl, uploadId, partNumberMarker string, limit int) (err error, parts []cos.Object, isTruncated bool, nextPartNumberMarker string) {
+					return nil, []cos.Object{{PartNumber: 1, Size: 1024}, {PartNumber: 2, Size: 1024}}, false, ""
+				})
+				var aborted []string
+				var c *cos.ObjectService
+				patches.ApplyMethodFunc(reflect.TypeOf(c), "AbortMultipartUpload", func(ctx context.Context, name string, uploadID string, opt ...*cos.AbortMultipartUploadOptions) (*cos.Response, error) {
+					aborted = append(aborted, name)
+					return nil, nil
+				})
+
+				args := []string{"abort", fmt.Sprintf("cos://%s-%s", testBucket, appID), "-e", testEndpoint,
+					"--older-than", "24h", "--exclude-upload-id", "3", "--dry-run"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(aborted, ShouldBeEmpty)
+
+				clearCmd()
+				cmd = rootCmd
+				args = []string{"abort", fmt.Sprintf("cos://%s-%s", testBucket, appID), "-e", testEndpoint,
+					"--older-than", "24h", "--exclude-upload-id", "3"}
+				cmd.SetArgs(args)
+				e = cmd.Execute()
+				So(e, ShouldBeNil)
+				So(aborted, ShouldResemble, []string{"old"})
+			})
 		})
 		Convey("failed", func() {
 			Convey("not enough argument", func() {
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var appendCmd = &cobra.Command{
+	Use:   "append",
+	Short: "Append data to an appendable object",
+	Long: `Append data to an appendable object
+
+Data is read from the local file, or from stdin when no file is given, and
+appended at the current end of the object with the Append API. The object is
+created as an appendable object if it does not exist. When reading a stream,
+buffered data is appended every --flush-interval seconds.
+
+Format:
+  ./coscli append cos://<bucket-name>-<appid>/<object> [local_file] [flags]
+
+Example:
+  ./coscli append cos://examplebucket-1234567890/app.log ./app.log
+  ./coscli append cos://examplebucket-1234567890/app.log < ./app.log
+  tail -f ./app.log | ./coscli append cos://examplebucket-1234567890/app.log`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flushInterval, _ := cmd.Flags().GetInt("flush-interval")
+		metaString, _ := cmd.Flags().GetString("meta")
+
+		if flushInterval < 1 || flushInterval > 3600 {
+			return fmt.Errorf("--flush-interval must be between 1 and 3600 (inclusive)")
+		}
+		meta, err := util.MetaStringToHeader(metaString)
+		if err != nil {
+			return fmt.Errorf("Append invalid meta " + err.Error())
+		}
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return err
+		}
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+		if cosUrl.(*util.CosUrl).Object == "" {
+			return fmt.Errorf("object key can not be empty")
+		}
+
+		var r io.Reader = os.Stdin
+		if len(args) == 2 {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Meta: meta,
+			},
+			Config: &config,
+			Param:  &param,
+			Ctx:    commandContext(),
+		}
+
+		c, err := util.NewClient(&config, &param, cosUrl.(*util.CosUrl).Bucket)
+		if err != nil {
+			return err
+		}
+		return util.AppendObject(c, cosUrl, r, time.Duration(flushInterval)*time.Second, fo)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(appendCmd)
+
+	appendCmd.Flags().Int("flush-interval", 1, "Interval in seconds to append the data buffered from stdin")
+	appendCmd.Flags().String("meta", "",
+		"Set the meta information of the object when it is created, "+
+			"the format is header:value#header:value, the example is Cache-Control:no-cache#Content-Type:text/plain")
+}
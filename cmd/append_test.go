@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestAppendCmd(t *testing.T) {
+	fmt.Println("TestAppendCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	cosObject := fmt.Sprintf("cos://%s", testAlias)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	Convey("Test coscli append", t, func() {
+		Convey("success", func() {
+			Convey("append local file", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), fmt.Sprintf("%s/small-file/0", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("append again", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), fmt.Sprintf("%s/small-file/1", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("follow", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.FollowObject, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations) error {
+					return nil
+				})
+				defer patches.Reset()
+				args := []string{"cat", fmt.Sprintf("%s/append/log", cosObject), "--follow", "--tail", "1", "--lines"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("not appendable object", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cp", fmt.Sprintf("%s/small-file/0", testDir), fmt.Sprintf("%s/normal", cosObject)}
+				cmd.SetArgs(args)
+				cmd.Execute()
+				clearCmd()
+				cmd = rootCmd
+				args = []string{"append", fmt.Sprintf("%s/normal", cosObject), fmt.Sprintf("%s/small-file/1", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("empty key", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", cosObject, fmt.Sprintf("%s/small-file/0", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", testDir, fmt.Sprintf("%s/small-file/0", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("local file not exist", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), fmt.Sprintf("%s/not-exist", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("flush-interval over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), "--flush-interval", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid meta", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), "--meta", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), fmt.Sprintf("%s/small-file/0", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("AppendObject", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.AppendObject, func(c *cos.Client, cosUrl util.StorageUrl, r io.Reader, flushInterval time.Duration, fo *util.FileOperations) error {
+					return fmt.Errorf("test append error")
+				})
+				defer patches.Reset()
+				args := []string{"append", fmt.Sprintf("%s/append/log", cosObject), fmt.Sprintf("%s/small-file/0", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up a local directory with content-defined deduplication",
+	Long: `Back up a local directory with content-defined deduplication
+
+Files are split into content-defined chunks (512KB~4MB, 1MB on average).
+Each unique chunk is uploaded once to <repo>/chunks/ under its SHA-256, and
+a snapshot manifest is written to <repo>/snapshots/. Chunks known to exist in
+the repository are recorded in a local leveldb index, so unchanged data is
+neither uploaded nor checked again. The index is reset automatically after
+the repository is pruned.
+
+Format:
+  ./coscli backup <local-dir> cos://<bucket-name>/<repo> [flags]
+  ./coscli backup list cos://<bucket-name>/<repo>
+  ./coscli backup restore cos://<bucket-name>/<repo>/snapshots/<snapshot-id> <local-dir> [flags]
+  ./coscli backup prune cos://<bucket-name>/<repo> [flags]
+
+Example:
+  ./coscli backup ~/images cos://examplebucket/backup/images
+  ./coscli backup list cos://examplebucket/backup/images
+  ./coscli backup restore cos://examplebucket/backup/images/snapshots/latest ~/images-restore
+  ./coscli backup prune cos://examplebucket/backup/images --keep-last 7`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		routines, _ := cmd.Flags().GetInt("routines")
+		indexPath, _ := cmd.Flags().GetString("index-path")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+
+		fileUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return fmt.Errorf("format local path error,%v", err)
+		}
+		if !fileUrl.IsFileUrl() {
+			return fmt.Errorf("backup source must be a local path")
+		}
+		if _, err = os.Stat(fileUrl.ToString()); err != nil {
+			return err
+		}
+		cosUrl, err := formatComposeUrl(args[1])
+		if err != nil {
+			return err
+		}
+
+		_, filters := util.GetFilter(include, exclude)
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Filters:  filters,
+				Routines: routines,
+			},
+			Monitor:       &util.FileProcessMonitor{},
+			Config:        &config,
+			Param:         &param,
+			ErrOutput:     &util.ErrOutput{},
+			CpType:        util.CpTypeUpload,
+			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
+		}
+
+		c, err := util.NewClient(fo.Config, fo.Param, cosUrl.(*util.CosUrl).Bucket, fo)
+		if err != nil {
+			return err
+		}
+		_, err = util.Backup(c, fileUrl, cosUrl, fo, indexPath)
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+
+	backupCmd.Flags().Int("routines", 3, "Specifies the number of files backed up concurrently")
+	backupCmd.Flags().String("index-path", "", "Path of the local chunk index, default is ~/.coscli/backup-index/<repo-hash>")
+	backupCmd.Flags().String("include", "", "Include files that meet the specified criteria")
+	backupCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"coscli/util"
+
+	"github.com/spf13/cobra"
+)
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots in a backup repository",
+	Long: `List snapshots in a backup repository
+
+Format:
+  ./coscli backup list cos://<bucket-name>/<repo>
+
+Example:
+  ./coscli backup list cos://examplebucket/backup/images`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cosUrl, err := formatComposeUrl(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := util.NewClient(&config, &param, cosUrl.(*util.CosUrl).Bucket)
+		if err != nil {
+			return err
+		}
+		return util.ListBackupSnapshots(commandContext(), c, cosUrl)
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupListCmd)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var backupPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove old snapshots and unreferenced chunks from a backup repository",
+	Long: `Remove old snapshots and unreferenced chunks from a backup repository
+
+With --keep-last, only the newest N snapshots are kept. Chunks that are not
+referenced by any remaining snapshot are deleted, except chunks uploaded
+within the last hour, which may belong to a backup still in progress.
+Pruning invalidates the local chunk index of every host, which is rebuilt
+on the next backup.
+
+Format:
+  ./coscli backup prune cos://<bucket-name>/<repo> [flags]
+
+Example:
+  ./coscli backup prune cos://examplebucket/backup/images --keep-last 7
+  ./coscli backup prune cos://examplebucket/backup/images --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keepLast, _ := cmd.Flags().GetInt("keep-last")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if keepLast < 0 {
+			return fmt.Errorf("--keep-last can not be negative")
+		}
+
+		cosUrl, err := formatComposeUrl(args[0])
+		if err != nil {
+			return err
+		}
+		c, err := util.NewClient(&config, &param, cosUrl.(*util.CosUrl).Bucket)
+		if err != nil {
+			return err
+		}
+		return util.PruneBackup(commandContext(), c, cosUrl, keepLast, dryRun)
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupPruneCmd)
+
+	backupPruneCmd.Flags().Int("keep-last", 0, "Keep only the newest N snapshots, 0 keeps all snapshots")
+	backupPruneCmd.Flags().Bool("dry-run", false, "Only print what would be removed")
+}
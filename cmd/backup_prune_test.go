@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestBackupPruneCmd(t *testing.T) {
+	fmt.Println("TestBackupPruneCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	repo := fmt.Sprintf("cos://%s/backup/repo", testAlias)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	for i := 0; i < 2; i++ {
+		clearCmd()
+		cmd.SetArgs([]string{"backup", fmt.Sprintf("%s/small-file", testDir), repo, "--index-path", fmt.Sprintf("%s/backup-index", testDir)})
+		cmd.Execute()
+	}
+	Convey("Test coscli backup prune", t, func() {
+		Convey("success", func() {
+			Convey("dry run", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "prune", repo, "--keep-last", "1", "--dry-run"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("keep last", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "prune", repo, "--keep-last", "1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("negative keep last", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "prune", repo, "--keep-last", "-1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "prune", testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"backup", "prune", repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
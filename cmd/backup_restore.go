@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup snapshot to a local directory",
+	Long: `Restore a backup snapshot to a local directory
+
+Every chunk is verified against its SHA-256. Files are written to temporary
+files and renamed into place, and file modes and modification times are
+restored. Use "latest" as the snapshot id to restore the newest snapshot.
+
+Format:
+  ./coscli backup restore cos://<bucket-name>/<repo>/snapshots/<snapshot-id> <local-dir> [flags]
+
+Example:
+  ./coscli backup restore cos://examplebucket/backup/images/snapshots/20240101T000000Z-1a2b ~/images
+  ./coscli backup restore cos://examplebucket/backup/images/snapshots/latest ~/images`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		routines, _ := cmd.Flags().GetInt("routines")
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+
+		snapshotUrl, err := formatComposeUrl(args[0])
+		if err != nil {
+			return err
+		}
+		if _, _, err = util.ParseBackupSnapshotUrl(snapshotUrl); err != nil {
+			return err
+		}
+		fileUrl, err := util.FormatUrl(args[1])
+		if err != nil {
+			return fmt.Errorf("format local path error,%v", err)
+		}
+		if !fileUrl.IsFileUrl() {
+			return fmt.Errorf("restore destination must be a local path")
+		}
+
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Routines: routines,
+			},
+			Monitor:       &util.FileProcessMonitor{},
+			Config:        &config,
+			Param:         &param,
+			ErrOutput:     &util.ErrOutput{},
+			CpType:        util.CpTypeDownload,
+			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
+		}
+		c, err := util.NewClient(fo.Config, fo.Param, snapshotUrl.(*util.CosUrl).Bucket, fo)
+		if err != nil {
+			return err
+		}
+		return util.RestoreBackup(c, snapshotUrl, fileUrl, fo)
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupRestoreCmd.Flags().Int("routines", 3, "Specifies the number of files restored concurrently")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestBackupRestoreCmd(t *testing.T) {
+	fmt.Println("TestBackupRestoreCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	repo := fmt.Sprintf("cos://%s/backup/repo", testAlias)
+	latest := repo + "/snapshots/latest"
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	cmd.SetArgs([]string{"backup", fmt.Sprintf("%s/small-file", testDir), repo, "--index-path", fmt.Sprintf("%s/backup-index", testDir)})
+	cmd.Execute()
+	Convey("Test coscli backup restore", t, func() {
+		Convey("success", func() {
+			clearCmd()
+			cmd := rootCmd
+			args := []string{"backup", "restore", latest, fmt.Sprintf("%s/restore", testDir)}
+			cmd.SetArgs(args)
+			e := cmd.Execute()
+			So(e, ShouldBeNil)
+		})
+		Convey("fail", func() {
+			Convey("invalid snapshot path", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "restore", repo, fmt.Sprintf("%s/restore", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("snapshot not exist", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "restore", repo + "/snapshots/not-exist", fmt.Sprintf("%s/restore", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("destination not local", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "restore", latest, repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("routines over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", "restore", latest, fmt.Sprintf("%s/restore", testDir), "--routines", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"backup", "restore", latest, fmt.Sprintf("%s/restore", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
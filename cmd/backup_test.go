@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestBackupCmd(t *testing.T) {
+	fmt.Println("TestBackupCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	repo := fmt.Sprintf("cos://%s/backup/repo", testAlias)
+	indexPath := fmt.Sprintf("%s/backup-index", testDir)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	Convey("Test coscli backup", t, func() {
+		Convey("success", func() {
+			Convey("backup dir", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", fmt.Sprintf("%s/big-file", testDir), repo, "--index-path", indexPath}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("backup dir again", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", fmt.Sprintf("%s/big-file", testDir), repo, "--index-path", indexPath, "--routines", "1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("routines over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", testDir, repo, "--routines", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("source not local", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", repo, repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("source not exist", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", fmt.Sprintf("%s/not-exist", testDir), repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("repo not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"backup", testDir, testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"backup", testDir, repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("Backup", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.Backup, func(c *cos.Client, fileUrl util.StorageUrl, cosUrl util.StorageUrl, fo *util.FileOperations, indexPath string) (*util.BackupSnapshot, error) {
+					return nil, fmt.Errorf("test backup error")
+				})
+				defer patches.Reset()
+				args := []string{"backup", testDir, repo}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketCorsCmd = &cobra.Command{
+	Use:   "bucket-cors",
+	Short: "Modify bucket cors",
+	Long: `Modify bucket cors
+
+Format:
+	./coscli bucket-cors --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-cors --method put cos://examplebucket --cors "<CORSConfiguration><CORSRule><AllowedOrigin>https://www.example.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod><AllowedMethod>PUT</AllowedMethod><AllowedHeader>*</AllowedHeader><MaxAgeSeconds>600</MaxAgeSeconds></CORSRule></CORSConfiguration>"
+	./coscli bucket-cors --method put cos://examplebucket --cors '{"CORSRule":[{"AllowedOrigin":["https://www.example.com"],"AllowedMethod":["GET","PUT"],"AllowedHeader":["*"],"MaxAgeSeconds":600}]}'
+	./coscli bucket-cors --method put cos://examplebucket --cors file:///path/to/cors.json
+	./coscli bucket-cors --method get cos://examplebucket
+	./coscli bucket-cors --method delete cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		cors, _ := cmd.Flags().GetString("cors")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if cors == "" {
+				return fmt.Errorf("no cors provided")
+			}
+			err = util.PutBucketCors(c, cors)
+		} else if method == "get" {
+			err = util.GetBucketCors(c)
+		} else if method == "delete" {
+			err = util.DeleteBucketCors(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put', 'get', and 'delete'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketCorsCmd)
+	bucketCorsCmd.Flags().String("method", "", "put/get/delete")
+	bucketCorsCmd.Flags().String("cors", "", "Bucket cors configuration, supporting both XML and JSON syntax (JSON keys use the COS XML element names, e.g. CORSRule, AllowedOrigin, AllowedMethod); if the input contains the file:// prefix, it indicates reading the configuration from a file.")
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"reflect"
+	"testing"
+)
+
+func TestBucketCorsCmd(t *testing.T) {
+	fmt.Println("TestBucketCorsCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli bucket_cors", t, func() {
+		Convey("success", func() {
+			Convey("put", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutCORS", func(ctx context.Context, opt *cos.BucketPutCORSOptions) (*cos.Response, error) {
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--cors", "<CORSConfiguration><CORSRule><AllowedOrigin>https://www.example.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod><MaxAgeSeconds>600</MaxAgeSeconds></CORSRule></CORSConfiguration>"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("put json", func() {
+				clearCmd()
+				var c *cos.BucketService
+				var putOpt *cos.BucketPutCORSOptions
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutCORS", func(ctx context.Context, opt *cos.BucketPutCORSOptions) (*cos.Response, error) {
+					putOpt = opt
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--cors", `{"CORSRule":[{"AllowedOrigin":["https://www.example.com"],"AllowedMethod":["GET","PUT"],"AllowedHeader":["*"],"MaxAgeSeconds":600}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(len(putOpt.Rules), ShouldEqual, 1)
+				So(putOpt.Rules[0].AllowedMethods, ShouldResemble, []string{"GET", "PUT"})
+				So(putOpt.Rules[0].MaxAgeSeconds, ShouldEqual, 600)
+			})
+			Convey("get", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "GetCORS", func(ctx context.Context) (*cos.BucketGetCORSResult, *cos.Response, error) {
+					return &cos.BucketGetCORSResult{
+						Rules: []cos.BucketCORSRule{
+							{
+								AllowedOrigins: []string{"https://www.example.com"},
+								AllowedMethods: []string{"GET"},
+								MaxAgeSeconds:  600,
+							},
+						},
+					}, nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("delete", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "delete",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("clinet err", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test put client error")
+				})
+				defer patches.Reset()
+				args := []string{"bucket-cors", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("no cors provided", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("json without rule", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--cors", `{"Rules":[{"AllowedOrigins":["*"],"AllowedMethods":["GET"]}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid cors", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--cors", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("cos path error", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "get",
+					fmt.Sprintf("cos:/%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-cors", "--method", "add",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketLifecycleCmd = &cobra.Command{
+	Use:   "bucket-lifecycle",
+	Short: "Modify bucket lifecycle",
+	Long: `Modify bucket lifecycle
+
+Format:
+	./coscli bucket-lifecycle --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-lifecycle --method put cos://examplebucket --lifecycle "<LifecycleConfiguration><Rule><ID>rule1</ID><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Transition><Days>30</Days><StorageClass>ARCHIVE</StorageClass></Transition><Expiration><Days>365</Days></Expiration><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule></LifecycleConfiguration>"
+	./coscli bucket-lifecycle --method put cos://examplebucket --lifecycle '{"Rule":[{"ID":"rule1","Status":"Enabled","Filter":{"Prefix":"logs/"},"Expiration":{"Days":365}}]}'
+	./coscli bucket-lifecycle --method put cos://examplebucket --lifecycle file:///path/to/lifecycle.json
+	./coscli bucket-lifecycle --method get cos://examplebucket
+	./coscli bucket-lifecycle --method delete cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		lifecycle, _ := cmd.Flags().GetString("lifecycle")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if lifecycle == "" {
+				return fmt.Errorf("no lifecycle provided")
+			}
+			err = util.PutBucketLifecycle(c, lifecycle)
+		} else if method == "get" {
+			err = util.GetBucketLifecycle(c)
+		} else if method == "delete" {
+			err = util.DeleteBucketLifecycle(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put', 'get', and 'delete'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketLifecycleCmd)
+	bucketLifecycleCmd.Flags().String("method", "", "put/get/delete")
+	bucketLifecycleCmd.Flags().String("lifecycle", "", "Bucket lifecycle configuration, supporting both XML and JSON syntax (JSON keys use the COS XML element names, e.g. Rule, ID, Status); if the input contains the file:// prefix, it indicates reading the configuration from a file.")
+}
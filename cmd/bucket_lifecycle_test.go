@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"reflect"
+	"testing"
+)
+
+func TestBucketLifecycleCmd(t *testing.T) {
+	fmt.Println("TestBucketLifecycleCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli bucket_lifecycle", t, func() {
+		Convey("success", func() {
+			Convey("put", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutLifecycle", func(ctx context.Context, opt *cos.BucketPutLifecycleOptions) (*cos.Response, error) {
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--lifecycle", "<LifecycleConfiguration><Rule><ID>rule1</ID><Status>Enabled</Status><Filter><Prefix>logs/</Prefix></Filter><Transition><Days>30</Days><StorageClass>ARCHIVE</StorageClass></Transition><Expiration><Days>365</Days></Expiration></Rule></LifecycleConfiguration>"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("put json", func() {
+				clearCmd()
+				var c *cos.BucketService
+				var putOpt *cos.BucketPutLifecycleOptions
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutLifecycle", func(ctx context.Context, opt *cos.BucketPutLifecycleOptions) (*cos.Response, error) {
+					putOpt = opt
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--lifecycle", `{"Rule":[{"ID":"rule1","Status":"Enabled","Filter":{"Prefix":"logs/"},"Transition":[{"Days":30,"StorageClass":"ARCHIVE"}],"Expiration":{"Days":365}}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(len(putOpt.Rules), ShouldEqual, 1)
+				So(putOpt.Rules[0].ID, ShouldEqual, "rule1")
+				So(putOpt.Rules[0].Filter.Prefix, ShouldEqual, "logs/")
+				So(putOpt.Rules[0].Expiration.Days, ShouldEqual, 365)
+			})
+			Convey("get", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "GetLifecycle", func(ctx context.Context, opt ...*cos.BucketGetLifecycleOptions) (*cos.BucketGetLifecycleResult, *cos.Response, error) {
+					return &cos.BucketGetLifecycleResult{
+						Rules: []cos.BucketLifecycleRule{
+							{
+								ID:     "rule1",
+								Status: "Enabled",
+								Filter: &cos.BucketLifecycleFilter{Prefix: "logs/"},
+								Transition: []cos.BucketLifecycleTransition{
+									{Days: 30, StorageClass: "ARCHIVE"},
+								},
+								Expiration: &cos.BucketLifecycleExpiration{Days: 365},
+								AbortIncompleteMultipartUpload: &cos.BucketLifecycleAbortIncompleteMultipartUpload{
+									DaysAfterInitiation: 7,
+								},
+							},
+						},
+					}, nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("delete", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "delete",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("clinet err", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test put client error")
+				})
+				defer patches.Reset()
+				args := []string{"bucket-lifecycle", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("no lifecycle provided", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("json without rule", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--lifecycle", `{"Rules":[{"ID":"rule1","Status":"Enabled"}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid lifecycle", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--lifecycle", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("cos path error", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "get",
+					fmt.Sprintf("cos:/%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-lifecycle", "--method", "add",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
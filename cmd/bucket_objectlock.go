@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketObjectLockCmd = &cobra.Command{
+	Use:   "bucket-objectlock",
+	Short: "Modify bucket object lock",
+	Long: `Modify bucket object lock
+
+Format:
+	./coscli bucket-objectlock --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-objectlock --method put cos://examplebucket --days 30
+	./coscli bucket-objectlock --method get cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		days, _ := cmd.Flags().GetInt("days")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if days < 0 || days > 36500 {
+				return fmt.Errorf("Flag --days should in range 0~36500")
+			}
+			err = util.PutBucketObjectLock(c, days)
+		} else if method == "get" {
+			err = util.GetBucketObjectLock(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put' and 'get'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketObjectLockCmd)
+	bucketObjectLockCmd.Flags().String("method", "", "put/get")
+	bucketObjectLockCmd.Flags().Int("days", 0, "Default retention days of the object lock. Object lock can not be disabled once enabled.")
+}
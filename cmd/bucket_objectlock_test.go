@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"reflect"
+	"testing"
+)
+
+func TestBucketObjectLockCmd(t *testing.T) {
+	fmt.Println("TestBucketObjectLockCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli bucket_objectlock", t, func() {
+		Convey("success", func() {
+			Convey("put", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutObjectLockConfiguration", func(ctx context.Context, opt *cos.BucketPutObjectLockOptions) (*cos.Response, error) {
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-objectlock", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--days", "30"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("get", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "GetObjectLockConfiguration", func(ctx context.Context) (*cos.BucketGetObjectLockResult, *cos.Response, error) {
+					return &cos.BucketGetObjectLockResult{
+						ObjectLockEnabled: "Enabled",
+						Rule:              &cos.ObjectLockRule{Days: 30},
+					}, nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-objectlock", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("clinet err", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test put client error")
+				})
+				defer patches.Reset()
+				args := []string{"bucket-objectlock", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("days over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-objectlock", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--days", "-1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("cos path error", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-objectlock", "--method", "get",
+					fmt.Sprintf("cos:/%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-objectlock", "--method", "delete",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketRefererCmd = &cobra.Command{
+	Use:   "bucket-referer",
+	Short: "Modify bucket referer",
+	Long: `Modify bucket referer
+
+Format:
+	./coscli bucket-referer --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-referer --method put cos://examplebucket --referer "<RefererConfiguration><Status>Enabled</Status><RefererType>White-List</RefererType><DomainList><Domain>*.example.com</Domain></DomainList><EmptyReferConfiguration>Allow</EmptyReferConfiguration></RefererConfiguration>"
+	./coscli bucket-referer --method put cos://examplebucket --referer '{"Status":"Enabled","RefererType":"White-List","DomainList":{"Domain":["*.example.com"]},"EmptyReferConfiguration":"Allow"}'
+	./coscli bucket-referer --method put cos://examplebucket --referer file:///path/to/referer.json
+	./coscli bucket-referer --method get cos://examplebucket
+	./coscli bucket-referer --method delete cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		referer, _ := cmd.Flags().GetString("referer")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if referer == "" {
+				return fmt.Errorf("no referer provided")
+			}
+			err = util.PutBucketReferer(c, referer)
+		} else if method == "get" {
+			err = util.GetBucketReferer(c)
+		} else if method == "delete" {
+			err = util.DeleteBucketReferer(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put', 'get', and 'delete'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketRefererCmd)
+	bucketRefererCmd.Flags().String("method", "", "put/get/delete")
+	bucketRefererCmd.Flags().String("referer", "", "Bucket referer configuration, supporting both XML and JSON syntax (JSON keys use the COS XML element names, e.g. Status, RefererType, DomainList.Domain); if the input contains the file:// prefix, it indicates reading the configuration from a file.")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"reflect"
+	"testing"
+)
+
+func TestBucketRefererCmd(t *testing.T) {
+	fmt.Println("TestBucketRefererCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli bucket_referer", t, func() {
+		Convey("success", func() {
+			Convey("put", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutReferer", func(ctx context.Context, opt *cos.BucketPutRefererOptions) (*cos.Response, error) {
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--referer", "<RefererConfiguration><Status>Enabled</Status><RefererType>White-List</RefererType><DomainList><Domain>*.example.com</Domain></DomainList></RefererConfiguration>"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("put json", func() {
+				clearCmd()
+				var c *cos.BucketService
+				var putOpt *cos.BucketPutRefererOptions
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutReferer", func(ctx context.Context, opt *cos.BucketPutRefererOptions) (*cos.Response, error) {
+					putOpt = opt
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--referer", `{"Status":"Enabled","RefererType":"White-List","DomainList":{"Domain":["*.example.com"]},"EmptyReferConfiguration":"Allow"}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(putOpt.Status, ShouldEqual, "Enabled")
+				So(putOpt.DomainList, ShouldResemble, []string{"*.example.com"})
+			})
+			Convey("get", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "GetReferer", func(ctx context.Context) (*cos.BucketGetRefererResult, *cos.Response, error) {
+					return &cos.BucketGetRefererResult{
+						Status:      "Enabled",
+						RefererType: "White-List",
+						DomainList:  []string{"*.example.com"},
+					}, nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("delete", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "delete",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("clinet err", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test put client error")
+				})
+				defer patches.Reset()
+				args := []string{"bucket-referer", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("no referer provided", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("json without rule", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--referer", `{"Status":"Enabled","RefererType":"White-List","DomainList":["*.example.com"]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid referer", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--referer", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("cos path error", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "get",
+					fmt.Sprintf("cos:/%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-referer", "--method", "add",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
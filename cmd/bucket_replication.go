@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketReplicationCmd = &cobra.Command{
+	Use:   "bucket-replication",
+	Short: "Modify bucket cross-region replication",
+	Long: `Modify bucket cross-region replication
+
+Format:
+	./coscli bucket-replication --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-replication --method put cos://examplebucket --replication "<ReplicationConfiguration><Role>qcs::cam::uin/100000000001:uin/100000000001</Role><Rule><ID>rule1</ID><Status>Enabled</Status><Prefix>data/</Prefix><Destination><Bucket>qcs::cos:ap-shanghai::destbucket-1250000000</Bucket><StorageClass>STANDARD</StorageClass></Destination></Rule></ReplicationConfiguration>"
+	./coscli bucket-replication --method put cos://examplebucket --replication '{"Role":"qcs::cam::uin/100000000001:uin/100000000001","Rule":[{"ID":"rule1","Status":"Enabled","Prefix":"data/","Destination":{"Bucket":"qcs::cos:ap-shanghai::destbucket-1250000000"}}]}'
+	./coscli bucket-replication --method put cos://examplebucket --replication file:///path/to/replication.json
+	./coscli bucket-replication --method get cos://examplebucket
+	./coscli bucket-replication --method delete cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		replication, _ := cmd.Flags().GetString("replication")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if replication == "" {
+				return fmt.Errorf("no replication provided")
+			}
+			err = util.PutBucketReplication(c, replication)
+		} else if method == "get" {
+			err = util.GetBucketReplication(c)
+		} else if method == "delete" {
+			err = util.DeleteBucketReplication(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put', 'get', and 'delete'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketReplicationCmd)
+	bucketReplicationCmd.Flags().String("method", "", "put/get/delete")
+	bucketReplicationCmd.Flags().String("replication", "", "Bucket cross-region replication configuration, supporting both XML and JSON syntax (JSON keys use the COS XML element names, e.g. Role, Rule, Destination.Bucket); if the input contains the file:// prefix, it indicates reading the configuration from a file.")
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+	"reflect"
+	"testing"
+)
+
+func TestBucketReplicationCmd(t *testing.T) {
+	fmt.Println("TestBucketReplicationCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli bucket_replication", t, func() {
+		Convey("success", func() {
+			Convey("put", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutBucketReplication", func(ctx context.Context, opt *cos.PutBucketReplicationOptions) (*cos.Response, error) {
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--replication", "<ReplicationConfiguration><Role>qcs::cam::uin/100000000001:uin/100000000001</Role><Rule><ID>rule1</ID><Status>Enabled</Status><Prefix>data/</Prefix><Destination><Bucket>qcs::cos:ap-shanghai::destbucket-1250000000</Bucket></Destination></Rule></ReplicationConfiguration>"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("put json", func() {
+				clearCmd()
+				var c *cos.BucketService
+				var putOpt *cos.PutBucketReplicationOptions
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "PutBucketReplication", func(ctx context.Context, opt *cos.PutBucketReplicationOptions) (*cos.Response, error) {
+					putOpt = opt
+					return nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--replication", `{"Role":"qcs::cam::uin/100000000001:uin/100000000001","Rule":[{"ID":"rule1","Status":"Enabled","Prefix":"data/","Destination":{"Bucket":"qcs::cos:ap-shanghai::destbucket-1250000000"}}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(len(putOpt.Rule), ShouldEqual, 1)
+				So(putOpt.Rule[0].ID, ShouldEqual, "rule1")
+				So(putOpt.Rule[0].Destination.Bucket, ShouldEqual, "qcs::cos:ap-shanghai::destbucket-1250000000")
+			})
+			Convey("get", func() {
+				clearCmd()
+				var c *cos.BucketService
+				patches := ApplyMethodFunc(reflect.TypeOf(c), "GetBucketReplication", func(ctx context.Context) (*cos.GetBucketReplicationResult, *cos.Response, error) {
+					return &cos.GetBucketReplicationResult{
+						Role: "qcs::cam::uin/100000000001:uin/100000000001",
+						Rule: []cos.BucketReplicationRule{
+							{
+								ID:          "rule1",
+								Status:      "Enabled",
+								Prefix:      "data/",
+								Destination: &cos.ReplicationDestination{Bucket: "qcs::cos:ap-shanghai::destbucket-1250000000"},
+							},
+						},
+					}, nil, nil
+				})
+				defer patches.Reset()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("delete", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "delete",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("clinet err", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test put client error")
+				})
+				defer patches.Reset()
+				args := []string{"bucket-replication", "--method", "get",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("no replication provided", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("json without rule", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--replication", `{"Role":"qcs::cam::uin/100000000001:uin/100000000001","Rules":[{"ID":"rule1","Status":"Enabled"}]}`}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid replication", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "put",
+					fmt.Sprintf("cos://%s", testAlias), "--replication", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("cos path error", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "get",
+					fmt.Sprintf("cos:/%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"bucket-replication", "--method", "add",
+					fmt.Sprintf("cos://%s", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+var bucketWebsiteCmd = &cobra.Command{
+	Use:   "bucket-website",
+	Short: "Modify bucket static website",
+	Long: `Modify bucket static website
+
+Format:
+	./coscli bucket-website --method [method] cos://<bucket-name>
+
+Example:
+	./coscli bucket-website --method put cos://examplebucket --website "<WebsiteConfiguration><IndexDocument><Suffix>index.html</Suffix></IndexDocument><ErrorDocument><Key>404.html</Key></ErrorDocument></WebsiteConfiguration>"
+	./coscli bucket-website --method put cos://examplebucket --website '{"IndexDocument":{"Suffix":"index.html"},"ErrorDocument":{"Key":"404.html"}}'
+	./coscli bucket-website --method put cos://examplebucket --website file:///path/to/website.xml
+	./coscli bucket-website --method get cos://examplebucket
+	./coscli bucket-website --method delete cos://examplebucket`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, _ := cmd.Flags().GetString("method")
+		website, _ := cmd.Flags().GetString("website")
+
+		var err error
+		cosPath := args[0]
+		if !util.IsCosPath(cosPath) {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName, _ := util.ParsePath(cosPath)
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if method == "put" {
+			if website == "" {
+				return fmt.Errorf("no website provided")
+			}
+			err = util.PutBucketWebsite(c, website)
+		} else if method == "get" {
+			err = util.GetBucketWebsite(c)
+		} else if method == "delete" {
+			err = util.DeleteBucketWebsite(c)
+		} else {
+			err = fmt.Errorf("method '%s' is not supported, valid methods are 'put', 'get', and 'delete'", method)
+		}
+
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketWebsiteCmd)
+	bucketWebsiteCmd.Flags().String("method", "", "put/get/delete")
+	bucketWebsiteCmd.Flags().String("website", "", "Bucket static website configuration, supporting both XML and JSON syntax (JSON keys use the COS XML element names, e.g. IndexDocument.Suffix, ErrorDocument.Key); if the input contains the file:// prefix, it indicates reading the configuration from a file.")
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"coscli/util"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tencentyun/cos-go-sdk-v5"
 )
 
 var catCmd = &cobra.Command{
@@ -13,37 +15,162 @@ var catCmd = &cobra.Command{
 	Long: `Cat object info
 
 Format:
-  ./coscli cat cos://<bucket-name>-<appid>/<object>
+  ./coscli cat cos://<bucket-name>-<appid>/<object> [cos://<bucket-name>-<appid>/<object> ...] [flags]
 
 Example:
-  ./coscli cat cos://examplebucket-1234567890/test.txt`,
+  ./coscli cat cos://examplebucket-1234567890/test.txt
+  ./coscli cat cos://examplebucket-1234567890/test.txt --range 100-199
+  ./coscli cat cos://examplebucket-1234567890/app.log --tail 20 --lines
+  ./coscli cat cos://examplebucket-1234567890/app.log --tail 20 --lines --follow
+  ./coscli cat cos://examplebucket-1234567890/logs/ -r --include "\.log$"
+  ./coscli cat cos://examplebucket-1234567890/secret.txt --client-encrypt ./master.key
+  ./coscli cat cos://examplebucket-1234567890/app.log.gz --decompress`,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
 			return err
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cosUrl, err := util.FormatUrl(args[0])
-		if err != nil {
-			return err
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		byteRange, _ := cmd.Flags().GetString("range")
+		head, _ := cmd.Flags().GetInt64("head")
+		tail, _ := cmd.Flags().GetInt64("tail")
+		lines, _ := cmd.Flags().GetBool("lines")
+		versionId, _ := cmd.Flags().GetString("version-id")
+		sseCustomerAlgo, _ := cmd.Flags().GetString("sse-customer-algo")
+		sseCustomerKey, _ := cmd.Flags().GetString("sse-customer-key")
+		sseCustomerKeyMD5, _ := cmd.Flags().GetString("sse-customer-key-md5")
+		clientEncrypt, _ := cmd.Flags().GetString("client-encrypt")
+		decompress, _ := cmd.Flags().GetBool("decompress")
+		follow, _ := cmd.Flags().GetBool("follow")
+
+		partial := 0
+		for _, set := range []bool{byteRange != "", cmd.Flags().Changed("head"), cmd.Flags().Changed("tail")} {
+			if set {
+				partial++
+			}
+		}
+		if partial > 1 {
+			return fmt.Errorf("--range, --head and --tail can not be used together")
+		}
+		if head < 0 || tail < 0 || (cmd.Flags().Changed("head") && head == 0) || (cmd.Flags().Changed("tail") && tail == 0) {
+			return fmt.Errorf("--head and --tail must be greater than 0")
+		}
+		if lines && head == 0 && tail == 0 {
+			return fmt.Errorf("--lines only work with --head or --tail")
 		}
-		if !cosUrl.IsCosUrl() {
-			return fmt.Errorf("cospath needs to contain cos://")
+		if decompress && (byteRange != "" || tail > 0) {
+			return fmt.Errorf("--range and --tail can not be used with --decompress")
+		}
+		if versionId != "" && recursive {
+			return fmt.Errorf("--version-id can not be used with --recursive")
 		}
 
-		// 实例化cos client
-		bucketName := cosUrl.(*util.CosUrl).Bucket
-		c, err := util.NewClient(&config, &param, bucketName)
-		if err != nil {
-			return err
+		if follow {
+			if len(args) > 1 || recursive {
+				return fmt.Errorf("--follow only work with a single object")
+			}
+			if byteRange != "" || head > 0 || versionId != "" || decompress || clientEncrypt != "" {
+				return fmt.Errorf("--follow can not be used with --range, --head, --version-id, --decompress or --client-encrypt")
+			}
+		}
+
+		_, filters := util.GetFilter(include, exclude)
+		if !recursive && len(filters) > 0 {
+			return fmt.Errorf("--include or --exclude only work with --recursive")
+		}
+
+		var cosUrls []util.StorageUrl
+		for _, arg := range args {
+			cosUrl, err := util.FormatUrl(arg)
+			if err != nil {
+				return err
+			}
+			if !cosUrl.IsCosUrl() {
+				return fmt.Errorf("cospath needs to contain cos://")
+			}
+			if !recursive && (cosUrl.(*util.CosUrl).Object == "" || strings.HasSuffix(cosUrl.(*util.CosUrl).Object, "/")) {
+				return fmt.Errorf("%s is not an object, use --recursive to cat objects under a prefix", arg)
+			}
+			cosUrls = append(cosUrls, cosUrl)
+		}
+
+		var masterKey []byte
+		if clientEncrypt != "" {
+			var err error
+			masterKey, err = util.LoadMasterKey(clientEncrypt)
+			if err != nil {
+				return err
+			}
+		}
+
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Recursive:         recursive,
+				Filters:           filters,
+				Range:             byteRange,
+				Head:              head,
+				Tail:              tail,
+				Lines:             lines,
+				VersionId:         versionId,
+				SSECustomerAlgo:   sseCustomerAlgo,
+				SSECustomerKey:    sseCustomerKey,
+				SSECustomerKeyMD5: sseCustomerKeyMD5,
+				ClientEncryptKey:  masterKey,
+				Decompress:        decompress,
+			},
+			Config: &config,
+			Param:  &param,
+			Ctx:    commandContext(),
 		}
 
-		err = util.CatObject(c, cosUrl)
-		return err
+		if follow {
+			c, err := util.NewClient(&config, &param, cosUrls[0].(*util.CosUrl).Bucket)
+			if err != nil {
+				return err
+			}
+			return util.FollowObject(c, cosUrls[0], fo)
+		}
+
+		// 按参数顺序输出，同一存储桶复用cos client
+		clients := make(map[string]*cos.Client)
+		for _, cosUrl := range cosUrls {
+			bucketName := cosUrl.(*util.CosUrl).Bucket
+			c, ok := clients[bucketName]
+			if !ok {
+				var err error
+				c, err = util.NewClient(&config, &param, bucketName)
+				if err != nil {
+					return err
+				}
+				clients[bucketName] = c
+			}
+			if err := util.CatObjects(c, cosUrl, fo); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(catCmd)
+
+	catCmd.Flags().BoolP("recursive", "r", false, "Cat all objects under the prefix in key order")
+	catCmd.Flags().String("include", "", "Include objects that meet the specified criteria")
+	catCmd.Flags().String("exclude", "", "Exclude objects that meet the specified criteria")
+	catCmd.Flags().String("range", "", "Byte range to output, the format is start-end, start- or -n (the last n bytes)")
+	catCmd.Flags().Int64("head", 0, "Output the first N bytes, or the first N lines with --lines")
+	catCmd.Flags().Int64("tail", 0, "Output the last N bytes, or the last N lines with --lines")
+	catCmd.Flags().Bool("lines", false, "Count --head and --tail in lines instead of bytes")
+	catCmd.Flags().BoolP("follow", "f", false, "Keep polling the appendable object and output appended data as it grows, like tail -f. Press Ctrl-C to stop")
+	catCmd.Flags().String("version-id", "", "Cat a specified version of the object, only available if bucket versioning is enabled")
+	catCmd.Flags().String("sse-customer-algo", "", "The encryption algorithm used when the object was uploaded with SSE-C, optional values: AES256 and SM4.")
+	catCmd.Flags().String("sse-customer-key", "", "The user-provided key used when the object was uploaded with SSE-C, a 32-byte string.")
+	catCmd.Flags().String("sse-customer-key-md5", "", "The MD5 value of the user-provided key")
+	catCmd.Flags().String("client-encrypt", "", "Path of the master key file used to decrypt client-side encrypted objects")
+	catCmd.Flags().Bool("decompress", false, "Decompress gzip or zstd objects according to their Content-Encoding")
 }
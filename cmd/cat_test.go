@@ -80,14 +80,117 @@ func TestCatCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
-			Convey("CatObject", func() {
-				patches := ApplyFunc(util.CatObject, func(c *cos.Client, cosUrl util.StorageUrl) error {
-					return fmt.Errorf("test CatObject error")
+			Convey("client-encrypt密钥文件不存在", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--client-encrypt", "not-exist.key"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("range与head同时使用", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--range", "0-9", "--head", "10"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("head为0", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--head", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("lines未指定head或tail", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--lines"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("tail用于解压", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "compress"), "--tail", "10", "--decompress"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("range格式非法", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--range", "10-1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("前缀未指定recursive", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("version-id与recursive同时使用", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/", testAlias), "-r", "--version-id", "123"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("follow多个对象", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/", testAlias), "-r", "--follow"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("follow与range同时使用", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--follow", "--range", "0-9"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("FollowObject", func() {
+				patches := ApplyFunc(util.FollowObject, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations) error {
+					return fmt.Errorf("test FollowObject error")
 				})
 				defer patches.Reset()
 				clearCmd()
 				cmd := rootCmd
-				args := []string{"cat", fmt.Sprintf("cos://%s", testAlias)}
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--follow"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("CatObjects", func() {
+				patches := ApplyFunc(util.CatObjects, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations) error {
+					return fmt.Errorf("test CatObjects error")
+				})
+				defer patches.Reset()
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small")}
 				cmd.SetArgs(args)
 				e := cmd.Execute()
 				fmt.Printf(" : %v", e)
@@ -102,6 +205,84 @@ func TestCatCmd(t *testing.T) {
 			e := cmd.Execute()
 			So(e, ShouldBeNil)
 		})
+		Convey("部分读取", func() {
+			Convey("range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--range", "10-19"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("head", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--head", "2", "--lines"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("tail", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), "--tail", "100"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("多个对象", func() {
+			Convey("多个参数", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/%s", testAlias, "single-small"), fmt.Sprintf("cos://%s/%s", testAlias, "single-small")}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("前缀", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"cat", fmt.Sprintf("cos://%s/", testAlias), "-r", "--include", "small"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("客户端加密", func() {
+			clearCmd()
+			cmd := rootCmd
+			keyFile := fmt.Sprintf("%s/master.key", testDir)
+			genMasterKey(keyFile)
+			localFileName := fmt.Sprintf("%s/small-file/1", testDir)
+			cosFileName := fmt.Sprintf("cos://%s/%s", testAlias, "client-encrypt")
+			args := []string{"cp", localFileName, cosFileName, "--client-encrypt", keyFile}
+			cmd.SetArgs(args)
+			e := cmd.Execute()
+			So(e, ShouldBeNil)
+			clearCmd()
+			cmd = rootCmd
+			args = []string{"cat", cosFileName, "--client-encrypt", keyFile}
+			cmd.SetArgs(args)
+			e = cmd.Execute()
+			So(e, ShouldBeNil)
+		})
+		Convey("解压", func() {
+			clearCmd()
+			cmd := rootCmd
+			localFileName := fmt.Sprintf("%s/small-file/2", testDir)
+			cosFileName := fmt.Sprintf("cos://%s/%s", testAlias, "compress")
+			args := []string{"cp", localFileName, cosFileName, "--compress", "gzip"}
+			cmd.SetArgs(args)
+			e := cmd.Execute()
+			So(e, ShouldBeNil)
+			clearCmd()
+			cmd = rootCmd
+			args = []string{"cat", cosFileName, "--decompress"}
+			cmd.SetArgs(args)
+			e = cmd.Execute()
+			So(e, ShouldBeNil)
+		})
 		Convey("retry", func() {
 			Convey("retry-2xx", func() {
 				Convey("retry-2xx-CloseAutoSwitchHost", func() {
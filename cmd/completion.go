@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// 参数可以为本地路径或cos路径的命令
+var completionLocalPathCommands = []*cobra.Command{cpCmd, syncCmd, hashCmd}
+
+// 参数只能为cos路径的命令
+var completionCosPathCommands = []*cobra.Command{lsCmd, duCmd, lsduCmd, rmCmd, restoreCmd, catCmd, lspartsCmd,
+	abortCmd, transitionCmd, replicationStatusCmd, signurlCmd, symlinkCmd, objectAclCmd, objectTaggingCmd, inventoryAnalyzeCmd, serveCmd, presignPostCmd, composeCmd}
+
+// 参数只能为存储桶的命令
+var completionBucketCommands = []*cobra.Command{mbCmd, rbCmd, bucketAclCmd, bucketCorsCmd, bucketEncryptionCmd,
+	bucketLifecycleCmd, bucketObjectLockCmd, bucketPolicyCmd, bucketRefererCmd, bucketReplicationCmd,
+	bucketTaggingCmd, bucketVersioningCmd, bucketWebsiteCmd, inventoryCmd}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion",
+	Short: "Generate the autocompletion script for the specified shell",
+	Long: `Generate the autocompletion script for the specified shell
+
+Besides commands and flags, cos paths are completed dynamically: "cos://",
+bucket names and aliases from the config file, then object prefixes listed
+from the bucket.
+
+Format:
+  ./coscli completion [bash|zsh|fish|powershell]
+
+Example:
+  source <(./coscli completion bash)
+  ./coscli completion zsh > "${fpath[1]}/_coscli"
+  ./coscli completion fish > ~/.config/fish/completions/coscli.fish`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletion(out)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletion(out)
+		}
+		return fmt.Errorf("shell '%s' is not supported, valid shells are 'bash', 'zsh', 'fish' and 'powershell'", args[0])
+	},
+}
+
+// completeCosPathArgs 补全cos路径参数
+func completeCosPathArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.HasPrefix(toComplete, util.SchemePrefix) {
+		return completeCosPath(toComplete), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	if strings.HasPrefix(util.SchemePrefix, toComplete) {
+		return []string{util.SchemePrefix}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLocalPathArgs 补全本地路径或cos路径参数
+func completeLocalPathArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.HasPrefix(toComplete, util.SchemePrefix) {
+		return completeCosPath(toComplete), cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	if strings.HasPrefix(util.SchemePrefix, toComplete) {
+		// 同时给出cos://及本地文件
+		candidates := append(completeLocalPath(toComplete), util.SchemePrefix)
+		return candidates, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+// completeBucketArgs 补全存储桶参数
+func completeBucketArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !strings.HasPrefix(toComplete, util.SchemePrefix) && strings.HasPrefix(util.SchemePrefix, toComplete) {
+		return []string{util.SchemePrefix}, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var candidates []string
+	for _, name := range completeBucketNames(strings.TrimPrefix(toComplete, util.SchemePrefix)) {
+		candidates = append(candidates, util.SchemePrefix+name)
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBucketNames 补全配置文件中的存储桶名称及别名
+func completeBucketNames(prefix string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, b := range config.Buckets {
+		for _, name := range []string{b.Alias, b.Name} {
+			if name != "" && !seen[name] && strings.HasPrefix(name, prefix) {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeCosPath 补全存储桶及对象路径
+func completeCosPath(cosPath string) []string {
+	rest := strings.TrimPrefix(cosPath, util.SchemePrefix)
+	var candidates []string
+
+	index := strings.Index(rest, util.CosSeparator)
+	if index < 0 {
+		for _, name := range completeBucketNames(rest) {
+			candidates = append(candidates, util.SchemePrefix+name+util.CosSeparator)
+		}
+		return candidates
+	}
+
+	bucketName := rest[:index]
+	prefix := rest[index+1:]
+	c, err := util.NewClient(&config, &param, bucketName)
+	if err != nil {
+		return nil
+	}
+	keys, err := util.ListCompletionKeys(c, prefix, 1000)
+	if err != nil {
+		return nil
+	}
+	for _, key := range keys {
+		candidates = append(candidates, util.SchemePrefix+bucketName+util.CosSeparator+key)
+	}
+	return candidates
+}
+
+func completeLocalPath(word string) []string {
+	matches, _ := filepath.Glob(word + "*")
+	for i, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			matches[i] = match + string(filepath.Separator)
+		}
+	}
+	return matches
+}
+
+// isCompletionRequest 是否为补全相关命令，此类命令不应触发交互式初始化配置
+func isCompletionRequest(firstArg string) bool {
+	return firstArg == cobra.ShellCompRequestCmd || firstArg == cobra.ShellCompNoDescRequestCmd || firstArg == "completion"
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	for _, c := range completionLocalPathCommands {
+		c.ValidArgsFunction = completeLocalPathArgs
+	}
+	for _, c := range completionCosPathCommands {
+		c.ValidArgsFunction = completeCosPathArgs
+	}
+	for _, c := range completionBucketCommands {
+		c.ValidArgsFunction = completeBucketArgs
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestCompletionCmd(t *testing.T) {
+	fmt.Println("TestCompletionCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	defer cmd.SetOut(nil)
+
+	Convey("test coscli completion", t, func() {
+		Convey("success", func() {
+			for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+				Convey(shell, func() {
+					clearCmd()
+					cmd := rootCmd
+					var out bytes.Buffer
+					cmd.SetOut(&out)
+					args := []string{"completion", shell}
+					cmd.SetArgs(args)
+					e := cmd.Execute()
+					So(e, ShouldBeNil)
+					So(out.String(), ShouldContainSubstring, "coscli")
+				})
+			}
+			Convey("complete scheme", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"__complete", "ls", "co"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, "cos://\n")
+			})
+			Convey("complete bucket alias", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"__complete", "rm", "cos://" + testAlias[:4]}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s/\n", testAlias))
+			})
+			Convey("complete bucket only", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"__complete", "bucket-acl", "cos://" + testAlias[:4]}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s\n", testAlias))
+			})
+			Convey("complete object prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.ListCompletionKeys, func(c *cos.Client, prefix string, limit int) ([]string, error) {
+					return []string{prefix + "dir/", prefix + "file.txt"}, nil
+				})
+				defer patches.Reset()
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"__complete", "cp", fmt.Sprintf("cos://%s/a", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s/adir/\n", testAlias))
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s/afile.txt\n", testAlias))
+			})
+		})
+		Convey("fail", func() {
+			Convey("not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"completion"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid shell", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"completion", "csh"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
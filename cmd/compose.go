@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var composeCmd = &cobra.Command{
+	Use:   "compose",
+	Short: "Concatenate objects into one object on the server side",
+	Long: `Concatenate objects into one object on the server side
+
+Sources are concatenated in the given order, or in key order when --prefix is
+used. Parts of at least 1MB are copied with UploadPartCopy, smaller parts are
+downloaded and uploaded again. The CRC64 of the result is verified against
+the CRC64 combined from the sources.
+
+Format:
+  ./coscli compose cos://<bucket-name>/<dest-key> cos://<bucket-name>/<src-key>... [flags]
+  ./coscli compose cos://<bucket-name>/<dest-key> --prefix cos://<bucket-name>/<prefix> [flags]
+
+Example:
+  ./coscli compose cos://examplebucket/out.log cos://examplebucket/part1 cos://examplebucket/part2
+  ./coscli compose cos://examplebucket/out.log --prefix cos://examplebucket/logs/20240101/ --include ".*\.log$"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prefix, _ := cmd.Flags().GetString("prefix")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		routines, _ := cmd.Flags().GetInt("routines")
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+		if prefix == "" && len(args) < 2 {
+			return fmt.Errorf("at least one source object or --prefix is required")
+		}
+		if prefix != "" && len(args) > 1 {
+			return fmt.Errorf("source objects and --prefix can not be used together")
+		}
+		if storageClass != "" && !isValidStorageClass(storageClass) {
+			return fmt.Errorf("invalid storage class: '%s'", storageClass)
+		}
+
+		destUrl, err := formatComposeUrl(args[0])
+		if err != nil {
+			return err
+		}
+		if destUrl.(*util.CosUrl).Object == "" {
+			return fmt.Errorf("destination object key can not be empty")
+		}
+
+		_, filters := util.GetFilter(include, exclude)
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Filters:      filters,
+				Routines:     routines,
+				StorageClass: storageClass,
+			},
+			Config: &config,
+			Param:  &param,
+			Ctx:    commandContext(),
+		}
+
+		c, err := util.NewClient(&config, &param, destUrl.(*util.CosUrl).Bucket)
+		if err != nil {
+			return err
+		}
+
+		var srcUrls []util.StorageUrl
+		if prefix != "" {
+			prefixUrl, err := formatComposeUrl(prefix)
+			if err != nil {
+				return err
+			}
+			prefixClient, err := util.NewClient(&config, &param, prefixUrl.(*util.CosUrl).Bucket)
+			if err != nil {
+				return err
+			}
+			// 获取桶类型
+			bucketType, err := util.GetBucketType(prefixClient, &param, &config, prefixUrl.(*util.CosUrl).Bucket)
+			if err != nil {
+				return err
+			}
+			urls, err := util.ListComposeSources(prefixClient, prefixUrl, bucketType, fo)
+			if err != nil {
+				return err
+			}
+			// 目标对象位于前缀下时排除
+			for _, srcUrl := range urls {
+				if srcUrl.(*util.CosUrl).Bucket != destUrl.(*util.CosUrl).Bucket || srcUrl.(*util.CosUrl).Object != destUrl.(*util.CosUrl).Object {
+					srcUrls = append(srcUrls, srcUrl)
+				}
+			}
+		} else {
+			for _, arg := range args[1:] {
+				srcUrl, err := formatComposeUrl(arg)
+				if err != nil {
+					return err
+				}
+				srcUrls = append(srcUrls, srcUrl)
+			}
+		}
+		if len(srcUrls) == 0 {
+			return fmt.Errorf("no source objects to compose")
+		}
+
+		return util.ComposeObjects(c, destUrl, srcUrls, fo)
+	},
+}
+
+func formatComposeUrl(path string) (util.StorageUrl, error) {
+	cosUrl, err := util.FormatUrl(path)
+	if err != nil {
+		return nil, fmt.Errorf("cos url format error:%v", err)
+	}
+	if !cosUrl.IsCosUrl() {
+		return nil, fmt.Errorf("cospath needs to contain %s", util.SchemePrefix)
+	}
+	return cosUrl, nil
+}
+
+func init() {
+	rootCmd.AddCommand(composeCmd)
+
+	composeCmd.Flags().String("prefix", "", "Compose all objects under the prefix in key order")
+	composeCmd.Flags().String("include", "", "Include files that meet the specified criteria")
+	composeCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
+	composeCmd.Flags().Int("routines", 10, "Specifies the number of concurrent parts")
+	composeCmd.Flags().String("storage-class", "", "Specifying a storage class of the composed object")
+}
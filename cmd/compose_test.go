@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestComposeCmd(t *testing.T) {
+	fmt.Println("TestComposeCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	cosObject := fmt.Sprintf("cos://%s", testAlias)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	args1 := []string{"cp", fmt.Sprintf("%s/small-file", testDir), fmt.Sprintf("%s/small/", cosObject), "-r"}
+	args2 := []string{"cp", fmt.Sprintf("%s/big-file", testDir), fmt.Sprintf("%s/big/", cosObject), "-r"}
+	cmd.SetArgs(args1)
+	cmd.Execute()
+	clearCmd()
+	cmd = rootCmd
+	cmd.SetArgs(args2)
+	cmd.Execute()
+	Convey("Test coscli compose", t, func() {
+		Convey("success", func() {
+			Convey("compose small and big objects", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/mixed", cosObject),
+					fmt.Sprintf("%s/small/0", cosObject), fmt.Sprintf("%s/big/0", cosObject),
+					fmt.Sprintf("%s/small/1", cosObject), fmt.Sprintf("%s/big/1", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("compose prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/small", cosObject), "--prefix", fmt.Sprintf("%s/small/", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("no source", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("source and prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject), fmt.Sprintf("%s/small/0", cosObject),
+					"--prefix", fmt.Sprintf("%s/small/", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("empty destination key", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", cosObject, fmt.Sprintf("%s/small/0", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject), testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("routines over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject), fmt.Sprintf("%s/small/0", cosObject), "--routines", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("source not exist", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject), fmt.Sprintf("%s/not-exist", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"compose", fmt.Sprintf("%s/out/none", cosObject), fmt.Sprintf("%s/small/0", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -25,7 +25,11 @@ Example:
   Download:
     ./coscli cp cos://examplebucket/example.txt ~/example.txt
   Copy:
-    ./coscli cp cos://examplebucket1/example1.txt cos://examplebucket2/example2.txt`,
+    ./coscli cp cos://examplebucket1/example1.txt cos://examplebucket2/example2.txt
+  Archive:
+    ./coscli cp ~/example cos://examplebucket/example.tar.gz --archive tar.gz
+  Extract:
+    ./coscli cp cos://examplebucket/example.tar.gz ~/example --extract`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
 			return err
@@ -53,6 +57,13 @@ Example:
 		disableAllSymlink, _ := cmd.Flags().GetBool("disable-all-symlink")
 		enableSymlinkDir, _ := cmd.Flags().GetBool("enable-symlink-dir")
 		disableCrc64, _ := cmd.Flags().GetBool("disable-crc64")
+		atomic, _ := cmd.Flags().GetBool("atomic")
+		clientEncrypt, _ := cmd.Flags().GetString("client-encrypt")
+		compress, _ := cmd.Flags().GetString("compress")
+		compressExt, _ := cmd.Flags().GetBool("compress-ext")
+		decompress, _ := cmd.Flags().GetBool("decompress")
+		archive, _ := cmd.Flags().GetString("archive")
+		extract, _ := cmd.Flags().GetBool("extract")
 		disableChecksum, _ := cmd.Flags().GetBool("disable-checksum")
 		disableLongLinks, _ := cmd.Flags().GetBool("disable-long-links")
 		longLinksNums, _ := cmd.Flags().GetInt("long-links-nums")
@@ -73,6 +84,9 @@ Example:
 		sseCustomerKey, _ := cmd.Flags().GetString("sse-customer-key")
 		sseCustomerKeyMD5, _ := cmd.Flags().GetString("sse-customer-key-md5")
 		checkPoint, _ := cmd.Flags().GetBool("check-point")
+		objectLockMode, _ := cmd.Flags().GetString("object-lock-mode")
+		retainUntil, _ := cmd.Flags().GetString("retain-until")
+		fromInventory, _ := cmd.Flags().GetString("from-inventory")
 
 		// 服务端加密参数验证
 		encryptionType = strings.ToUpper(encryptionType)
@@ -95,6 +109,25 @@ Example:
 			return fmt.Errorf("error: encryptionType must be either 'SSE-COS' or 'SSE-C'")
 		}
 
+		// 对象锁定参数验证
+		objectLockMode = strings.ToUpper(objectLockMode)
+		if retainUntil != "" && objectLockMode == "" {
+			objectLockMode = util.ObjectLockModeCompliance
+		}
+		if objectLockMode != "" {
+			if objectLockMode != util.ObjectLockModeCompliance && objectLockMode != util.ObjectLockModeGovernance {
+				return fmt.Errorf("error: object-lock-mode must be either 'COMPLIANCE' or 'GOVERNANCE'")
+			}
+			if retainUntil == "" {
+				return fmt.Errorf("--retain-until is required when --object-lock-mode is set")
+			}
+			var err error
+			retainUntil, err = util.ParseRetainUntil(retainUntil)
+			if err != nil {
+				return err
+			}
+		}
+
 		meta, err := util.MetaStringToHeader(metaString)
 		if err != nil {
 			return fmt.Errorf("Copy invalid meta " + err.Error())
@@ -138,6 +171,53 @@ Example:
 
 		_, filters := util.GetFilter(include, exclude)
 
+		if compress != "" {
+			if err = util.CheckCompressFormat(compress); err != nil {
+				return err
+			}
+			if !(srcUrl.IsFileUrl() && destUrl.IsCosUrl()) {
+				return fmt.Errorf("--compress only work with upload")
+			}
+			if clientEncrypt != "" {
+				return fmt.Errorf("--compress can not be used with --client-encrypt")
+			}
+		} else if compressExt {
+			return fmt.Errorf("--compress-ext only work with --compress")
+		}
+		if decompress && !(srcUrl.IsCosUrl() && destUrl.IsFileUrl()) {
+			return fmt.Errorf("--decompress only work with download")
+		}
+
+		if archive != "" {
+			if err = util.CheckArchiveFormat(archive); err != nil {
+				return err
+			}
+			if !extract && !(srcUrl.IsFileUrl() && destUrl.IsCosUrl()) {
+				return fmt.Errorf("--archive only work with upload or --extract")
+			}
+		}
+		if extract && !(srcUrl.IsCosUrl() && destUrl.IsFileUrl()) {
+			return fmt.Errorf("--extract only work with download")
+		}
+		if archive != "" || extract {
+			if compress != "" || decompress || clientEncrypt != "" {
+				return fmt.Errorf("--archive and --extract can not be used with --compress, --decompress or --client-encrypt")
+			}
+			// 归档上传及解包时include/exclude作用于归档成员
+			recursive = true
+		}
+
+		var clientEncryptKey []byte
+		if clientEncrypt != "" {
+			if srcUrl.IsCosUrl() && destUrl.IsCosUrl() {
+				return fmt.Errorf("--client-encrypt only work with upload or download")
+			}
+			clientEncryptKey, err = util.LoadMasterKey(clientEncrypt)
+			if err != nil {
+				return err
+			}
+		}
+
 		fo := &util.FileOperations{
 			Operation: util.Operation{
 				Recursive:         recursive,
@@ -159,6 +239,13 @@ Example:
 				DisableAllSymlink: disableAllSymlink,
 				EnableSymlinkDir:  enableSymlinkDir,
 				DisableCrc64:      disableCrc64,
+				Atomic:            atomic,
+				ClientEncryptKey:  clientEncryptKey,
+				Compress:          compress,
+				CompressExt:       compressExt,
+				Decompress:        decompress,
+				Archive:           archive,
+				Extract:           extract,
 				DisableChecksum:   disableChecksum,
 				DisableLongLinks:  disableLongLinks,
 				LongLinksNums:     longLinksNums,
@@ -178,6 +265,9 @@ Example:
 				SSECustomerKey:       sseCustomerKey,
 				SSECustomerKeyMD5:    sseCustomerKeyMD5,
 				CheckPoint:           checkPoint,
+				ObjectLockMode:       objectLockMode,
+				RetainUntil:          retainUntil,
+				FromInventory:        fromInventory,
 			},
 			Monitor:       &util.FileProcessMonitor{},
 			Config:        &config,
@@ -188,12 +278,26 @@ Example:
 			Command:       util.CommandCP,
 			BucketType:    "COS",
 			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
 		}
 
 		if !fo.Operation.Recursive && len(fo.Operation.Filters) > 0 {
 			return fmt.Errorf("--include or --exclude only work with --recursive")
 		}
 
+		if objectLockMode != "" && !(srcUrl.IsFileUrl() && destUrl.IsCosUrl()) {
+			return fmt.Errorf("--object-lock-mode and --retain-until only work with upload")
+		}
+
+		if fromInventory != "" {
+			if !fo.Operation.Recursive {
+				return fmt.Errorf("--from-inventory only work with --recursive")
+			}
+			if !srcUrl.IsCosUrl() {
+				return fmt.Errorf("--from-inventory only work with download or copy")
+			}
+		}
+
 		srcPath := srcUrl.ToString()
 		destPath := destUrl.ToString()
 
@@ -222,13 +326,21 @@ Example:
 			if fo.Operation.DisableCrc64 {
 				c.Conf.EnableCRC = false
 			}
-			// 格式化上传路径
-			err = util.FormatUploadPath(srcUrl, destUrl, fo)
-			if err != nil {
-				return err
+			if archive != "" {
+				// 打包上传
+				err = util.ArchiveUpload(c, srcUrl, destUrl, fo)
+				if err != nil {
+					return err
+				}
+			} else {
+				// 格式化上传路径
+				err = util.FormatUploadPath(srcUrl, destUrl, fo)
+				if err != nil {
+					return err
+				}
+				// 上传
+				util.Upload(c, srcUrl, destUrl, fo)
 			}
-			// 上传
-			util.Upload(c, srcUrl, destUrl, fo)
 		} else if srcUrl.IsCosUrl() && destUrl.IsFileUrl() {
 			operate = "Download"
 			logger.Infof("Download %s to %s start", srcPath, destPath)
@@ -272,15 +384,23 @@ Example:
 			if fo.Operation.DisableCrc64 {
 				c.Conf.EnableCRC = false
 			}
-			// 格式化下载路径
-			err = util.FormatDownloadPath(srcUrl, destUrl, fo, c)
-			if err != nil {
-				return err
-			}
-			// 下载
-			err = util.Download(c, srcUrl, destUrl, fo)
-			if err != nil {
-				return err
+			if extract {
+				// 下载并解包
+				err = util.ArchiveExtract(c, srcUrl, destUrl, fo)
+				if err != nil {
+					return err
+				}
+			} else {
+				// 格式化下载路径
+				err = util.FormatDownloadPath(srcUrl, destUrl, fo, c)
+				if err != nil {
+					return err
+				}
+				// 下载
+				err = util.Download(c, srcUrl, destUrl, fo)
+				if err != nil {
+					return err
+				}
 			}
 		} else if srcUrl.IsCosUrl() && destUrl.IsCosUrl() {
 			operate = "Copy"
@@ -347,6 +467,11 @@ Example:
 		endT := time.Now().UnixNano() / 1000 / 1000
 		util.PrintCostTime(startT, endT)
 
+		if fo.Interrupted() {
+			logger.Warningf("%s %s to %s interrupted, %s", operate, srcPath, destPath, fo.Monitor.GetFinishInfo())
+			return util.ErrInterrupted
+		}
+
 		if fo.Monitor.ErrNum > 0 || fo.Monitor.ListErrNum > 0 {
 			logger.Warningf("%s %s to %s %s", operate, srcPath, destPath, fo.Monitor.GetFinishInfo())
 			os.Exit(2)
@@ -383,6 +508,13 @@ func init() {
 	cpCmd.Flags().Bool("disable-all-symlink", true, "Ignore all symbolic link subfiles and symbolic link subdirectories when uploading, not uploaded by default")
 	cpCmd.Flags().Bool("enable-symlink-dir", false, "Upload linked subdirectories, not uploaded by default")
 	cpCmd.Flags().Bool("disable-crc64", false, "Disable CRC64 data validation. By default, coscli enables CRC64 validation for data transfer")
+	cpCmd.Flags().Bool("atomic", true, "Download into a temporary file (.coscli-tmp-*) and rename it into place after verification, so an interrupted or corrupted download never leaves a truncated file under the final name")
+	cpCmd.Flags().String("client-encrypt", "", "Path of a 32-byte master key file (raw, hex or base64). Objects are encrypted locally with a per-object data key (AES-256-GCM) before upload and decrypted transparently on download")
+	cpCmd.Flags().String("compress", "", "Compress files while uploading, optional values: gzip and zstd. Content-Encoding and x-cos-meta-original-size are set on the object")
+	cpCmd.Flags().Bool("compress-ext", false, "Append the extension of --compress (.gz or .zst) to the object key")
+	cpCmd.Flags().Bool("decompress", false, "Decompress gzip or zstd objects according to their Content-Encoding while downloading")
+	cpCmd.Flags().String("archive", "", "Upload a local directory as one archive object, optional values: tar, tar.gz and zip. The archive is streamed to COS without a local temporary file. With --extract, specifies the archive format instead of detecting it from the object key")
+	cpCmd.Flags().Bool("extract", false, "Download an archive object (.tar, .tar.gz, .tgz or .zip) and unpack it into the local directory, --include and --exclude apply to archive members")
 	cpCmd.Flags().Bool("disable-checksum", true, "Disable overall CRC64 checksum, only validate fragments")
 	cpCmd.Flags().Bool("disable-long-links", false, "Disable long links, use short links")
 	cpCmd.Flags().Int("long-links-nums", 0, "The long connection quantity parameter, if 0 or not provided, defaults to the concurrent file count.")
@@ -403,6 +535,9 @@ func init() {
 	cpCmd.Flags().String("sse-customer-key", "", "The user-provided key should be a 32-byte string, supporting combinations of numbers, letters, and special characters. Chinese characters are not supported.")
 	cpCmd.Flags().String("sse-customer-key-md5", "", "The MD5 value of the user-provided key")
 	cpCmd.Flags().Bool("check-point", true, "Whether to enable breakpoint resume, default is true, enable breakpoint resume.")
+	cpCmd.Flags().String("object-lock-mode", "", "Object lock retention mode of uploaded objects, optional values: COMPLIANCE and GOVERNANCE. Defaults to COMPLIANCE when --retain-until is set.")
+	cpCmd.Flags().String("from-inventory", "", "Read source objects from the bucket CSV inventory report manifest instead of listing the source bucket, only available for download or copy, e.g. cos://destbucket/inventory/manifest.json")
+	cpCmd.Flags().String("retain-until", "", "Object lock retention expiry date of uploaded objects, in RFC3339 (e.g. 2030-01-01T00:00:00Z) or YYYY-MM-DD format.")
 }
 
 func getCommandType(srcUrl util.StorageUrl, destUrl util.StorageUrl) util.CpType {
@@ -99,6 +99,38 @@ func TestCpCmd(t *testing.T) {
 			So(e, ShouldBeNil)
 		})
 		Convey("upload", func() {
+			Convey("压缩上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/big-file/1", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"cp", localFileName, cosFileName, "--compress", "zstd", "--compress-ext"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("打包上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.tar.gz")
+				args := []string{"cp", localFileName, cosFileName, "--archive", "tar.gz"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("客户端加密上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				keyFile := fmt.Sprintf("%s/master.key", testDir)
+				genMasterKey(keyFile)
+				localFileName := fmt.Sprintf("%s/big-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"cp", localFileName, cosFileName, "--client-encrypt", keyFile, "--part-size", "1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 			Convey("上传单个小文件并关闭crc64校验", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -223,6 +255,47 @@ func TestCpCmd(t *testing.T) {
 			})
 		})
 		Convey("Download", func() {
+			Convey("解压下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/compress", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress.zst")
+				args := []string{"cp", cosFileName, localFileName, "--decompress"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("下载解包", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/archive", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.tar.gz")
+				args := []string{"cp", cosFileName, localFileName, "--extract", "--exclude", "0$"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("客户端加密下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				keyFile := fmt.Sprintf("%s/master.key", testDir)
+				localFileName := fmt.Sprintf("%s/download/client-encrypt", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"cp", cosFileName, localFileName, "--client-encrypt", keyFile}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("关闭原子下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/non-atomic", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias2, "single-copy-small")
+				args := []string{"cp", cosFileName, localFileName, "--atomic=false"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 			Convey("下载单个小文件", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -265,6 +338,131 @@ func TestCpCmd(t *testing.T) {
 			})
 		})
 		Convey("fail", func() {
+			Convey("interrupted", func() {
+				clearCmd()
+				cmd := rootCmd
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				patches := ApplyFunc(commandContext, func() context.Context {
+					return ctx
+				})
+				defer patches.Reset()
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "interrupted/")
+				args := []string{"cp", testDir, cosFileName, "-r"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldEqual, util.ErrInterrupted)
+			})
+			Convey("compress格式非法", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"cp", localFileName, cosFileName, "--compress", "bzip2"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("compress用于下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/compress", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"cp", cosFileName, localFileName, "--compress", "gzip"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("decompress用于上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"cp", localFileName, cosFileName, "--decompress"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("compress-ext未指定compress", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"cp", localFileName, cosFileName, "--compress-ext"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("archive格式非法", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.rar")
+				args := []string{"cp", localFileName, cosFileName, "--archive", "rar"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("archive用于cos间复制", func() {
+				clearCmd()
+				cmd := rootCmd
+				srcPath := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.tar.gz")
+				dstPath := fmt.Sprintf("cos://%s/%s", testAlias2, "archive.tar.gz")
+				args := []string{"cp", srcPath, dstPath, "--archive", "tar.gz"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("extract用于上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.tar.gz")
+				args := []string{"cp", localFileName, cosFileName, "--extract"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("archive与compress同时使用", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "archive.tar")
+				args := []string{"cp", localFileName, cosFileName, "--archive", "tar", "--compress", "gzip"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("client-encrypt密钥文件不存在", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"cp", localFileName, cosFileName, "--client-encrypt", "not-exist.key"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("client-encrypt用于cos间复制", func() {
+				clearCmd()
+				cmd := rootCmd
+				srcPath := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				dstPath := fmt.Sprintf("cos://%s/%s", testAlias2, "client-encrypt")
+				args := []string{"cp", srcPath, dstPath, "--client-encrypt", fmt.Sprintf("%s/master.key", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("encryptionType非法", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -305,6 +503,45 @@ func TestCpCmd(t *testing.T) {
 				e := cmd.Execute()
 				So(e, ShouldBeError)
 			})
+			Convey("object-lock-mode非法", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/big-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "multi-big")
+				args := []string{"cp", localFileName, cosFileName, "-r", "--object-lock-mode", "LEGAL", "--retain-until", "2099-01-01"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeError)
+			})
+			Convey("retain-until已过期", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/big-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "multi-big")
+				args := []string{"cp", localFileName, cosFileName, "-r", "--retain-until", "2000-01-01"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeError)
+			})
+			Convey("object lock only work with upload", func() {
+				clearCmd()
+				cmd := rootCmd
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "multi-big")
+				args := []string{"cp", cosFileName, testDir, "-r", "--retain-until", "2099-01-01"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeError)
+			})
+			Convey("from-inventory only work with download or copy", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/big-file", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "multi-big")
+				args := []string{"cp", localFileName, cosFileName, "-r", "--from-inventory", fmt.Sprintf("cos://%s/manifest.json", testAlias1)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeError)
+			})
 			Convey("encode tag error", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -15,14 +15,50 @@ Format:
   ./coscli du cos://<bucket_alias>[/prefix/] [flags]
 
 Example:
-  ./coscli du cos://examplebucket/test/`,
+  ./coscli du cos://examplebucket/test/
+  ./coscli du cos://examplebucket/test/ --from-inventory cos://destbucket/inventory/manifest.json
+  ./coscli du cos://examplebucket/test/ --depth 2 --top 10
+  ./coscli du cos://examplebucket/test/ --depth 1 --sort count --output json`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		include, _ := cmd.Flags().GetString("include")
 		exclude, _ := cmd.Flags().GetString("exclude")
 		allVersions, _ := cmd.Flags().GetBool("all-versions")
+		fromInventory, _ := cmd.Flags().GetString("from-inventory")
+		depth, _ := cmd.Flags().GetInt("depth")
+		top, _ := cmd.Flags().GetInt("top")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		output, _ := cmd.Flags().GetString("output")
 		_, filters := util.GetFilter(include, exclude)
 
+		if depth < 0 || depth > 100 {
+			return fmt.Errorf("Flag --depth should in range 0~100")
+		}
+
+		if top < 0 {
+			return fmt.Errorf("Flag --top should be greater than or equal to 0")
+		}
+
+		if sortBy != util.DuSortBySize && sortBy != util.DuSortByCount && sortBy != util.DuSortByName {
+			return fmt.Errorf("sort '%s' is not supported, valid sorts are 'size', 'count' and 'name'", sortBy)
+		}
+
+		if output != util.OutputFormatTable && output != util.OutputFormatJson {
+			return fmt.Errorf("output '%s' is not supported, valid outputs are 'table' and 'json'", output)
+		}
+
+		// 按前缀层级统计
+		byPrefix := depth > 0 || output == util.OutputFormatJson
+		if byPrefix && allVersions {
+			return fmt.Errorf("--depth and --output json can not be used with --all-versions")
+		}
+		prefixOpt := util.DuPrefixOptions{
+			Depth:  depth,
+			Top:    top,
+			SortBy: sortBy,
+			Output: output,
+		}
+
 		cosPath := args[0]
 		cosUrl, err := util.FormatUrl(cosPath)
 		if err != nil {
@@ -38,6 +74,24 @@ Example:
 			return err
 		}
 
+		// 使用清单报告作为对象列表来源
+		if fromInventory != "" {
+			if allVersions {
+				return fmt.Errorf("--from-inventory can not be used with --all-versions")
+			}
+			source, err := util.NewInventorySource(&config, &param, fromInventory)
+			if err != nil {
+				return err
+			}
+			if err = source.CheckSourceBucket(&config, cosUrl); err != nil {
+				return err
+			}
+			if byPrefix {
+				return util.DuInventoryPrefixes(source, cosUrl, filters, prefixOpt)
+			}
+			return util.DuInventoryObjects(source, cosUrl, filters, util.DU_TYPE_CATEGORIZATION)
+		}
+
 		// 判断存储桶是否开启版本控制
 		if allVersions {
 			res, _, err := util.GetBucketVersioning(c)
@@ -55,6 +109,10 @@ Example:
 			return err
 		}
 
+		if byPrefix {
+			return util.DuPrefixObjects(c, cosUrl, filters, bucketType, prefixOpt)
+		}
+
 		err = util.DuObjects(c, cosUrl, filters, util.DU_TYPE_CATEGORIZATION, allVersions, bucketType)
 		return err
 	},
@@ -65,4 +123,9 @@ func init() {
 	duCmd.Flags().String("include", "", "List files that meet the specified criteria")
 	duCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
 	duCmd.Flags().BoolP("all-versions", "", false, "List all versions of objects, only available if bucket versioning is enabled.")
+	duCmd.Flags().Int("depth", 0, "Aggregate sizes, counts and per-class sizes for each prefix level down to the specified depth")
+	duCmd.Flags().Int("top", 0, "Only show the top K sub-prefixes of each prefix, 0 means show all")
+	duCmd.Flags().String("sort", util.DuSortBySize, "Sort sub-prefixes by size, count or name")
+	duCmd.Flags().String("output", util.OutputFormatTable, "Output format, optional values: table and json")
+	duCmd.Flags().String("from-inventory", "", "Count objects from the bucket CSV inventory report manifest instead of listing the bucket, e.g. cos://destbucket/inventory/manifest.json")
 }
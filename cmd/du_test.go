@@ -73,6 +73,22 @@ func TestDuCmd(t *testing.T) {
 				e := cmd.Execute()
 				So(e, ShouldBeNil)
 			})
+			Convey("duCosObjects by prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", fmt.Sprintf("cos://%s", testAlias), "--depth", "2", "--top", "5"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("duOfsObjects json", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", ofsFileName, "--depth", "1", "--sort", "count", "--output", "json"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 			Convey("duCosObjectVersions", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -92,6 +108,42 @@ func TestDuCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
+			Convey("depth over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", cosFileName, "--depth", "-1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid sort", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", cosFileName, "--depth", "1", "--sort", "time"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("depth with all-versions", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", versioningFileName, "--depth", "1", "--all-versions"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("from-inventory invalid manifest", func() {
+				clearCmd()
+				cmd := rootCmd
+				args = []string{"du", fmt.Sprintf("cos://%s", testAlias), "--from-inventory", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("FormatUrl", func() {
 				patches := ApplyFunc(util.FormatUrl, func(urlStr string) (util.StorageUrl, error) {
 					return nil, fmt.Errorf("test formaturl fail")
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var inventoryAnalyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze inventory report",
+	Long: `Analyze inventory report
+
+Inventory reports in CSV, ORC and Parquet format are supported.
+
+Format:
+	./coscli inventory analyze cos://<bucket-name>/<manifest-path> [flags]
+
+Example:
+	./coscli inventory analyze cos://destbucket/inventory/examplebucket/list4/20240101/manifest.json
+	./coscli inventory analyze cos://destbucket/inventory/examplebucket/list4/20240101/manifest.json --depth 2 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		depth, _ := cmd.Flags().GetInt("depth")
+		output, _ := cmd.Flags().GetString("output")
+
+		if depth < 1 || depth > 100 {
+			return fmt.Errorf("Flag --depth should in range 1~100")
+		}
+
+		if output != util.OutputFormatTable && output != util.OutputFormatJson {
+			return fmt.Errorf("output '%s' is not supported, valid outputs are 'table' and 'json'", output)
+		}
+
+		source, err := util.NewInventorySource(&config, &param, args[0])
+		if err != nil {
+			return err
+		}
+
+		return util.AnalyzeInventory(source, depth, output)
+	},
+}
+
+func init() {
+	inventoryCmd.AddCommand(inventoryAnalyzeCmd)
+	inventoryAnalyzeCmd.Flags().Int("depth", 1, "Prefix depth used to aggregate objects")
+	inventoryAnalyzeCmd.Flags().String("output", util.OutputFormatTable, "Output format, optional values: table and json")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInventoryAnalyzeCmd(t *testing.T) {
+	fmt.Println("TestInventoryAnalyzeCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	manifest := fmt.Sprintf("cos://%s/inventory/manifest.json", testAlias)
+
+	Convey("test coscli inventory analyze", t, func() {
+		Convey("success", func() {
+			Convey("json", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewInventorySource, func(config *util.Config, param *util.Param, manifestPath string) (*util.InventorySource, error) {
+					return &util.InventorySource{Manifest: &util.InventoryManifest{}}, nil
+				})
+				defer patches.Reset()
+				patches.ApplyFunc(util.AnalyzeInventory, func(source *util.InventorySource, depth int, output string) error {
+					return nil
+				})
+				args := []string{"inventory", "analyze", manifest, "--depth", "2", "--output", "json"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("depth over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"inventory", "analyze", manifest, "--depth", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid output", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"inventory", "analyze", manifest, "--output", "xml"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("manifest not exist", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewInventorySource, func(config *util.Config, param *util.Param, manifestPath string) (*util.InventorySource, error) {
+					return nil, fmt.Errorf("test get inventory manifest error")
+				})
+				defer patches.Reset()
+				args := []string{"inventory", "analyze", manifest}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
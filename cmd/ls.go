@@ -15,7 +15,8 @@ Format:
   ./coscli ls cos://<bucket-name>[/prefix/] [flags]
 
 Example:
-  ./coscli ls cos://examplebucket/test/ -r`,
+  ./coscli ls cos://examplebucket/test/ -r
+  ./coscli ls cos://examplebucket/test/ -r --from-inventory cos://destbucket/inventory/manifest.json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		limit, _ := cmd.Flags().GetInt("limit")
@@ -23,6 +24,7 @@ Example:
 		include, _ := cmd.Flags().GetString("include")
 		exclude, _ := cmd.Flags().GetString("exclude")
 		allVersions, _ := cmd.Flags().GetBool("all-versions")
+		fromInventory, _ := cmd.Flags().GetString("from-inventory")
 		if limit == 0 {
 			limit = 10000
 		} else if limit < -1 {
@@ -68,6 +70,21 @@ Example:
 
 			_, filters := util.GetFilter(include, exclude)
 
+			// 使用清单报告作为对象列表来源
+			if fromInventory != "" {
+				if allVersions {
+					return fmt.Errorf("--from-inventory can not be used with --all-versions")
+				}
+				source, err := util.NewInventorySource(&config, &param, fromInventory)
+				if err != nil {
+					return err
+				}
+				if err = source.CheckSourceBucket(&config, cosUrl); err != nil {
+					return err
+				}
+				return util.ListInventoryObjects(source, cosUrl, limit, recursive, filters)
+			}
+
 			// 获取桶类型
 			bucketType, err := util.GetBucketType(c, &param, &config, bucketName)
 			if err != nil {
@@ -108,4 +125,5 @@ func init() {
 	lsCmd.Flags().String("include", "", "List files that meet the specified criteria")
 	lsCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
 	lsCmd.Flags().BoolP("all-versions", "", false, "List all versions of objects, only available if bucket versioning is enabled.")
+	lsCmd.Flags().String("from-inventory", "", "List objects from the bucket CSV inventory report manifest instead of listing the bucket, e.g. cos://destbucket/inventory/manifest.json")
 }
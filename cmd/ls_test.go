@@ -97,6 +97,15 @@ func TestLsCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
+			Convey("from-inventory invalid manifest", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"ls", fmt.Sprintf("cos://%s", testAlias), "-r", "--from-inventory", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("FormatUrl", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"coscli/util"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var presignPostCmd = &cobra.Command{
+	Use:   "presign-post",
+	Short: "Generate the policy and signature for browser form uploads",
+	Long: `Generate the policy and signature for browser form uploads
+
+The URL and form fields are printed as JSON. When the path ends with "/", any
+key under the prefix is allowed and the "key" field is set to
+"<prefix>${filename}"; otherwise only the exact key can be uploaded.
+A content type ending with "*" only limits the prefix of Content-Type, the
+form needs to fill in the Content-Type field itself.
+
+Format:
+  ./coscli presign-post cos://<bucket-name>[/<prefix>/|/<key>] [flags]
+
+Example:
+  ./coscli presign-post cos://examplebucket/uploads/ --expire 30m --max-size 10MB --content-type "image/*"
+  ./coscli presign-post cos://examplebucket/avatar/1.png --min-size 1KB --max-size 1MB --content-type image/png`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expire, _ := cmd.Flags().GetString("expire")
+		minSize, _ := cmd.Flags().GetString("min-size")
+		maxSize, _ := cmd.Flags().GetString("max-size")
+		contentType, _ := cmd.Flags().GetString("content-type")
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return fmt.Errorf("cos url format error:%v", err)
+		}
+
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		opt := util.PostPolicyOptions{
+			Key:         cosUrl.(*util.CosUrl).Object,
+			ContentType: contentType,
+		}
+		if opt.Expire, err = util.ParseAge(expire); err != nil {
+			return fmt.Errorf("Flag --expire error : %v", err)
+		}
+		if opt.Expire <= 0 {
+			return fmt.Errorf("Flag --expire should be greater than 0")
+		}
+		if opt.MinSize, err = util.ParseSize(minSize); err != nil {
+			return fmt.Errorf("Flag --min-size error : %v", err)
+		}
+		if opt.MaxSize, err = util.ParseSize(maxSize); err != nil {
+			return fmt.Errorf("Flag --max-size error : %v", err)
+		}
+		if opt.MaxSize > 0 && opt.MinSize > opt.MaxSize {
+			return fmt.Errorf("Flag --min-size should not be greater than --max-size")
+		}
+
+		bucketName := cosUrl.(*util.CosUrl).Bucket
+		bucket, _, err := util.FindBucket(&config, bucketName)
+		if err != nil {
+			return err
+		}
+
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		form, err := util.GeneratePostPolicy(c, bucket.Name, opt)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(form)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(presignPostCmd)
+
+	presignPostCmd.Flags().String("expire", "1h", "Validity time of the policy, such as 30m, 2h or 1d")
+	presignPostCmd.Flags().String("min-size", "", "Minimum size of the uploaded file, such as 1KB")
+	presignPostCmd.Flags().String("max-size", "", "Maximum size of the uploaded file, such as 10MB")
+	presignPostCmd.Flags().String("content-type", "", "Content-Type of the uploaded file, ending with * to limit the prefix, such as image/*")
+}
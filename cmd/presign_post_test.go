@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"coscli/util"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestPresignPostCmd(t *testing.T) {
+	fmt.Println("TestPresignPostCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	defer cmd.SetOut(nil)
+
+	Convey("test coscli presign-post", t, func() {
+		Convey("success", func() {
+			Convey("prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"presign-post", fmt.Sprintf("cos://%s/uploads/", testAlias),
+					"--expire", "30m", "--max-size", "10MB", "--content-type", "image/*"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				form := util.PostPolicyForm{}
+				So(json.Unmarshal(out.Bytes(), &form), ShouldBeNil)
+				So(form.Fields["key"], ShouldEqual, "uploads/${filename}")
+				So(form.Fields["q-signature"], ShouldNotBeEmpty)
+				policy, err := base64.StdEncoding.DecodeString(form.Fields["policy"])
+				So(err, ShouldBeNil)
+				So(string(policy), ShouldContainSubstring, `["starts-with","$key","uploads/"]`)
+				So(string(policy), ShouldContainSubstring, `["content-length-range",0,10485760]`)
+				So(string(policy), ShouldContainSubstring, `["starts-with","$Content-Type","image/"]`)
+			})
+			Convey("exact key", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetOut(&out)
+				args := []string{"presign-post", fmt.Sprintf("cos://%s/avatar/1.png", testAlias), "--content-type", "image/png"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				form := util.PostPolicyForm{}
+				So(json.Unmarshal(out.Bytes(), &form), ShouldBeNil)
+				So(form.Fields["key"], ShouldEqual, "avatar/1.png")
+				So(form.Fields["Content-Type"], ShouldEqual, "image/png")
+			})
+		})
+		Convey("fail", func() {
+			Convey("not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"presign-post"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"presign-post", testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid expire", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"presign-post", fmt.Sprintf("cos://%s/", testAlias), "--expire", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("min size greater than max size", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"presign-post", fmt.Sprintf("cos://%s/", testAlias), "--min-size", "2MB", "--max-size", "1MB"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"presign-post", fmt.Sprintf("cos://%s/", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
@@ -57,6 +57,7 @@ Example:
 					Config:    &config,
 					Param:     &param,
 					ErrOutput: &util.ErrOutput{},
+					Ctx:       commandContext(),
 				}
 
 				bucketType, err := util.GetBucketType(c, &param, &config, bucketIDName)
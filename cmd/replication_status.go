@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var replicationStatusCmd = &cobra.Command{
+	Use:   "replication-status",
+	Short: "Show the cross-region replication status of objects",
+	Long: `Show the cross-region replication status of objects
+
+Format:
+  ./coscli replication-status cos://<bucket-name>[/<prefix>] [flags]
+
+Example:
+  ./coscli replication-status cos://examplebucket/test/ -r
+  ./coscli replication-status cos://examplebucket/test/ -r --include ".*\.log$" --routines 10
+  ./coscli replication-status cos://examplebucket/test.txt`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		routines, _ := cmd.Flags().GetInt("routines")
+		failOutput, _ := cmd.Flags().GetBool("fail-output")
+		failOutputPath, _ := cmd.Flags().GetString("fail-output-path")
+
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+
+		_, filters := util.GetFilter(include, exclude)
+
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Recursive:      recursive,
+				Filters:        filters,
+				Routines:       routines,
+				FailOutput:     failOutput,
+				FailOutputPath: failOutputPath,
+			},
+			Config:        &config,
+			Param:         &param,
+			ErrOutput:     &util.ErrOutput{},
+			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
+		}
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return fmt.Errorf("cos url format error:%v", err)
+		}
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain %s", util.SchemePrefix)
+		}
+
+		bucketName := cosUrl.(*util.CosUrl).Bucket
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if recursive {
+			return util.ReplicationStatus(c, cosUrl, fo)
+		}
+		return util.SingleReplicationStatus(c, cosUrl)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replicationStatusCmd)
+
+	replicationStatusCmd.Flags().BoolP("recursive", "r", false, "Scan objects recursively")
+	replicationStatusCmd.Flags().String("include", "", "Include files that meet the specified criteria")
+	replicationStatusCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
+	replicationStatusCmd.Flags().Int("routines", 10, "Specifies the number of concurrent head requests")
+	replicationStatusCmd.Flags().Bool("fail-output", true, "This option determines whether objects that are not yet replicated and head failures are recorded in a file within the specified directory (if not specified, the default directory is coscli_output). If disabled, only the summary will be output to the console.")
+	replicationStatusCmd.Flags().String("fail-output-path", "coscli_output", "This option specifies the output folder where objects that are not yet replicated will be recorded. If this option is not set, the default folder (coscli_output) will be used.")
+}
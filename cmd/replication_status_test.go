@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestReplicationStatusCmd(t *testing.T) {
+	fmt.Println("TestReplicationStatusCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	localObject := fmt.Sprintf("%s/small-file/0", testDir)
+	localFileName := fmt.Sprintf("%s/small-file", testDir)
+	cosObject := fmt.Sprintf("cos://%s", testAlias)
+	cosFileName := fmt.Sprintf("cos://%s/%s", testAlias, "multi-small")
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	args1 := []string{"cp", localObject, cosObject}
+	args2 := []string{"cp", localFileName, cosFileName, "-r"}
+	cmd.SetArgs(args1)
+	cmd.Execute()
+	clearCmd()
+	cmd = rootCmd
+	cmd.SetArgs(args2)
+	cmd.Execute()
+	Convey("Test coscli replication-status", t, func() {
+		Convey("success", func() {
+			Convey("SingleReplicationStatus", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status", fmt.Sprintf("%s/0", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("ReplicationStatus", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status", cosFileName, "-r"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("Not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("routines over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status", cosFileName, "-r", "--routines", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("object not found", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"replication-status", fmt.Sprintf("%s/not-exist", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"replication-status", cosFileName, "-r"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
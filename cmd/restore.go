@@ -3,7 +3,10 @@ package cmd
 import (
 	"coscli/util"
 	"fmt"
+	"os"
+	"time"
 
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -16,7 +19,9 @@ Format:
   ./coscli restore cos://<bucket-name>[/<prefix>] [flags]
 
 Example:
-  ./coscli restore cos://examplebucket/test/ -r -d 3 -m Expedited`,
+  ./coscli restore cos://examplebucket/test/ -r -d 3 -m Expedited
+  ./coscli restore cos://examplebucket/test/ -r --routines 20 --progress-path ./restore_progress
+  ./coscli restore cos://examplebucket/test/ -r --wait --wait-interval 5m --download-to ~/example/`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		recursive, _ := cmd.Flags().GetBool("recursive")
@@ -26,11 +31,44 @@ Example:
 		mode, _ := cmd.Flags().GetString("mode")
 		failOutput, _ := cmd.Flags().GetBool("fail-output")
 		failOutputPath, _ := cmd.Flags().GetString("fail-output-path")
+		routines, _ := cmd.Flags().GetInt("routines")
+		progressPath, _ := cmd.Flags().GetString("progress-path")
+		wait, _ := cmd.Flags().GetBool("wait")
+		waitInterval, _ := cmd.Flags().GetString("wait-interval")
+		waitTimeout, _ := cmd.Flags().GetString("wait-timeout")
+		downloadTo, _ := cmd.Flags().GetString("download-to")
+		fromInventory, _ := cmd.Flags().GetString("from-inventory")
 
 		if days < 1 || days > 365 {
 			return fmt.Errorf("Flag --days should in range 1~365")
 		}
 
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+
+		if fromInventory != "" && !recursive {
+			return fmt.Errorf("--from-inventory only work with --recursive")
+		}
+
+		// 指定下载目录时需要等待回热完成
+		if downloadTo != "" {
+			wait = true
+		}
+
+		interval, err := util.ParseAge(waitInterval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid --wait-interval %s", waitInterval)
+		}
+
+		var timeout time.Duration
+		if waitTimeout != "" {
+			timeout, err = util.ParseAge(waitTimeout)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("invalid --wait-timeout %s", waitTimeout)
+			}
+		}
+
 		_, filters := util.GetFilter(include, exclude)
 
 		fo := &util.FileOperations{
@@ -41,11 +79,18 @@ Example:
 				FailOutputPath: failOutputPath,
 				Days:           days,
 				RestoreMode:    mode,
+				Routines:       routines,
+				ProgressPath:   progressPath,
+				WaitInterval:   interval,
+				WaitTimeout:    timeout,
+				FromInventory:  fromInventory,
 			},
-			Config:    &config,
-			Param:     &param,
-			ErrOutput: &util.ErrOutput{},
-			Command:   util.CommandRestore,
+			Config:        &config,
+			Param:         &param,
+			ErrOutput:     &util.ErrOutput{},
+			Command:       util.CommandRestore,
+			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
 		}
 
 		cosPath := ""
@@ -66,8 +111,8 @@ Example:
 			return err
 		}
 
+		var bucketType string
 		if recursive {
-			var bucketType string
 			// 获取桶类型
 			bucketType, err = util.GetBucketType(c, fo.Param, fo.Config, bucketName)
 			if err != nil {
@@ -75,12 +120,108 @@ Example:
 			}
 			err = util.RestoreObjects(c, cosUrl, fo, bucketType)
 		} else {
-			_, err = util.TryRestoreObject(c, bucketName, cosUrl.(*util.CosUrl).Object, days, mode)
+			resp, e := util.TryRestoreObject(c, bucketName, cosUrl.(*util.CosUrl).Object, days, mode, fo)
+			// 对象已在回热中
+			if e != nil && (resp == nil || resp.StatusCode != 409) {
+				err = e
+			}
 		}
-		return err
+		util.CloseErrorOutputFile(fo)
+		if err != nil || !wait {
+			return err
+		}
+
+		// 等待回热完成
+		if recursive {
+			err = util.WaitRestoreObjects(c, cosUrl, fo, bucketType)
+		} else {
+			err = util.WaitRestoreObject(c, cosUrl, fo)
+		}
+		if err != nil || downloadTo == "" {
+			return err
+		}
+
+		return restoreDownload(cosUrl, downloadTo, fo, bucketType)
 	},
 }
 
+// restoreDownload 回热完成后下载对象到本地
+func restoreDownload(cosUrl util.StorageUrl, downloadTo string, restoreFo *util.FileOperations, bucketType string) error {
+	fileUrl, err := util.FormatUrl(downloadTo)
+	if err != nil {
+		return fmt.Errorf("local url format error:%v", err)
+	}
+	if !fileUrl.IsFileUrl() {
+		return fmt.Errorf("--download-to needs to be a local path")
+	}
+
+	fo := &util.FileOperations{
+		Operation: util.Operation{
+			Recursive:       restoreFo.Operation.Recursive,
+			Filters:         restoreFo.Operation.Filters,
+			PartSize:        32,
+			Routines:        restoreFo.Operation.Routines,
+			FailOutput:      restoreFo.Operation.FailOutput,
+			FailOutputPath:  restoreFo.Operation.FailOutputPath,
+			ProcessLog:      true,
+			ProcessLogPath:  restoreFo.Operation.FailOutputPath,
+			ErrRetryNum:     5,
+			DisableChecksum: true,
+			CheckPoint:      true,
+			FromInventory:   restoreFo.Operation.FromInventory,
+			Atomic:          true,
+		},
+		Monitor:       &util.FileProcessMonitor{},
+		Config:        &config,
+		Param:         &param,
+		ErrOutput:     &util.ErrOutput{},
+		ProcessLogger: &util.ProcessLogger{},
+		CpType:        util.CpTypeDownload,
+		Command:       util.CommandCP,
+		BucketType:    bucketType,
+		OutPutDirName: restoreFo.OutPutDirName,
+		Ctx:           restoreFo.Ctx,
+	}
+
+	bucketName := cosUrl.(*util.CosUrl).Bucket
+	c, err := util.NewClient(fo.Config, fo.Param, bucketName, fo)
+	if err != nil {
+		return err
+	}
+	// 单个对象回热时未获取桶类型
+	if fo.BucketType == "" {
+		fo.BucketType, err = util.GetBucketType(c, fo.Param, fo.Config, bucketName)
+		if err != nil {
+			return err
+		}
+	}
+
+	srcPath := cosUrl.ToString()
+	logger.Infof("Download %s to %s start", srcPath, fileUrl.ToString())
+	startT := time.Now().UnixNano() / 1000 / 1000
+	// 格式化下载路径
+	err = util.FormatDownloadPath(cosUrl, fileUrl, fo, c)
+	if err != nil {
+		return err
+	}
+	err = util.Download(c, cosUrl, fileUrl, fo)
+	if err != nil {
+		return err
+	}
+	util.CloseErrorOutputFile(fo)
+	util.CloseProcessLoggerFile(fo)
+	endT := time.Now().UnixNano() / 1000 / 1000
+	util.PrintCostTime(startT, endT)
+
+	if fo.Monitor.ErrNum > 0 || fo.Monitor.ListErrNum > 0 {
+		logger.Warningf("Download %s to %s %s", srcPath, fileUrl.ToString(), fo.Monitor.GetFinishInfo())
+		os.Exit(2)
+	} else {
+		logger.Infof("Download %s to %s %s", srcPath, fileUrl.ToString(), fo.Monitor.GetFinishInfo())
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(restoreCmd)
 
@@ -91,4 +232,11 @@ func init() {
 	restoreCmd.Flags().StringP("mode", "m", "Standard", "Specifies the mode for fetching temporary files")
 	restoreCmd.Flags().Bool("fail-output", true, "This option determines whether error output for failed file restore is enabled. If enabled, any error messages for failed file reheats will be recorded in a file within the specified directory (if not specified, the default directory is coscli_output). If disabled, only the number of error files will be output to the console.")
 	restoreCmd.Flags().String("fail-output-path", "coscli_output", "This option specifies the error output folder where error messages for file restore failures will be recorded. By providing a custom folder path, you can control the location and name of the error output folder. If this option is not set, the default error log folder (coscli_output) will be used.")
+	restoreCmd.Flags().Int("routines", 10, "Specifies the number of objects restored concurrently")
+	restoreCmd.Flags().String("progress-path", "", "Persist restore progress to the specified directory. Objects already restored in a previous run with the same progress path will be skipped.")
+	restoreCmd.Flags().Bool("wait", false, "Wait until all restored objects are readable")
+	restoreCmd.Flags().String("wait-interval", "1m", "Interval between restore status checks when --wait is set, e.g. 30s, 5m, 1h")
+	restoreCmd.Flags().String("wait-timeout", "", "Maximum time to wait for restore when --wait is set, e.g. 12h, 2d. Wait forever if not set")
+	restoreCmd.Flags().String("from-inventory", "", "Restore objects listed in the bucket CSV inventory report manifest instead of listing the bucket, e.g. cos://destbucket/inventory/manifest.json")
+	restoreCmd.Flags().String("download-to", "", "Download the objects to the specified local path once restore is finished, implies --wait")
 }
@@ -53,6 +53,32 @@ func TestRestoreCmd(t *testing.T) {
 				e := cmd.Execute()
 				So(e, ShouldBeNil)
 			})
+			Convey("RestoreObjects with progress", func() {
+				clearCmd()
+				cmd := rootCmd
+				progressPath := fmt.Sprintf("%s/restore_progress", testDir)
+				args := []string{"restore", cosFileName, "-r", "--routines", "5", "--progress-path", progressPath}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				clearCmd()
+				cmd = rootCmd
+				cmd.SetArgs(args)
+				e = cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("RestoreObjects wait", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.WaitRestoreObjects, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations, bucketType string) error {
+					return nil
+				})
+				defer patches.Reset()
+				args := []string{"restore", cosFileName, "-r", "--wait", "--wait-interval", "1s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 		})
 		Convey("fail", func() {
 			Convey("Not enough arguments", func() {
@@ -73,6 +99,46 @@ func TestRestoreCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
+			Convey("from-inventory without recursive", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"restore", fmt.Sprintf("%s/0", cosObject), "--from-inventory", fmt.Sprintf("%s/manifest.json", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("routines over range", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"restore", cosFileName, "-r", "--routines", "0"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid wait interval", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"restore", cosFileName, "-r", "--wait", "--wait-interval", "abc"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("wait timeout", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.WaitRestoreObject, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations) error {
+					return fmt.Errorf("test wait restore timeout")
+				})
+				defer patches.Reset()
+				args := []string{"restore", fmt.Sprintf("%s/0", cosObject), "--wait", "--wait-timeout", "1s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("FormatUrl", func() {
 				patches := ApplyFunc(util.FormatUrl, func(urlStr string) (util.StorageUrl, error) {
 					return nil, fmt.Errorf("test formaturl fail")
@@ -16,7 +16,8 @@ Format:
   ./coscli rm cos://<bucket-name>[/prefix/] [cos://<bucket-name>[/prefix/]...] [flags]
 
 Example:
-  ./coscli rm cos://example/test/ -r`,
+  ./coscli rm cos://example/test/ -r
+  ./coscli rm cos://example/test/ -r --from-inventory cos://destbucket/inventory/manifest.json`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
 			return err
@@ -40,6 +41,7 @@ Example:
 		failOutputPath, _ := cmd.Flags().GetString("fail-output-path")
 		allVersions, _ := cmd.Flags().GetBool("all-versions")
 		versionId, _ := cmd.Flags().GetString("version-id")
+		fromInventory, _ := cmd.Flags().GetString("from-inventory")
 
 		_, filters := util.GetFilter(include, exclude)
 
@@ -51,6 +53,10 @@ Example:
 			return fmt.Errorf("all-versions can not be used to delete single object")
 		}
 
+		if fromInventory != "" && !recursive {
+			return fmt.Errorf("--from-inventory only work with --recursive")
+		}
+
 		fo := &util.FileOperations{
 			Operation: util.Operation{
 				Recursive:      recursive,
@@ -62,12 +68,14 @@ Example:
 				FailOutputPath: failOutputPath,
 				AllVersions:    allVersions,
 				VersionId:      versionId,
+				FromInventory:  fromInventory,
 			},
 			Monitor:   &util.FileProcessMonitor{},
 			Config:    &config,
 			Param:     &param,
 			ErrOutput: &util.ErrOutput{},
 			Command:   util.CommandRm,
+			Ctx:       commandContext(),
 		}
 		var err error
 		if recursive {
@@ -91,5 +99,6 @@ func init() {
 	rmCmd.Flags().Bool("fail-output", true, "This option determines whether error output for failed file deletions is enabled. If enabled, any error messages for failed file deletions will be recorded in a file within the specified directory (if not specified, the default directory is coscli_output). If disabled, only the number of error files will be output to the console.")
 	rmCmd.Flags().String("fail-output-path", "coscli_output", "This option specifies the error output folder where error messages for failed file deletions will be recorded. By providing a custom folder path, you can control the location and name of the error output folder. If this option is not set, the default error log folder (coscli_output) will be used.")
 	rmCmd.Flags().BoolP("all-versions", "", false, "remove all versions of objects, only available if bucket versioning is enabled.")
+	rmCmd.Flags().String("from-inventory", "", "Remove objects listed in the bucket CSV inventory report manifest instead of listing the bucket, e.g. cos://destbucket/inventory/manifest.json")
 	rmCmd.Flags().String("version-id", "", "remove Downloading a specified version of a object, only available if bucket versioning is enabled.")
 }
@@ -104,6 +104,15 @@ func TestRmCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
+			Convey("from-inventory without recursive", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"rm", fmt.Sprintf("cos://%s/test", testAlias), "--from-inventory", fmt.Sprintf("cos://%s/manifest.json", testAlias)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("Invalid arguments", func() {
 				clearCmd()
 				cmd := rootCmd
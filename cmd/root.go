@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	clilog "coscli/logger"
 	"coscli/util"
 	"fmt"
@@ -32,11 +33,26 @@ var rootCmd = &cobra.Command{
 	Version: util.Version,
 }
 
+// stopInterrupt 停止监听中断信号，交互式shell中每条命令单独监听
+var stopInterrupt = func() {}
+
 // Execute 执行
 func Execute() error {
 	rootCmd.SilenceErrors = true
 	rootCmd.SilenceUsage = true
-	return rootCmd.Execute()
+	ctx, stop := util.NewInterruptContext(context.Background())
+	defer stop()
+	stopInterrupt = stop
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// commandContext 当前执行命令的context
+// 子命令的cmd.Context()在首次执行后不再更新，shell中需从rootCmd获取
+func commandContext() context.Context {
+	if ctx := rootCmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
 }
 
 func init() {
@@ -57,6 +73,11 @@ func init() {
 }
 
 func initConfig() {
+	// 交互式shell中已加载过配置，无需重复加载
+	if inShell {
+		return
+	}
+
 	// 初始化日志路径
 	clilog.InitLoggerWithDir(logPath, disableLog)
 
@@ -80,6 +101,10 @@ func initConfig() {
 	} else {
 		_, err = os.Stat(home + "/.cos.yaml")
 		if os.IsNotExist(err) {
+			// 补全时不能进行交互式初始化，直接跳过
+			if isCompletionRequest(firstArg) {
+				return
+			}
 			if firstArg != "config" {
 				if !initSkip {
 					log.Println("Welcome to coscli!\nWhen you use coscli for the first time, you need to input some necessary information to generate the default configuration file of coscli.")
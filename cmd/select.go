@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var selectCmd = &cobra.Command{
+	Use:   "select",
+	Short: "Query CSV or JSON object with SQL",
+	Long: `Query CSV or JSON object with SQL
+
+Only the matched records are returned and printed to stdout, the bytes scanned,
+processed and returned are printed to stderr at the end. The input format and
+compression are detected by the object suffix (.csv, .json, .jsonl, .gz, .bz2)
+unless specified. Delimiters accept escapes such as "\t".
+
+Format:
+  ./coscli select cos://<bucket-name>-<appid>/<object> --sql <expression> [flags]
+
+Example:
+  ./coscli select cos://examplebucket-1234567890/data.csv --sql "select s._1, s._3 from cosobject s where s._2 = '2024'"
+  ./coscli select cos://examplebucket-1234567890/data.csv.gz --sql "select * from cosobject s where s.city = 'beijing'" --csv-header use
+  ./coscli select cos://examplebucket-1234567890/data.tsv --sql "select count(*) from cosobject s" --csv-delimiter "\t"
+  ./coscli select cos://examplebucket-1234567890/events.jsonl --sql "select s.user from cosobject s where s.code > 400" --output-format csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opt := util.SelectOptions{}
+		opt.Expression, _ = cmd.Flags().GetString("sql")
+		opt.InputFormat, _ = cmd.Flags().GetString("input-format")
+		opt.Compression, _ = cmd.Flags().GetString("compression")
+		opt.CSVHeader, _ = cmd.Flags().GetString("csv-header")
+		opt.CSVDelimiter, _ = cmd.Flags().GetString("csv-delimiter")
+		opt.CSVRecordDelimiter, _ = cmd.Flags().GetString("csv-record-delimiter")
+		opt.CSVQuote, _ = cmd.Flags().GetString("csv-quote")
+		opt.CSVComments, _ = cmd.Flags().GetString("csv-comments")
+		opt.JSONType, _ = cmd.Flags().GetString("json-type")
+		opt.OutputFormat, _ = cmd.Flags().GetString("output-format")
+		opt.OutputDelimiter, _ = cmd.Flags().GetString("output-delimiter")
+		opt.OutputRecordDelimiter, _ = cmd.Flags().GetString("output-record-delimiter")
+		opt.QuoteFields, _ = cmd.Flags().GetString("quote-fields")
+
+		if opt.Expression == "" {
+			return fmt.Errorf("--sql can not be empty")
+		}
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return err
+		}
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+		object := cosUrl.(*util.CosUrl).Object
+		if object == "" || strings.HasSuffix(object, "/") {
+			return fmt.Errorf("%s is not an object", args[0])
+		}
+
+		fo := &util.FileOperations{
+			Config: &config,
+			Param:  &param,
+			Ctx:    commandContext(),
+		}
+
+		c, err := util.NewClient(&config, &param, cosUrl.(*util.CosUrl).Bucket)
+		if err != nil {
+			return err
+		}
+		return util.SelectObject(c, cosUrl, opt, fo)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selectCmd)
+
+	selectCmd.Flags().String("sql", "", "SQL expression, the object is referenced as cosobject, such as: select * from cosobject s limit 10")
+	selectCmd.Flags().String("input-format", "", "Format of the object, optional values: csv and json. Detected by the object suffix by default")
+	selectCmd.Flags().String("compression", "", "Compression of the object, optional values: none, gzip and bzip2. Detected by the object suffix by default")
+	selectCmd.Flags().String("csv-header", "", "How to use the first line of csv, optional values: none, use and ignore. Columns can be referenced by name with use")
+	selectCmd.Flags().String("csv-delimiter", "", "Field delimiter of csv, default is \",\"")
+	selectCmd.Flags().String("csv-record-delimiter", "", "Record delimiter of csv, default is \"\\n\"")
+	selectCmd.Flags().String("csv-quote", "", "Quote character of csv, default is '\"'")
+	selectCmd.Flags().String("csv-comments", "", "Lines starting with this character are skipped in csv")
+	selectCmd.Flags().String("json-type", "", "Type of json, optional values: lines and document, default is lines")
+	selectCmd.Flags().String("output-format", "", "Format of the returned records, optional values: csv and json. The same as the input format by default")
+	selectCmd.Flags().String("output-delimiter", "", "Field delimiter of csv output, default is \",\"")
+	selectCmd.Flags().String("output-record-delimiter", "", "Record delimiter of the returned records, default is \"\\n\"")
+	selectCmd.Flags().String("quote-fields", "", "When to quote fields of csv output, optional values: always and asneeded")
+}
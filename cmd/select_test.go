@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestSelectCmd(t *testing.T) {
+	fmt.Println("TestSelectCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	cosObject := fmt.Sprintf("cos://%s", testAlias)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	args := []string{"cp", fmt.Sprintf("%s/small-file/0", testDir), fmt.Sprintf("%s/data.csv", cosObject)}
+	cmd.SetArgs(args)
+	cmd.Execute()
+	Convey("Test coscli select", t, func() {
+		Convey("success", func() {
+			Convey("select csv", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select count(*) from cosobject s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("select csv to json", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select * from cosobject s limit 1",
+					"--csv-delimiter", "\\t", "--output-format", "json"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("empty sql", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", testDir, "--sql", "select * from cosobject s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not object", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/", cosObject), "--sql", "select * from cosobject s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid input format", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select * from cosobject s", "--input-format", "parquet"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("csv option with json input", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select * from cosobject s",
+					"--input-format", "json", "--csv-header", "use"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid sql", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "invalid sql"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select * from cosobject s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("SelectObject", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.SelectObject, func(c *cos.Client, cosUrl util.StorageUrl, opt util.SelectOptions, fo *util.FileOperations) error {
+					return fmt.Errorf("test select error")
+				})
+				defer patches.Reset()
+				args := []string{"select", fmt.Sprintf("%s/data.csv", cosObject), "--sql", "select * from cosobject s"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
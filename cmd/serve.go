@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a bucket over local HTTP",
+	Long: `Serve a bucket over local HTTP
+
+GET and HEAD requests (with Range support) are mapped to objects under the
+prefix, paths ending with "/" return a directory listing. PUT and DELETE are
+only allowed with --writable, which requires --user/--password (basic auth)
+or --auth-token (Bearer token or ?token= query) to protect the gateway.
+
+Format:
+  ./coscli serve cos://<bucket-name>[/<prefix>] [flags]
+
+Example:
+  ./coscli serve cos://examplebucket/test/ --listen 127.0.0.1:8080
+  ./coscli serve cos://examplebucket --listen :8080 --user admin --password 123456 --writable
+  ./coscli serve cos://examplebucket --listen :8080 --auth-token my-secret-token`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listen, _ := cmd.Flags().GetString("listen")
+		writable, _ := cmd.Flags().GetBool("writable")
+		user, _ := cmd.Flags().GetString("user")
+		password, _ := cmd.Flags().GetString("password")
+		token, _ := cmd.Flags().GetString("auth-token")
+
+		if listen == "" {
+			return fmt.Errorf("Flag --listen can not be empty")
+		}
+		if user == "" && password != "" {
+			return fmt.Errorf("Flag --password needs to be used with --user")
+		}
+		if writable && user == "" && token == "" {
+			return fmt.Errorf("Flag --writable needs to be used with --user or --auth-token")
+		}
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return fmt.Errorf("cos url format error:%v", err)
+		}
+
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain cos://")
+		}
+
+		bucketName := cosUrl.(*util.CosUrl).Bucket
+		prefix := cosUrl.(*util.CosUrl).Object
+		if prefix != "" && !strings.HasSuffix(prefix, util.CosSeparator) {
+			prefix += util.CosSeparator
+		}
+
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		return util.Serve(commandContext(), c, prefix, util.ServeOptions{
+			Listen:   listen,
+			Writable: writable,
+			User:     user,
+			Password: password,
+			Token:    token,
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().Bool("writable", false, "Allow PUT and DELETE requests")
+	serveCmd.Flags().String("user", "", "Username of basic auth")
+	serveCmd.Flags().String("password", "", "Password of basic auth")
+	serveCmd.Flags().String("auth-token", "", "Token required in the Authorization header (Bearer) or the token query parameter")
+}
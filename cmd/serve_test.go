@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"coscli/util"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestServeCmd(t *testing.T) {
+	fmt.Println("TestServeCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	Convey("test coscli serve", t, func() {
+		Convey("success", func() {
+			Convey("serve prefix", func() {
+				clearCmd()
+				cmd := rootCmd
+				var servePrefix string
+				var serveOpt util.ServeOptions
+				patches := ApplyFunc(util.Serve, func(ctx context.Context, c *cos.Client, prefix string, opt util.ServeOptions) error {
+					servePrefix = prefix
+					serveOpt = opt
+					return nil
+				})
+				defer patches.Reset()
+				args := []string{"serve", fmt.Sprintf("cos://%s/test", testAlias), "--listen", ":18080", "--writable", "--auth-token", "abc"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(servePrefix, ShouldEqual, "test/")
+				So(serveOpt.Listen, ShouldEqual, ":18080")
+				So(serveOpt.Writable, ShouldBeTrue)
+				So(serveOpt.Token, ShouldEqual, "abc")
+			})
+		})
+		Convey("fail", func() {
+			Convey("not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"serve"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"serve", testDir}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("writable without auth", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"serve", fmt.Sprintf("cos://%s", testAlias), "--writable"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("password without user", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"serve", fmt.Sprintf("cos://%s", testAlias), "--password", "123"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
+
+// newFakeCosServer 模拟cos接口，objects为对象key及内容
+func newFakeCosServer(objects map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if key == "" && r.Method == http.MethodGet {
+			prefix := r.URL.Query().Get("prefix")
+			var contents, commonPrefixes []string
+			seen := make(map[string]bool)
+			for k, v := range objects {
+				if !strings.HasPrefix(k, prefix) {
+					continue
+				}
+				rest := k[len(prefix):]
+				if i := strings.Index(rest, "/"); i >= 0 {
+					dir := prefix + rest[:i+1]
+					if !seen[dir] {
+						seen[dir] = true
+						commonPrefixes = append(commonPrefixes, "<CommonPrefixes><Prefix>"+url.QueryEscape(dir)+"</Prefix></CommonPrefixes>")
+					}
+					continue
+				}
+				contents = append(contents, fmt.Sprintf("<Contents><Key>%s</Key><Size>%d</Size><LastModified>2024-01-01T00:00:00.000Z</LastModified></Contents>", url.QueryEscape(k), len(v)))
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			fmt.Fprintf(w, "<ListBucketResult><IsTruncated>false</IsTruncated>%s%s</ListBucketResult>", strings.Join(contents, ""), strings.Join(commonPrefixes, ""))
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			content, ok := objects[key]
+			if !ok {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, "<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>")
+				return
+			}
+			http.ServeContent(w, r, key, time.Time{}, strings.NewReader(content))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[key] = string(body)
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("x-cos-hash-crc64ecma", strconv.FormatUint(crc64.Checksum(body, crc64.MakeTable(crc64.ECMA)), 10))
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestServeHandler(t *testing.T) {
+	fmt.Println("TestServeHandler")
+	objects := map[string]string{
+		"data/a.txt":     "hello world",
+		"data/dir/b.txt": "b",
+		"other.txt":      "other",
+	}
+	server := newFakeCosServer(objects)
+	defer server.Close()
+	bucketUrl, _ := url.Parse(server.URL)
+	c := cos.NewClient(&cos.BaseURL{BucketURL: bucketUrl}, &http.Client{})
+
+	do := func(handler http.Handler, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	Convey("test serve handler against fake cos", t, func() {
+		handler := util.NewServeHandler(c, "data/", util.ServeOptions{})
+		Convey("get object", func() {
+			rec := do(handler, http.MethodGet, "/a.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Body.String(), ShouldEqual, "hello world")
+		})
+		Convey("get range", func() {
+			rec := do(handler, http.MethodGet, "/a.txt", "", map[string]string{"Range": "bytes=6-10"})
+			So(rec.Code, ShouldEqual, http.StatusPartialContent)
+			So(rec.Body.String(), ShouldEqual, "world")
+			So(rec.Header().Get("Content-Range"), ShouldEqual, "bytes 6-10/11")
+		})
+		Convey("head object", func() {
+			rec := do(handler, http.MethodHead, "/a.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Length"), ShouldEqual, "11")
+			So(rec.Body.Len(), ShouldEqual, 0)
+		})
+		Convey("list directory", func() {
+			rec := do(handler, http.MethodGet, "/", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Body.String(), ShouldContainSubstring, `href="a.txt"`)
+			So(rec.Body.String(), ShouldContainSubstring, `href="dir/"`)
+			So(rec.Body.String(), ShouldNotContainSubstring, "other.txt")
+		})
+		Convey("redirect directory", func() {
+			rec := do(handler, http.MethodGet, "/dir", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusMovedPermanently)
+			So(rec.Header().Get("Location"), ShouldEqual, "/dir/")
+		})
+		Convey("not found", func() {
+			rec := do(handler, http.MethodGet, "/missing.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+		})
+		Convey("not escape prefix", func() {
+			rec := do(handler, http.MethodGet, "/../other.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+		})
+		Convey("read-only", func() {
+			rec := do(handler, http.MethodPut, "/c.txt", "c", nil)
+			So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+			rec = do(handler, http.MethodDelete, "/a.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+		})
+		Convey("writable", func() {
+			handler := util.NewServeHandler(c, "data/", util.ServeOptions{Writable: true})
+			rec := do(handler, http.MethodPut, "/c.txt", "c", nil)
+			So(rec.Code, ShouldEqual, http.StatusCreated)
+			So(objects["data/c.txt"], ShouldEqual, "c")
+			rec = do(handler, http.MethodDelete, "/c.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusNoContent)
+			So(objects, ShouldNotContainKey, "data/c.txt")
+		})
+		Convey("basic auth", func() {
+			handler := util.NewServeHandler(c, "data/", util.ServeOptions{User: "admin", Password: "123"})
+			rec := do(handler, http.MethodGet, "/a.txt", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+			So(rec.Header().Get("WWW-Authenticate"), ShouldNotBeEmpty)
+			req := httptest.NewRequest(http.MethodGet, "/a.txt", nil)
+			req.SetBasicAuth("admin", "123")
+			rec = httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+		Convey("token", func() {
+			handler := util.NewServeHandler(c, "data/", util.ServeOptions{Token: "abc"})
+			rec := do(handler, http.MethodGet, "/a.txt", "", map[string]string{"Authorization": "Bearer wrong"})
+			So(rec.Code, ShouldEqual, http.StatusUnauthorized)
+			rec = do(handler, http.MethodGet, "/a.txt", "", map[string]string{"Authorization": "Bearer abc"})
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			rec = do(handler, http.MethodGet, "/a.txt?token=abc", "", nil)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
@@ -0,0 +1,455 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"coscli/util"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// inShell 是否处于交互式shell中，shell中不重复加载配置文件
+var inShell bool
+
+// 参数只能为cos路径的命令，shell中的相对路径按当前目录解析
+var shellCosPathCommands = map[string]bool{
+	"ls":                 true,
+	"du":                 true,
+	"lsdu":               true,
+	"rm":                 true,
+	"restore":            true,
+	"cat":                true,
+	"lsparts":            true,
+	"abort":              true,
+	"transition":         true,
+	"replication-status": true,
+}
+
+// 未指定路径时默认使用当前目录的命令
+var shellDefaultPathCommands = map[string]bool{
+	"ls":      true,
+	"du":      true,
+	"lsdu":    true,
+	"lsparts": true,
+	"abort":   true,
+}
+
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Start an interactive shell",
+	Long: `Start an interactive shell
+
+Inside the shell, all coscli commands can be used without the "coscli" prefix.
+Use "cd" and "pwd" to navigate cos:// paths; relative paths of commands which
+only accept cos paths (ls, du, rm, cat, restore ...) are resolved against the
+current directory. Other commands (cp, sync ...) accept "cos:<relative-path>".
+
+Format:
+  ./coscli shell
+
+Example:
+  ./coscli shell
+  coscli> cd cos://examplebucket/test/
+  coscli:cos://examplebucket/test/> ls
+  coscli:cos://examplebucket/test/> cp cos:example.txt ~/example.txt`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := &cosShell{
+			baseParam: param,
+			out:       cmd.OutOrStdout(),
+		}
+		return s.run(cmd.InOrStdin())
+	},
+}
+
+type cosShell struct {
+	cwd       string
+	baseParam util.Param
+	out       io.Writer
+	history   []string
+}
+
+func (s *cosShell) prompt() string {
+	if s.cwd == "" {
+		return "coscli> "
+	}
+	return fmt.Sprintf("coscli:%s> ", s.cwd)
+}
+
+func (s *cosShell) run(in io.Reader) error {
+	util.EnableSessionCache()
+	// 由每条命令单独监听中断信号，避免中断一条命令后退出shell
+	stopInterrupt()
+	inShell = true
+	defer func() {
+		inShell = false
+	}()
+
+	// 非终端输入（如管道）按行执行，不支持补全
+	f, ok := in.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			if s.execLine(scanner.Text()) {
+				break
+			}
+		}
+		return scanner.Err()
+	}
+
+	fd := int(f.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, oldState)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, s.out}, s.prompt())
+	t.AutoCompleteCallback = s.complete
+
+	for {
+		line, err := t.ReadLine()
+		if err == io.EOF {
+			fmt.Fprintln(t)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 执行命令时恢复终端模式，保证命令输出正常换行
+		term.Restore(fd, oldState)
+		exit := s.execLine(line)
+		if _, err = term.MakeRaw(fd); err != nil {
+			return err
+		}
+		if exit {
+			return nil
+		}
+		t.SetPrompt(s.prompt())
+	}
+}
+
+// execLine 执行一行输入，返回是否退出shell
+func (s *cosShell) execLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+	s.history = append(s.history, line)
+
+	args, err := splitShellArgs(line)
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+		return false
+	}
+	if args[0] == "coscli" {
+		args = args[1:]
+		if len(args) == 0 {
+			return false
+		}
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "pwd":
+		fmt.Fprintln(s.out, s.cwd)
+		return false
+	case "cd":
+		target := ""
+		if len(args) > 1 {
+			target = args[1]
+		}
+		if err = s.cd(target); err != nil {
+			fmt.Fprintf(s.out, "Error: %v\n", err)
+		}
+		return false
+	case "history":
+		for i, h := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, h)
+		}
+		return false
+	case "shell":
+		fmt.Fprintln(s.out, "Error: already in coscli shell")
+		return false
+	}
+
+	args = s.resolveArgs(args)
+	resetShellFlags(rootCmd)
+	rootCmd.SetArgs(args)
+	ctx, stop := util.NewInterruptContext(context.Background())
+	err = rootCmd.ExecuteContext(ctx)
+	stop()
+	// 恢复启动shell时的全局参数
+	param = s.baseParam
+	if err != nil {
+		fmt.Fprintf(s.out, "Error: %v\n", err)
+	}
+	return false
+}
+
+func (s *cosShell) cd(target string) error {
+	if target == "" || target == util.SchemePrefix || target == "/" {
+		s.cwd = ""
+		return nil
+	}
+	cosPath, ok := s.resolvePath(target, true)
+	if !ok {
+		return fmt.Errorf("cd only support cos path")
+	}
+	if cosPath == util.SchemePrefix {
+		s.cwd = ""
+		return nil
+	}
+	if !strings.HasSuffix(cosPath, util.CosSeparator) {
+		cosPath += util.CosSeparator
+	}
+	s.cwd = cosPath
+	return nil
+}
+
+// resolvePath 将相对路径解析为cos路径
+// relative为true时不带cos:前缀的路径也按cos相对路径处理
+func (s *cosShell) resolvePath(p string, relative bool) (string, bool) {
+	if strings.HasPrefix(p, util.SchemePrefix) {
+		return p, true
+	}
+	if strings.HasPrefix(p, "cos:") {
+		p = strings.TrimPrefix(p, "cos:")
+	} else if !relative {
+		return p, false
+	}
+
+	base := strings.TrimPrefix(s.cwd, util.SchemePrefix)
+	joined := path.Clean("/" + base + p)
+	if joined == "/" {
+		return util.SchemePrefix, true
+	}
+	if strings.HasSuffix(p, util.CosSeparator) || p == "." || p == ".." || strings.HasSuffix(p, "/.") || strings.HasSuffix(p, "/..") {
+		joined += util.CosSeparator
+	}
+	return util.SchemePrefix + strings.TrimPrefix(joined, "/"), true
+}
+
+// resolveArgs 解析命令参数中的相对路径
+func (s *cosShell) resolveArgs(args []string) []string {
+	sub, _, err := rootCmd.Find(args)
+	if err != nil || sub == rootCmd {
+		return args
+	}
+	cosOnly := shellCosPathCommands[sub.Name()]
+
+	resolved := []string{args[0]}
+	hasPath := false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			resolved = append(resolved, arg)
+			// 带值的参数，下一个参数为参数值
+			if !strings.Contains(arg, "=") && shellFlagNeedsValue(sub, arg) && i+1 < len(args) {
+				i++
+				resolved = append(resolved, args[i])
+			}
+			continue
+		}
+		if sub.Name() != args[0] && i == 1 && arg == sub.Name() {
+			// 子命令名称，例如 inventory analyze
+			resolved = append(resolved, arg)
+			continue
+		}
+		if p, ok := s.resolvePath(arg, cosOnly); ok {
+			arg = p
+			hasPath = true
+		}
+		resolved = append(resolved, arg)
+	}
+
+	if !hasPath && s.cwd != "" && shellDefaultPathCommands[sub.Name()] {
+		resolved = append(resolved, s.cwd)
+	}
+	return resolved
+}
+
+func shellFlagNeedsValue(c *cobra.Command, arg string) bool {
+	var flag *pflag.Flag
+	if strings.HasPrefix(arg, "--") {
+		flag = c.Flags().Lookup(strings.TrimPrefix(arg, "--"))
+		if flag == nil {
+			flag = c.InheritedFlags().Lookup(strings.TrimPrefix(arg, "--"))
+		}
+	} else {
+		name := strings.TrimPrefix(arg, "-")
+		if len(name) != 1 {
+			return false
+		}
+		flag = c.Flags().ShorthandLookup(name)
+		if flag == nil {
+			flag = c.InheritedFlags().ShorthandLookup(name)
+		}
+	}
+	return flag != nil && flag.NoOptDefVal == ""
+}
+
+// resetShellFlags 重置各命令参数为默认值，避免上一条命令的参数残留
+func resetShellFlags(c *cobra.Command) {
+	for _, sub := range c.Commands() {
+		sub.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			// 切片类型参数Set(DefValue)会得到"[]"，需直接清空
+			if v, ok := flag.Value.(pflag.SliceValue); ok {
+				v.Replace(nil)
+			} else {
+				flag.Value.Set(flag.DefValue)
+			}
+			flag.Changed = false
+		})
+		resetShellFlags(sub)
+	}
+}
+
+// splitShellArgs 按空格拆分参数，支持单双引号及转义
+func splitShellArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// complete tab补全命令、存储桶别名、对象及本地路径
+func (s *cosShell) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	start := strings.LastIndexAny(head, " \t") + 1
+	word := head[start:]
+
+	var candidates []string
+	if strings.TrimSpace(head[:start]) == "" {
+		candidates = s.completeCommand(word)
+	} else {
+		fields := strings.Fields(head[:start])
+		candidates = s.completePath(word, shellCosPathCommands[fields[0]])
+	}
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	common := longestCommonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(common, util.CosSeparator) && !strings.HasSuffix(common, string(filepath.Separator)) {
+		common += " "
+	}
+	if len(common) <= len(word) {
+		return "", 0, false
+	}
+	return head[:start] + common + line[pos:], start + len(common), true
+}
+
+func (s *cosShell) completeCommand(word string) []string {
+	var candidates []string
+	for _, name := range []string{"cd", "pwd", "history", "exit"} {
+		if strings.HasPrefix(name, word) {
+			candidates = append(candidates, name)
+		}
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() != "shell" && strings.HasPrefix(c.Name(), word) {
+			candidates = append(candidates, c.Name())
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+func (s *cosShell) completePath(word string, cosOnly bool) []string {
+	cosPath, ok := s.resolvePath(word, cosOnly)
+	if !ok {
+		return completeLocalPath(word)
+	}
+
+	// 补全结果保持用户输入的形式
+	display := func(p string) string {
+		if strings.HasPrefix(word, util.SchemePrefix) {
+			return p
+		}
+		rel := strings.TrimPrefix(p, s.cwd)
+		if strings.HasPrefix(word, "cos:") {
+			return "cos:" + rel
+		}
+		return rel
+	}
+
+	var candidates []string
+	for _, p := range completeCosPath(cosPath) {
+		// 相对路径只补全当前目录下的内容
+		if !strings.HasPrefix(word, util.SchemePrefix) && !strings.HasPrefix(p, s.cwd) {
+			continue
+		}
+		candidates = append(candidates, display(p))
+	}
+	return candidates
+}
+
+func longestCommonPrefix(items []string) string {
+	prefix := items[0]
+	for _, item := range items[1:] {
+		for !strings.HasPrefix(item, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
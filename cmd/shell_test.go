@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestShellCmd(t *testing.T) {
+	fmt.Println("TestShellCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	defer cmd.SetIn(nil)
+	defer cmd.SetOut(nil)
+
+	Convey("test coscli shell", t, func() {
+		Convey("success", func() {
+			Convey("cd and pwd", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				input := fmt.Sprintf("cd cos://%s/a/b/\npwd\ncd ../c\npwd\ncd ..\ncd ..\npwd\nexit\n", testAlias)
+				cmd.SetIn(strings.NewReader(input))
+				cmd.SetOut(&out)
+				cmd.SetArgs([]string{"shell"})
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s/a/b/\n", testAlias))
+				So(out.String(), ShouldContainSubstring, fmt.Sprintf("cos://%s/a/c/\n", testAlias))
+				So(out.String(), ShouldContainSubstring, "\n\n")
+			})
+			Convey("run commands", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				input := fmt.Sprintf("cd %s\nls\nls -r --limit 10\ndu\nexit\n", testAlias)
+				cmd.SetIn(strings.NewReader(input))
+				cmd.SetOut(&out)
+				cmd.SetArgs([]string{"shell"})
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldNotContainSubstring, "Error")
+			})
+		})
+		Convey("fail", func() {
+			Convey("unknown command", func() {
+				clearCmd()
+				cmd := rootCmd
+				var out bytes.Buffer
+				cmd.SetIn(strings.NewReader("unknown\nshell\ncd \"unterminated\n"))
+				cmd.SetOut(&out)
+				cmd.SetArgs([]string{"shell"})
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+				So(out.String(), ShouldContainSubstring, "unknown command")
+				So(out.String(), ShouldContainSubstring, "already in coscli shell")
+				So(out.String(), ShouldContainSubstring, "unterminated quote")
+			})
+		})
+	})
+}
@@ -1,40 +1,66 @@
 package cmd
 
 import (
-	"context"
 	"coscli/util"
 	"fmt"
 	logger "github.com/sirupsen/logrus"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/tencentyun/cos-go-sdk-v5"
 )
 
 var signurlCmd = &cobra.Command{
 	Use:   "signurl",
-	Short: "Gets the signed download URL",
-	Long: `Gets the signed download URL
+	Short: "Gets the signed URL",
+	Long: `Gets the signed URL
+
+Headers and query parameters given by --header and --query are signed into
+the URL, the request using the URL must carry the same headers.
 
 Format:
   ./coscli signurl cos://<bucket-name>/<key> [flags]
 
 Example:
-  ./coscli signurl cos://examplebucket/test.jpg -t 100`,
+  ./coscli signurl cos://examplebucket/test.jpg -t 100
+  ./coscli signurl cos://examplebucket/upload/test.jpg --method PUT --header "Content-Type:image/jpeg"
+  ./coscli signurl cos://examplebucket/test.jpg --query "response-content-disposition=attachment; filename=test.jpg"
+  ./coscli signurl cos://examplebucket/test/ -r --include ".*\.jpg$" --output json --output-file urls.json`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		time, _ := cmd.Flags().GetInt("time")
 		simpleOutput, _ := cmd.Flags().GetBool("simple-output")
-		var err error
-		if util.IsCosPath(args[0]) {
-			err = GetSignedURL(args[0], time, simpleOutput)
-		} else {
+		method, _ := cmd.Flags().GetString("method")
+		headers, _ := cmd.Flags().GetStringArray("header")
+		queries, _ := cmd.Flags().GetStringArray("query")
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		output, _ := cmd.Flags().GetString("output")
+		outputFile, _ := cmd.Flags().GetString("output-file")
+
+		if !util.IsCosPath(args[0]) {
 			return fmt.Errorf("cospath needs to contain cos://")
 		}
 
-		return err
+		opt, err := signURLOptions(method, time, headers, queries)
+		if err != nil {
+			return err
+		}
+
+		if !recursive {
+			return GetSignedURL(args[0], opt, simpleOutput)
+		}
+
+		if output != util.OutputFormatCsv && output != util.OutputFormatJson {
+			return fmt.Errorf("output '%s' is not supported, valid outputs are 'csv' and 'json'", output)
+		}
+		_, filters := util.GetFilter(include, exclude)
+		return GetSignedURLs(args[0], opt, filters, output, outputFile)
 	},
 }
 
@@ -43,22 +69,59 @@ func init() {
 
 	signurlCmd.Flags().IntP("time", "t", 10000, "Set the validity time of the signature(Default 10000)")
 	signurlCmd.Flags().BoolP("simple-output", "", false, "Set simple output mode")
+	signurlCmd.Flags().String("method", http.MethodGet, "HTTP method of the signed URL, valid values are GET, PUT, HEAD and DELETE")
+	signurlCmd.Flags().StringArray("header", []string{}, "Header signed into the URL, in the format of \"key:value\", can be specified multiple times")
+	signurlCmd.Flags().StringArray("query", []string{}, "Query parameter signed into the URL, in the format of \"key=value\", can be specified multiple times")
+	signurlCmd.Flags().BoolP("recursive", "r", false, "Sign all objects under the prefix")
+	signurlCmd.Flags().String("include", "", "Sign files that meet the specified criteria")
+	signurlCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
+	signurlCmd.Flags().String("output", util.OutputFormatCsv, "Output format of recursive signing, csv or json")
+	signurlCmd.Flags().String("output-file", "", "Write the result of recursive signing to the file instead of stdout")
+}
+
+// signURLOptions 解析签名方法、有效期及需要签入url的头部和参数
+func signURLOptions(method string, t int, headers, queries []string) (util.SignURLOptions, error) {
+	opt := util.SignURLOptions{
+		Method: strings.ToUpper(method),
+		Expire: time.Second * time.Duration(t),
+		Query:  url.Values{},
+		Header: http.Header{},
+	}
+
+	switch opt.Method {
+	case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+	default:
+		return opt, fmt.Errorf("method '%s' is not supported, valid methods are GET, PUT, HEAD and DELETE", method)
+	}
+
+	for _, header := range headers {
+		kv := strings.SplitN(header, ":", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return opt, fmt.Errorf("header '%s' format error, should be \"key:value\"", header)
+		}
+		opt.Header.Add(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+	}
+
+	for _, query := range queries {
+		kv := strings.SplitN(query, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return opt, fmt.Errorf("query '%s' format error, should be \"key=value\"", query)
+		}
+		opt.Query.Add(kv[0], kv[1])
+	}
+
+	return opt, nil
 }
 
 // GetSignedURL 生成签名url
-func GetSignedURL(path string, t int, simpleOutput bool) error {
+func GetSignedURL(path string, opt util.SignURLOptions, simpleOutput bool) error {
 	bucketName, cosPath := util.ParsePath(path)
 	c, err := util.NewClient(&config, &param, bucketName)
 	if err != nil {
 		return err
 	}
 
-	opt := &cos.PresignedURLOptions{
-		Query:  &url.Values{},
-		Header: &http.Header{},
-	}
-
-	presignedURL, err := c.Object.GetPresignedURL2(context.Background(), http.MethodGet, cosPath, time.Second*time.Duration(t), opt)
+	presignedURL, err := util.SignURL(c, cosPath, opt)
 	if err != nil {
 		return err
 	}
@@ -72,3 +135,35 @@ func GetSignedURL(path string, t int, simpleOutput bool) error {
 
 	return nil
 }
+
+// GetSignedURLs 批量生成前缀下对象的签名url
+func GetSignedURLs(path string, opt util.SignURLOptions, filters []util.FilterOptionType, output, outputFile string) error {
+	cosUrl, err := util.FormatUrl(path)
+	if err != nil {
+		return fmt.Errorf("cos url format error:%v", err)
+	}
+
+	bucketName := cosUrl.(*util.CosUrl).Bucket
+	c, err := util.NewClient(&config, &param, bucketName)
+	if err != nil {
+		return err
+	}
+
+	// 获取桶类型
+	bucketType, err := util.GetBucketType(c, &param, &config, bucketName)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	return util.SignObjectURLs(c, cosUrl, bucketType, filters, opt, output, w)
+}
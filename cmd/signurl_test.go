@@ -5,6 +5,7 @@ import (
 	"coscli/util"
 	"fmt"
 	"net/url"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -41,6 +42,32 @@ func TestSignurlCmd(t *testing.T) {
 			e := cmd.Execute()
 			So(e, ShouldBeNil)
 		})
+		Convey("put with header and query", func() {
+			clearCmd()
+			cmd := rootCmd
+			args := []string{"signurl", fmt.Sprintf("cos://%s/upload/1", testAlias), "--method", "put",
+				"--header", "Content-Type:text/plain", "--query", "response-content-disposition=attachment", "--simple-output"}
+			cmd.SetArgs(args)
+			e := cmd.Execute()
+			So(e, ShouldBeNil)
+		})
+		Convey("recursive", func() {
+			for _, output := range []string{"csv", "json"} {
+				Convey(output, func() {
+					clearCmd()
+					cmd := rootCmd
+					outputFile := fmt.Sprintf("%s/urls.%s", testDir, output)
+					args := []string{"signurl", fmt.Sprintf("cos://%s/", testAlias), "-r", "--include", "^0$",
+						"--output", output, "--output-file", outputFile}
+					cmd.SetArgs(args)
+					e := cmd.Execute()
+					So(e, ShouldBeNil)
+					data, err := os.ReadFile(outputFile)
+					So(err, ShouldBeNil)
+					So(string(data), ShouldContainSubstring, "q-signature")
+				})
+			}
+		})
 		Convey("failed", func() {
 			Convey("Not enough arguments", func() {
 				clearCmd()
@@ -61,6 +88,42 @@ func TestSignurlCmd(t *testing.T) {
 				fmt.Printf(" : %v", e)
 				So(e, ShouldBeError)
 			})
+			Convey("invalid method", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"signurl", fmt.Sprintf("cos://%s/0", testAlias), "--method", "POST"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid header", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"signurl", fmt.Sprintf("cos://%s/0", testAlias), "--header", "Content-Type"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid query", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"signurl", fmt.Sprintf("cos://%s/0", testAlias), "--query", "=attachment"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid output", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"signurl", fmt.Sprintf("cos://%s/", testAlias), "-r", "--output", "table"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("New Client", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -56,6 +56,11 @@ Example:
 		disableAllSymlink, _ := cmd.Flags().GetBool("disable-all-symlink")
 		enableSymlinkDir, _ := cmd.Flags().GetBool("enable-symlink-dir")
 		disableCrc64, _ := cmd.Flags().GetBool("disable-crc64")
+		atomic, _ := cmd.Flags().GetBool("atomic")
+		clientEncrypt, _ := cmd.Flags().GetString("client-encrypt")
+		compress, _ := cmd.Flags().GetString("compress")
+		compressExt, _ := cmd.Flags().GetBool("compress-ext")
+		decompress, _ := cmd.Flags().GetBool("decompress")
 		disableChecksum, _ := cmd.Flags().GetBool("disable-checksum")
 		disableLongLinks, _ := cmd.Flags().GetBool("disable-long-links")
 		longLinksNums, _ := cmd.Flags().GetInt("long-links-nums")
@@ -144,6 +149,37 @@ Example:
 			return fmt.Errorf("delete can only use with --recursive option")
 		}
 
+		if compress != "" {
+			if err = util.CheckCompressFormat(compress); err != nil {
+				return err
+			}
+			if !(srcUrl.IsFileUrl() && destUrl.IsCosUrl()) {
+				return fmt.Errorf("--compress only work with upload")
+			}
+			if clientEncrypt != "" {
+				return fmt.Errorf("--compress can not be used with --client-encrypt")
+			}
+			if compressExt && delete {
+				return fmt.Errorf("--compress-ext can not be used with --delete")
+			}
+		} else if compressExt {
+			return fmt.Errorf("--compress-ext only work with --compress")
+		}
+		if decompress && !(srcUrl.IsCosUrl() && destUrl.IsFileUrl()) {
+			return fmt.Errorf("--decompress only work with download")
+		}
+
+		var clientEncryptKey []byte
+		if clientEncrypt != "" {
+			if srcUrl.IsCosUrl() && destUrl.IsCosUrl() {
+				return fmt.Errorf("--client-encrypt only work with upload or download")
+			}
+			clientEncryptKey, err = util.LoadMasterKey(clientEncrypt)
+			if err != nil {
+				return err
+			}
+		}
+
 		fo := &util.FileOperations{
 			Operation: util.Operation{
 				Recursive:         recursive,
@@ -165,6 +201,11 @@ Example:
 				DisableAllSymlink: disableAllSymlink,
 				EnableSymlinkDir:  enableSymlinkDir,
 				DisableCrc64:      disableCrc64,
+				Atomic:            atomic,
+				ClientEncryptKey:  clientEncryptKey,
+				Compress:          compress,
+				CompressExt:       compressExt,
+				Decompress:        decompress,
 				DisableChecksum:   disableChecksum,
 				DisableLongLinks:  disableLongLinks,
 				LongLinksNums:     longLinksNums,
@@ -198,6 +239,7 @@ Example:
 			CpType:        getCommandType(srcUrl, destUrl),
 			Command:       util.CommandSync,
 			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
 		}
 
 		// 快照db实例化
@@ -205,6 +247,7 @@ Example:
 		if err != nil {
 			return err
 		}
+		defer util.CloseSnapshotDb(fo)
 
 		srcPath := srcUrl.ToString()
 		destPath := destUrl.ToString()
@@ -346,6 +389,11 @@ Example:
 		endT := time.Now().UnixNano() / 1000 / 1000
 		util.PrintCostTime(startT, endT)
 
+		if fo.Interrupted() {
+			logger.Warningf("%s %s to %s interrupted, %s", operate, srcPath, destPath, fo.Monitor.GetFinishInfo())
+			return util.ErrInterrupted
+		}
+
 		if fo.Monitor.ErrNum > 0 {
 			logger.Warningf("%s %s to %s %s", operate, srcPath, destPath, fo.Monitor.GetFinishInfo())
 			os.Exit(2)
@@ -402,6 +450,11 @@ func init() {
 	syncCmd.Flags().Bool("disable-all-symlink", true, "Ignore all symbolic link subfiles and symbolic link subdirectories when uploading, not uploaded by default")
 	syncCmd.Flags().Bool("enable-symlink-dir", false, "Upload linked subdirectories, not uploaded by default")
 	syncCmd.Flags().Bool("disable-crc64", false, "Disable CRC64 data validation. By default, coscli enables CRC64 validation for data transfer")
+	syncCmd.Flags().Bool("atomic", true, "Download into a temporary file (.coscli-tmp-*) and rename it into place after verification, so an interrupted or corrupted download never leaves a truncated file under the final name")
+	syncCmd.Flags().String("client-encrypt", "", "Path of a 32-byte master key file (raw, hex or base64). Objects are encrypted locally with a per-object data key (AES-256-GCM) before upload and decrypted transparently on download")
+	syncCmd.Flags().String("compress", "", "Compress files while uploading, optional values: gzip and zstd. Content-Encoding and x-cos-meta-original-size are set on the object")
+	syncCmd.Flags().Bool("compress-ext", false, "Append the extension of --compress (.gz or .zst) to the object key")
+	syncCmd.Flags().Bool("decompress", false, "Decompress gzip or zstd objects according to their Content-Encoding while downloading")
 	syncCmd.Flags().Bool("disable-checksum", true, "Disable overall CRC64 checksum, only validate fragments")
 	syncCmd.Flags().Bool("disable-long-links", false, "Disable long links, use short links")
 	syncCmd.Flags().Bool("long-links-nums", false, "The long connection quantity parameter, if 0 or not provided, defaults to the concurrent file count.")
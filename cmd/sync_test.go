@@ -31,6 +31,28 @@ func TestSyncCmd(t *testing.T) {
 	defer delDir(testDir)
 	Convey("Test coscli sync", t, func() {
 		Convey("upload", func() {
+			Convey("压缩上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/big-file/1", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"sync", localFileName, cosFileName, "--compress", "zstd", "--compress-ext"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("客户端加密上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				keyFile := fmt.Sprintf("%s/master.key", testDir)
+				genMasterKey(keyFile)
+				localFileName := fmt.Sprintf("%s/big-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"sync", localFileName, cosFileName, "--client-encrypt", keyFile, "--part-size", "1"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 			Convey("上传单个小文件并关闭crc64校验", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -195,6 +217,37 @@ func TestSyncCmd(t *testing.T) {
 			})
 		})
 		Convey("Download", func() {
+			Convey("解压下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/compress", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress.zst")
+				args := []string{"sync", cosFileName, localFileName, "--decompress"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("客户端加密下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				keyFile := fmt.Sprintf("%s/master.key", testDir)
+				localFileName := fmt.Sprintf("%s/download/client-encrypt", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"sync", cosFileName, localFileName, "--client-encrypt", keyFile}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("关闭原子下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/non-atomic", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias2, "single-copy-small")
+				args := []string{"sync", cosFileName, localFileName, "--atomic=false"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
 			Convey("下载单个小文件", func() {
 				clearCmd()
 				cmd := rootCmd
@@ -257,6 +310,82 @@ func TestSyncCmd(t *testing.T) {
 			})
 		})
 		Convey("fail", func() {
+			Convey("compress格式非法", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"sync", localFileName, cosFileName, "--compress", "bzip2"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("compress用于下载", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/download/compress", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"sync", cosFileName, localFileName, "--compress", "gzip"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("decompress用于上传", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"sync", localFileName, cosFileName, "--decompress"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("compress-ext未指定compress", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress")
+				args := []string{"sync", localFileName, cosFileName, "--compress-ext"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("compress-ext与delete同时使用", func() {
+				clearCmd()
+				cmd := rootCmd
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "compress/")
+				args := []string{"sync", testDir, cosFileName, "-r", "--compress", "gzip", "--compress-ext", "--delete", "--force"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("client-encrypt密钥文件不存在", func() {
+				clearCmd()
+				cmd := rootCmd
+				localFileName := fmt.Sprintf("%s/small-file/0", testDir)
+				cosFileName := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				args := []string{"sync", localFileName, cosFileName, "--client-encrypt", "not-exist.key"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("client-encrypt用于cos间复制", func() {
+				clearCmd()
+				cmd := rootCmd
+				srcPath := fmt.Sprintf("cos://%s/%s", testAlias1, "client-encrypt")
+				dstPath := fmt.Sprintf("cos://%s/%s", testAlias2, "client-encrypt")
+				args := []string{"sync", srcPath, dstPath, "--client-encrypt", fmt.Sprintf("%s/master.key", testDir)}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
 			Convey("encryptionType非法", func() {
 				clearCmd()
 				cmd := rootCmd
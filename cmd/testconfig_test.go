@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"encoding/hex"
 	"fmt"
 	"io"
 	"math/rand"
@@ -291,6 +292,15 @@ func genDir(dirName string, num int) {
 	}
 }
 
+// genMasterKey 生成客户端加密使用的主密钥文件
+func genMasterKey(path string) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600); err != nil {
+		logger.Errorln("genMasterKey error: 生成主密钥失败")
+	}
+}
+
 func delDir(dirName string) {
 	logger.Infoln(fmt.Sprintf("删除测试临时文件夹：%s", dirName))
 	if err := os.RemoveAll(dirName); err != nil {
@@ -306,6 +316,10 @@ func clearCmd() {
 	// 重置子命令的状态
 	for _, subCmd := range rootCmd.Commands() {
 		subCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			if v, ok := flag.Value.(pflag.SliceValue); ok {
+				v.Replace(nil)
+				return
+			}
 			flag.Value.Set(flag.DefValue)
 		})
 	}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var transitionCmd = &cobra.Command{
+	Use:   "transition",
+	Short: "Change the storage class of objects",
+	Long: `Change the storage class of objects
+
+Format:
+  ./coscli transition cos://<bucket-name>[/<prefix>] --storage-class <class> [flags]
+
+Example:
+  ./coscli transition cos://examplebucket/test/ -r --storage-class DEEP_ARCHIVE --older-than 90d --min-size 1MB
+  ./coscli transition cos://examplebucket/test/ -r --storage-class ARCHIVE --include ".*\.log$" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+		include, _ := cmd.Flags().GetString("include")
+		exclude, _ := cmd.Flags().GetString("exclude")
+		minSizeStr, _ := cmd.Flags().GetString("min-size")
+		maxSizeStr, _ := cmd.Flags().GetString("max-size")
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+		newerThanStr, _ := cmd.Flags().GetString("newer-than")
+		routines, _ := cmd.Flags().GetInt("routines")
+		threadNum, _ := cmd.Flags().GetInt("thread-num")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		force, _ := cmd.Flags().GetBool("force")
+		failOutput, _ := cmd.Flags().GetBool("fail-output")
+		failOutputPath, _ := cmd.Flags().GetString("fail-output-path")
+
+		if !isValidStorageClass(storageClass) {
+			return fmt.Errorf("invalid storage class: '%s'", storageClass)
+		}
+
+		if routines < 1 || routines > 1000 {
+			return fmt.Errorf("Flag --routines should in range 1~1000")
+		}
+
+		var minSize, maxSize int64
+		var olderThan, newerThan time.Duration
+		var err error
+		if minSizeStr != "" {
+			if minSize, err = util.ParseSize(minSizeStr); err != nil {
+				return err
+			}
+		}
+		if maxSizeStr != "" {
+			if maxSize, err = util.ParseSize(maxSizeStr); err != nil {
+				return err
+			}
+		}
+		if olderThanStr != "" {
+			if olderThan, err = util.ParseAge(olderThanStr); err != nil {
+				return err
+			}
+		}
+		if newerThanStr != "" {
+			if newerThan, err = util.ParseAge(newerThanStr); err != nil {
+				return err
+			}
+		}
+
+		_, filters := util.GetFilter(include, exclude)
+
+		fo := &util.FileOperations{
+			Operation: util.Operation{
+				Recursive:      recursive,
+				Filters:        filters,
+				StorageClass:   storageClass,
+				MinSize:        minSize,
+				MaxSize:        maxSize,
+				OlderThan:      olderThan,
+				NewerThan:      newerThan,
+				Routines:       routines,
+				ThreadNum:      threadNum,
+				DryRun:         dryRun,
+				Force:          force,
+				FailOutput:     failOutput,
+				FailOutputPath: failOutputPath,
+			},
+			Config:        &config,
+			Param:         &param,
+			ErrOutput:     &util.ErrOutput{},
+			Command:       util.CommandTransition,
+			OutPutDirName: time.Now().Format("20060102_150405"),
+			Ctx:           commandContext(),
+		}
+
+		cosUrl, err := util.FormatUrl(args[0])
+		if err != nil {
+			return fmt.Errorf("cos url format error:%v", err)
+		}
+		if !cosUrl.IsCosUrl() {
+			return fmt.Errorf("cospath needs to contain %s", util.SchemePrefix)
+		}
+
+		bucketName := cosUrl.(*util.CosUrl).Bucket
+		c, err := util.NewClient(&config, &param, bucketName)
+		if err != nil {
+			return err
+		}
+
+		if !recursive {
+			return util.TransitionSingleObject(c, cosUrl, fo)
+		}
+
+		// 获取桶类型
+		bucketType, err := util.GetBucketType(c, fo.Param, fo.Config, bucketName)
+		if err != nil {
+			return err
+		}
+		if bucketType == util.BucketTypeOfs {
+			return fmt.Errorf("transition is not supported for %s bucket", util.BucketTypeOfs)
+		}
+
+		return util.TransitionObjects(c, cosUrl, fo)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(transitionCmd)
+
+	transitionCmd.Flags().BoolP("recursive", "r", false, "Transition objects recursively")
+	transitionCmd.Flags().String("storage-class", "", "Specifying the target storage class")
+	transitionCmd.Flags().String("include", "", "Include files that meet the specified criteria")
+	transitionCmd.Flags().String("exclude", "", "Exclude files that meet the specified criteria")
+	transitionCmd.Flags().String("min-size", "", "Only transition objects not smaller than the specified size, e.g. 1MB")
+	transitionCmd.Flags().String("max-size", "", "Only transition objects not larger than the specified size, e.g. 1GB")
+	transitionCmd.Flags().String("older-than", "", "Only transition objects last modified before the specified age, e.g. 30d or 12h")
+	transitionCmd.Flags().String("newer-than", "", "Only transition objects last modified within the specified age, e.g. 30d or 12h")
+	transitionCmd.Flags().Int("routines", 3, "Specifies the number of files concurrent transition")
+	transitionCmd.Flags().Int("thread-num", 5, "Specifies the number of partition concurrent transition threads for large objects")
+	transitionCmd.Flags().Bool("dry-run", false, "Only print the summary of objects to be transitioned")
+	transitionCmd.Flags().BoolP("force", "f", false, "Force transition without confirmation")
+	transitionCmd.Flags().Bool("fail-output", true, "This option determines whether error output for failed file transition is enabled. If enabled, any error messages for failed file transitions will be recorded in a file within the specified directory (if not specified, the default directory is coscli_output). If disabled, only the number of error files will be output to the console.")
+	transitionCmd.Flags().String("fail-output-path", "coscli_output", "This option specifies the error output folder where error messages for file transition failures will be recorded. By providing a custom folder path, you can control the location and name of the error output folder. If this option is not set, the default error log folder (coscli_output) will be used.")
+
+	_ = transitionCmd.MarkFlagRequired("storage-class")
+}
+
+func isValidStorageClass(storageClass string) bool {
+	switch storageClass {
+	case util.Standard, util.StandardIA, util.IntelligentTiering, util.Archive, util.DeepArchive,
+		util.MAZStandard, util.MAZStandardIA, util.MAZIntelligentTiering, util.MAZArchive,
+		util.Cold, util.MAZCold:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"coscli/util"
+	"fmt"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+func TestTransitionCmd(t *testing.T) {
+	fmt.Println("TestTransitionCmd")
+	testBucket = randStr(8)
+	testAlias = testBucket + "-alias"
+	setUp(testBucket, testAlias, testEndpoint, false, false)
+	defer tearDown(testBucket, testAlias, testEndpoint, false)
+	genDir(testDir, 3)
+	defer delDir(testDir)
+	localObject := fmt.Sprintf("%s/small-file/0", testDir)
+	localFileName := fmt.Sprintf("%s/small-file", testDir)
+	cosObject := fmt.Sprintf("cos://%s", testAlias)
+	cosFileName := fmt.Sprintf("cos://%s/%s", testAlias, "multi-small")
+	clearCmd()
+	cmd := rootCmd
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	args1 := []string{"cp", localObject, cosObject}
+	args2 := []string{"cp", localFileName, cosFileName, "-r"}
+	cmd.SetArgs(args1)
+	cmd.Execute()
+	clearCmd()
+	cmd = rootCmd
+	cmd.SetArgs(args2)
+	cmd.Execute()
+	Convey("Test coscli transition", t, func() {
+		Convey("success", func() {
+			Convey("TransitionSingleObject", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition",
+					fmt.Sprintf("%s/0", cosObject), "--storage-class", "STANDARD_IA", "-f"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("TransitionSingleObject dry run", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition",
+					fmt.Sprintf("%s/1", cosObject), "--storage-class", "ARCHIVE", "--min-size", "1B", "--dry-run"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("TransitionObjects dry run", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE", "--dry-run"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+			Convey("TransitionObjects", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE", "--min-size", "1B", "--older-than", "0s", "-f"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				So(e, ShouldBeNil)
+			})
+		})
+		Convey("fail", func() {
+			Convey("Not enough arguments", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid storage class", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "invalid"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid size", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE", "--min-size", "1XB"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("invalid age", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE", "--older-than", "abc"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("not cos url", func() {
+				clearCmd()
+				cmd := rootCmd
+				args := []string{"transition", "invalid", "--storage-class", "ARCHIVE"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("New Client", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.NewClient, func(config *util.Config, param *util.Param, bucketName string, options ...*util.FileOperations) (client *cos.Client, err error) {
+					return nil, fmt.Errorf("test new client error")
+				})
+				defer patches.Reset()
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+			Convey("TransitionObjects", func() {
+				clearCmd()
+				cmd := rootCmd
+				patches := ApplyFunc(util.TransitionObjects, func(c *cos.Client, cosUrl util.StorageUrl, fo *util.FileOperations) error {
+					return fmt.Errorf("test TransitionObjects error")
+				})
+				defer patches.Reset()
+				args := []string{"transition", cosFileName, "-r", "--storage-class", "ARCHIVE", "-f"}
+				cmd.SetArgs(args)
+				e := cmd.Execute()
+				fmt.Printf(" : %v", e)
+				So(e, ShouldBeError)
+			})
+		})
+	})
+}
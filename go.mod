@@ -4,6 +4,7 @@ go 1.18
 
 require (
 	github.com/agiledragon/gomonkey/v2 v2.12.0
+	github.com/klauspost/compress v1.15.15
 	github.com/lestrrat-go/file-rotatelogs v2.4.0+incompatible
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/mozillazg/go-httpheader v0.4.0
@@ -15,12 +16,12 @@ require (
 	github.com/stretchr/testify v1.7.0
 	github.com/syndtr/goleveldb v1.0.0
 	github.com/tencentyun/cos-go-sdk-v5 v0.7.70-0.20250909083833-a714b40b9ec5
+	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/term v0.0.0-20201126162022-7de9c90e9dd1 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
 
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 单次追加上传的最大长度
+	appendMaxSize = 8 * 1024 * 1024
+	// 从输入读取数据的缓冲大小
+	appendReadSize = 64 * 1024
+)
+
+// getAppendPosition 查询对象的追加位置，对象不存在时为0
+func getAppendPosition(c *cos.Client, object string) (int, error) {
+	resp, err := c.Object.Head(context.Background(), object, nil)
+	if err != nil {
+		if cos.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if resp.Header.Get("x-cos-object-type") != "appendable" {
+		return 0, fmt.Errorf("%s is not an appendable object", object)
+	}
+	if pos := resp.Header.Get("x-cos-next-append-position"); pos != "" {
+		return strconv.Atoi(pos)
+	}
+	return int(resp.ContentLength), nil
+}
+
+// AppendObject 读取r中的数据追加上传到对象末尾，对象不存在时创建追加类型对象
+// 缓冲数据达到appendMaxSize或超过flushInterval未提交时提交一次，读到末尾或被中断时提交剩余数据
+func AppendObject(c *cos.Client, cosUrl StorageUrl, r io.Reader, flushInterval time.Duration, fo *FileOperations) error {
+	object := cosUrl.(*CosUrl).Object
+	position, err := getAppendPosition(c, object)
+	if err != nil {
+		return err
+	}
+	startPosition := position
+
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	// 返回后关闭done，读取协程不再阻塞在发送上
+	chRead := make(chan readResult, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		send := func(res readResult) bool {
+			select {
+			case chRead <- res:
+				return true
+			case <-done:
+				return false
+			}
+		}
+		for {
+			buf := make([]byte, appendReadSize)
+			n, err := r.Read(buf)
+			if n > 0 && !send(readResult{data: buf[:n]}) {
+				return
+			}
+			if err != nil {
+				send(readResult{err: err})
+				return
+			}
+		}
+	}()
+
+	// 首次创建对象时设置元数据
+	opt := &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
+			CacheControl:       fo.Operation.Meta.CacheControl,
+			ContentDisposition: fo.Operation.Meta.ContentDisposition,
+			ContentEncoding:    fo.Operation.Meta.ContentEncoding,
+			ContentType:        fo.Operation.Meta.ContentType,
+			ContentLanguage:    fo.Operation.Meta.ContentLanguage,
+			Expires:            fo.Operation.Meta.Expires,
+			XCosMetaXXX:        fo.Operation.Meta.XCosMetaXXX,
+			XOptionHeader:      &http.Header{},
+		},
+	}
+	var pending bytes.Buffer
+	flush := func() error {
+		for pending.Len() > 0 {
+			data := pending.Next(appendMaxSize)
+			if position > 0 {
+				opt = nil
+			}
+			next, resp, err := c.Object.Append(context.Background(), object, position, bytes.NewReader(data), opt)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusConflict {
+					return fmt.Errorf("append to %s at position %d conflicts with the object length, it may be appended by another writer: %v",
+						cosUrl.ToString(), position, err)
+				}
+				return fmt.Errorf("append to %s at position %d error: %v", cosUrl.ToString(), position, err)
+			}
+			position = next
+		}
+		return nil
+	}
+
+	timer := time.NewTimer(flushInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case res := <-chRead:
+			if res.err != nil {
+				if err = flush(); err != nil {
+					return err
+				}
+				if res.err != io.EOF {
+					return res.err
+				}
+				logger.Infof("Appended %s to %s, next append position: %d", FormatSize(int64(position-startPosition)), cosUrl.ToString(), position)
+				return nil
+			}
+			if pending.Len() == 0 {
+				timer.Reset(flushInterval)
+			}
+			pending.Write(res.data)
+			if pending.Len() >= appendMaxSize {
+				if err = flush(); err != nil {
+					return err
+				}
+			}
+		case <-timer.C:
+			if err = flush(); err != nil {
+				return err
+			}
+		case <-fo.Context().Done():
+			// 中断时提交已读取的数据
+			if err = flush(); err != nil {
+				return err
+			}
+			logger.Infof("Appended %s to %s, next append position: %d", FormatSize(int64(position-startPosition)), cosUrl.ToString(), position)
+			return ErrInterrupted
+		}
+	}
+}
@@ -0,0 +1,573 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	ArchiveTar   = "tar"
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+
+	// 估算归档大小时每个成员的头部开销
+	archiveEntryOverhead = 2048
+	// 解包zip时每次范围下载的大小
+	archiveReadAheadSize = 4 * 1024 * 1024
+)
+
+// CheckArchiveFormat 校验归档格式
+func CheckArchiveFormat(format string) error {
+	switch format {
+	case ArchiveTar, ArchiveTarGz, ArchiveZip:
+		return nil
+	}
+	return fmt.Errorf("--archive must be one of 'tar', 'tar.gz' and 'zip'")
+}
+
+// archiveFormatOf 根据对象扩展名判断归档格式
+func archiveFormatOf(key string) string {
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(lower, ".tar"):
+		return ArchiveTar
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip
+	}
+	return ""
+}
+
+func archiveContentType(format string) string {
+	switch format {
+	case ArchiveTarGz:
+		return "application/gzip"
+	case ArchiveZip:
+		return "application/zip"
+	}
+	return "application/x-tar"
+}
+
+// archiveWriter 各归档格式的统一写入接口
+type archiveWriter interface {
+	writeEntry(name string, info os.FileInfo, r io.Reader) error
+	Close() error
+}
+
+type tarArchiveWriter struct {
+	tw *tar.Writer
+	gz *gzip.Writer
+}
+
+func (w *tarArchiveWriter) writeEntry(name string, info os.FileInfo, r io.Reader) error {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err = w.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if r != nil {
+		_, err = io.CopyN(w.tw, r, hdr.Size)
+	}
+	return err
+}
+
+func (w *tarArchiveWriter) Close() error {
+	err := w.tw.Close()
+	if w.gz != nil {
+		if closeErr := w.gz.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func (w *zipArchiveWriter) writeEntry(name string, info os.FileInfo, r io.Reader) error {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if !info.IsDir() {
+		hdr.Method = zip.Deflate
+	}
+	fw, err := w.zw.CreateHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if r != nil {
+		_, err = io.CopyN(fw, r, info.Size())
+	}
+	return err
+}
+
+func (w *zipArchiveWriter) Close() error {
+	return w.zw.Close()
+}
+
+func newArchiveWriter(w io.Writer, format string) archiveWriter {
+	switch format {
+	case ArchiveZip:
+		return &zipArchiveWriter{zw: zip.NewWriter(w)}
+	case ArchiveTarGz:
+		gz := gzip.NewWriter(w)
+		return &tarArchiveWriter{tw: tar.NewWriter(gz), gz: gz}
+	}
+	return &tarArchiveWriter{tw: tar.NewWriter(w)}
+}
+
+// ArchiveUpload 将本地文件列表边打包边分块上传为一个归档对象，不产生本地临时文件
+func ArchiveUpload(c *cos.Client, fileUrl StorageUrl, cosUrl StorageUrl, fo *FileOperations) error {
+	startT := time.Now().UnixNano() / 1000 / 1000
+	localPath := fileUrl.ToString()
+	cosPath := cosUrl.(*CosUrl).Object
+	if cosPath == "" || strings.HasSuffix(cosPath, "/") {
+		return fmt.Errorf("--archive requires an object key as destination, e.g. cos://bucket/example.tar.gz")
+	}
+
+	fo.Monitor.init(fo.CpType)
+	chProgressSignal = make(chan chProgressSignalType, 10)
+	go progressBar(fo)
+
+	// 先统计文件总量，用于估算归档大小及分块大小
+	fileStatistic(localPath, fo)
+	estimateSize := fo.Monitor.TotalSize + (fo.Monitor.totalNum+1)*archiveEntryOverhead
+	partSize := fo.Operation.PartSize * 1024 * 1024
+	if min := estimateSize/9000 + 1; partSize < min {
+		partSize = min
+	}
+	threadNum := fo.Operation.ThreadNum
+	if threadNum == 0 {
+		var err error
+		threadNum, err = getThreadNumByPartSize(estimateSize, fo.Operation.PartSize)
+		if err != nil {
+			closeProgress()
+			return err
+		}
+	}
+
+	aclOpt := &cos.ACLHeaderOptions{
+		XCosACL:              fo.Operation.Acl,
+		XCosGrantRead:        fo.Operation.GrantRead,
+		XCosGrantFullControl: fo.Operation.GrantFullControl,
+		XCosGrantReadACP:     fo.Operation.GrantReadAcp,
+		XCosGrantWriteACP:    fo.Operation.GrantWriteAcp,
+	}
+	headerOpt := &cos.ObjectPutHeaderOptions{
+		CacheControl:             fo.Operation.Meta.CacheControl,
+		ContentDisposition:       fo.Operation.Meta.ContentDisposition,
+		ContentEncoding:          fo.Operation.Meta.ContentEncoding,
+		ContentType:              fo.Operation.Meta.ContentType,
+		ContentLanguage:          fo.Operation.Meta.ContentLanguage,
+		Expires:                  fo.Operation.Meta.Expires,
+		XCosMetaXXX:              fo.Operation.Meta.XCosMetaXXX,
+		XCosStorageClass:         fo.Operation.StorageClass,
+		XCosServerSideEncryption: fo.Operation.ServerSideEncryption,
+		XCosSSECustomerAglo:      fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:       fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5:    fo.Operation.SSECustomerKeyMD5,
+		XOptionHeader:            &http.Header{},
+		XCosTrafficLimit:         (int)(fo.Operation.RateLimiting * 1024 * 1024 * 8),
+	}
+	if headerOpt.ContentType == "" {
+		headerOpt.ContentType = archiveContentType(fo.Operation.Archive)
+	}
+	if fo.Operation.Tags != "" {
+		headerOpt.XOptionHeader.Add("x-cos-tagging", fo.Operation.Tags)
+	}
+	if fo.Operation.ForbidOverWrite {
+		headerOpt.XOptionHeader.Add("x-cos-forbid-overwrite", "true")
+	}
+	if fo.Operation.ObjectLockMode != "" {
+		setObjectLockHeader(headerOpt.XOptionHeader, fo.Operation.ObjectLockMode, fo.Operation.RetainUntil)
+	}
+
+	chFiles := make(chan fileInfoType, ChannelSize)
+	chListError := make(chan error, 1)
+	go generateFileList(localPath, chFiles, chListError, fo)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeArchive(pw, chFiles, fo))
+		// 打包失败时继续消费列表，避免列表协程阻塞
+		for range chFiles {
+		}
+	}()
+	err := streamUpload(fo.Context(), c, cosPath, pr, aclOpt, headerOpt, partSize, threadNum)
+	pr.CloseWithError(err)
+	listErr := <-chListError
+
+	closeProgress()
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
+	endT := time.Now().UnixNano() / 1000 / 1000
+	PrintTransferStats(startT, endT, fo)
+
+	if err == nil && listErr != nil {
+		err = fmt.Errorf("list local files error: %v", listErr)
+	}
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
+	return err
+}
+
+// writeArchive 按文件列表顺序写入归档，无法读取的文件记录错误后跳过，写入中途出错时归档不完整，直接返回
+func writeArchive(w io.Writer, chFiles <-chan fileInfoType, fo *FileOperations) error {
+	aw := newArchiveWriter(w, fo.Operation.Archive)
+	for file := range chFiles {
+		if fo.Interrupted() {
+			return ErrInterrupted
+		}
+		if file.isDir && fo.Operation.SkipDir {
+			fo.Monitor.updateMonitor(true, nil, true, 0)
+			continue
+		}
+		localFilePath := filepath.Join(file.dir, file.filePath)
+		f, info, err := openArchiveEntry(localFilePath)
+		if err != nil {
+			logger.Errorf("Archive %s failed: %v", localFilePath, err)
+			if fo.Operation.FailOutput {
+				writeError(fmt.Sprintf("archive %s error: %v", localFilePath, err), fo)
+			}
+			fo.Monitor.updateMonitor(false, err, file.isDir, 0)
+			continue
+		}
+		name := filepath.ToSlash(file.filePath)
+		var size int64
+		if info.IsDir() {
+			if !strings.HasSuffix(name, "/") {
+				name += "/"
+			}
+			err = aw.writeEntry(name, info, nil)
+		} else {
+			size = info.Size()
+			err = aw.writeEntry(name, info, f)
+		}
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("archive %s error: %v", localFilePath, err)
+		}
+		fo.Monitor.updateMonitor(false, nil, info.IsDir(), size)
+	}
+	return aw.Close()
+}
+
+func openArchiveEntry(localFilePath string) (*os.File, os.FileInfo, error) {
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// ArchiveExtract 流式下载归档对象并解包到本地目录，include/exclude作用于归档成员
+func ArchiveExtract(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo *FileOperations) error {
+	startT := time.Now().UnixNano() / 1000 / 1000
+	cosPath := cosUrl.(*CosUrl).Object
+	format := fo.Operation.Archive
+	if format == "" {
+		format = archiveFormatOf(cosPath)
+	}
+	if format == "" {
+		return fmt.Errorf("can not detect archive format of %s, please specify it with --archive", cosPath)
+	}
+	localDir := fileUrl.ToString()
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return err
+	}
+
+	fo.Monitor.init(fo.CpType)
+	chProgressSignal = make(chan chProgressSignalType, 10)
+	go progressBar(fo)
+
+	ex := &archiveExtractor{localDir: localDir, fo: fo}
+	var err error
+	if format == ArchiveZip {
+		err = ex.extractZip(c, cosPath)
+	} else {
+		err = ex.extractTar(c, cosPath, format)
+	}
+	ex.setDirTimes()
+
+	closeProgress()
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
+	endT := time.Now().UnixNano() / 1000 / 1000
+	PrintTransferStats(startT, endT, fo)
+
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
+	return err
+}
+
+// archiveExtractor 解包归档成员到本地目录
+type archiveExtractor struct {
+	localDir string
+	fo       *FileOperations
+	dirTimes map[string]time.Time
+}
+
+func (ex *archiveExtractor) getOptions() *cos.ObjectGetOptions {
+	header := &http.Header{}
+	header.Set("Accept-Encoding", "identity")
+	return &cos.ObjectGetOptions{
+		XCosSSECustomerAglo:   ex.fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:    ex.fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5: ex.fo.Operation.SSECustomerKeyMD5,
+		XCosTrafficLimit:      (int)(ex.fo.Operation.RateLimiting * 1024 * 1024 * 8),
+		XOptionHeader:         header,
+	}
+}
+
+func (ex *archiveExtractor) versionId() []string {
+	if ex.fo.Operation.VersionId == "" {
+		return nil
+	}
+	return []string{ex.fo.Operation.VersionId}
+}
+
+func (ex *archiveExtractor) extractTar(c *cos.Client, cosPath, format string) error {
+	resp, err := c.Object.Get(ex.fo.Context(), cosPath, ex.getOptions(), ex.versionId()...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var r io.Reader = resp.Body
+	if format == ArchiveTarGz {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read gzip error: %v", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for !ex.fo.Interrupted() {
+		hdr, err := tr.Next()
+		if err == io.EOF || (err != nil && ex.fo.Interrupted()) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read archive error: %v", err)
+		}
+		// tar成员数量事先未知，边读取边统计
+		if matchPatterns(hdr.Name, ex.fo.Operation.Filters) {
+			ex.fo.Monitor.updateScanSizeNum(hdr.Size, 1)
+		}
+		ex.extractEntry(hdr.Name, hdr.FileInfo(), tr)
+	}
+	ex.fo.Monitor.setScanEnd()
+	return nil
+}
+
+func (ex *archiveExtractor) extractZip(c *cos.Client, cosPath string) error {
+	resp, err := c.Object.Head(ex.fo.Context(), cosPath, &cos.ObjectHeadOptions{
+		XCosSSECustomerAglo:   ex.fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:    ex.fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5: ex.fo.Operation.SSECustomerKeyMD5,
+	}, ex.versionId()...)
+	if err != nil {
+		return err
+	}
+	ra := &cosReaderAt{ctx: ex.fo.Context(), c: c, key: cosPath, size: resp.ContentLength, opt: ex.getOptions(), versionId: ex.versionId()}
+	zr, err := zip.NewReader(ra, ra.size)
+	if err != nil {
+		return fmt.Errorf("read archive error: %v", err)
+	}
+	for _, f := range zr.File {
+		if matchPatterns(f.Name, ex.fo.Operation.Filters) {
+			ex.fo.Monitor.updateScanSizeNum(int64(f.UncompressedSize64), 1)
+		}
+	}
+	ex.fo.Monitor.setScanEnd()
+
+	for _, f := range zr.File {
+		if ex.fo.Interrupted() {
+			break
+		}
+		info := f.FileInfo()
+		if !info.Mode().IsRegular() {
+			ex.extractEntry(f.Name, info, nil)
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("read archive error: %v", err)
+		}
+		ex.extractEntry(f.Name, info, rc)
+		rc.Close()
+	}
+	return nil
+}
+
+// extractEntry 解包单个成员，失败时记录错误并继续处理后续成员
+func (ex *archiveExtractor) extractEntry(name string, info os.FileInfo, r io.Reader) {
+	if !matchPatterns(name, ex.fo.Operation.Filters) {
+		return
+	}
+	isDir := info.IsDir()
+	if !isDir && !info.Mode().IsRegular() {
+		// 不解包符号链接、硬链接及设备文件
+		logger.Warningf("Skip %s: unsupported archive entry type", name)
+		ex.fo.Monitor.updateMonitor(true, nil, false, info.Size())
+		return
+	}
+
+	local, err := archiveMemberPath(ex.localDir, name)
+	if err == nil {
+		if isDir {
+			err = os.MkdirAll(local, 0755)
+			if err == nil {
+				if ex.dirTimes == nil {
+					ex.dirTimes = make(map[string]time.Time)
+				}
+				ex.dirTimes[local] = info.ModTime()
+			}
+		} else {
+			err = ex.extractFile(local, info, r)
+		}
+	}
+	if err != nil {
+		logger.Errorf("Extract %s failed: %v", name, err)
+		if ex.fo.Operation.FailOutput {
+			writeError(fmt.Sprintf("extract %s error: %v", name, err), ex.fo)
+		}
+		ex.fo.Monitor.updateMonitor(false, err, isDir, 0)
+		return
+	}
+	ex.fo.Monitor.updateMonitor(false, nil, isDir, info.Size())
+}
+
+func (ex *archiveExtractor) extractFile(local string, info os.FileInfo, r io.Reader) error {
+	if err := createParentDirectory(local); err != nil {
+		return err
+	}
+	writePath := local
+	if ex.fo.Operation.Atomic {
+		writePath = atomicTempPath(local, false)
+	}
+	out, err := os.OpenFile(writePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(out, r)
+	if err == nil && n != info.Size() {
+		err = fmt.Errorf("size mismatch, expected %d, got %d", info.Size(), n)
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(writePath, info.Mode().Perm())
+	}
+	if err != nil {
+		os.Remove(writePath)
+		return err
+	}
+	os.Chtimes(writePath, info.ModTime(), info.ModTime())
+	if writePath != local {
+		return os.Rename(writePath, local)
+	}
+	return nil
+}
+
+// setDirTimes 写入文件会改变目录的修改时间，最后统一设置
+func (ex *archiveExtractor) setDirTimes() {
+	for dir, modTime := range ex.dirTimes {
+		os.Chtimes(dir, modTime, modTime)
+	}
+}
+
+// archiveMemberPath 归档成员对应的本地路径，不允许跳出解包目录
+func archiveMemberPath(localDir, name string) (string, error) {
+	local := filepath.Join(localDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(localDir, local)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid path in archive: %s", name)
+	}
+	return local, nil
+}
+
+// cosReaderAt 通过范围下载随机读取对象，缓存最近下载的数据块以减少请求次数
+type cosReaderAt struct {
+	ctx       context.Context
+	c         *cos.Client
+	key       string
+	size      int64
+	opt       *cos.ObjectGetOptions
+	versionId []string
+
+	mu  sync.Mutex
+	off int64
+	buf []byte
+}
+
+func (r *cosReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for n < len(p) && off < r.size {
+		if off < r.off || off >= r.off+int64(len(r.buf)) {
+			if err := r.fetch(off); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(p[n:], r.buf[off-r.off:])
+		n += copied
+		off += int64(copied)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (r *cosReaderAt) fetch(off int64) error {
+	end := off + archiveReadAheadSize
+	if end > r.size {
+		end = r.size
+	}
+	opt := *r.opt
+	opt.Range = fmt.Sprintf("bytes=%d-%d", off, end-1)
+	resp, err := r.c.Object.Get(r.ctx, r.key, &opt, r.versionId...)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) != end-off {
+		return fmt.Errorf("range read size mismatch, expected %d, got %d", end-off, len(buf))
+	}
+	r.off = off
+	r.buf = buf
+	return nil
+}
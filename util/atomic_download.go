@@ -0,0 +1,136 @@
+package util
+
+import (
+	"fmt"
+	"hash/crc64"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 原子下载临时文件前缀
+	atomicTempPrefix = ".coscli-tmp-"
+	// sdk断点续传记录文件后缀
+	checkPointFileSuffix = ".cosresumabletask"
+	// 超过该时间未更新的临时文件视为上次运行的残留
+	atomicTempStaleAge = time.Hour
+)
+
+// atomicTempPath 原子下载的临时文件路径，与目标文件同目录
+// 可续传时使用固定文件名以便下次继续下载，否则添加随机后缀，避免并发下载同一文件时写入同一临时文件
+func atomicTempPath(localFilePath string, resumable bool) string {
+	dir, name := filepath.Split(localFilePath)
+	if resumable {
+		return filepath.Join(dir, atomicTempPrefix+name)
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s%s.%d-%04x", atomicTempPrefix, name, os.Getpid(), rand.Intn(0x10000)))
+}
+
+// commitAtomicDownload 校验临时文件大小及crc64，落盘后重命名为目标文件，crc64为空时不校验
+func commitAtomicDownload(tempPath, localFilePath string, size int64, crc string, resp *cos.Response) error {
+	f, err := os.OpenFile(tempPath, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	// 传输层自动解压时本地大小与对象大小不一致，不校验大小
+	uncompressed := resp != nil && resp.Response != nil && resp.Uncompressed
+	if !uncompressed && info.Size() != size {
+		f.Close()
+		return fmt.Errorf("downloaded size mismatch, expected %d, got %d", size, info.Size())
+	}
+	if !uncompressed && crc != "" {
+		ecma := crc64.New(crc64.MakeTable(crc64.ECMA))
+		if _, err = io.Copy(ecma, f); err != nil {
+			f.Close()
+			return err
+		}
+		if local := strconv.FormatUint(ecma.Sum64(), 10); local != crc {
+			f.Close()
+			return fmt.Errorf("downloaded crc64 mismatch, expected %s, got %s", crc, local)
+		}
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, localFilePath)
+}
+
+// removeAtomicTemp 下载失败时删除临时文件，可续传时保留以便下次继续下载
+func removeAtomicTemp(tempPath string, resumable bool) {
+	if resumable {
+		return
+	}
+	os.Remove(tempPath)
+}
+
+// cleanStaleTempFiles 清理本地目录下上次运行残留的临时文件
+func cleanStaleTempFiles(localPath string, fo *FileOperations) {
+	info, err := os.Stat(localPath)
+	if err != nil || !info.IsDir() {
+		return
+	}
+	filepath.Walk(localPath, func(fpath string, f os.FileInfo, err error) error {
+		if err != nil || f.IsDir() || !strings.HasPrefix(f.Name(), atomicTempPrefix) {
+			return nil
+		}
+		removeStaleTempFile(fpath, f, fo)
+		return nil
+	})
+}
+
+// cleanStaleTempFilesOf 清理单个目标文件上次运行残留的临时文件
+func cleanStaleTempFilesOf(localFilePath string, fo *FileOperations) {
+	dir, name := filepath.Split(localFilePath)
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), atomicTempPrefix+name) {
+			continue
+		}
+		if f, err := entry.Info(); err == nil {
+			removeStaleTempFile(filepath.Join(dir, entry.Name()), f, fo)
+		}
+	}
+}
+
+// removeStaleTempFile 删除超过atomicTempStaleAge未更新的临时文件
+func removeStaleTempFile(fpath string, f os.FileInfo, fo *FileOperations) {
+	if time.Since(f.ModTime()) < atomicTempStaleAge {
+		return
+	}
+	// 开启断点续传时保留可续传的临时文件及其记录文件
+	if fo.Operation.CheckPoint {
+		pair := fpath + checkPointFileSuffix
+		if strings.HasSuffix(fpath, checkPointFileSuffix) {
+			pair = strings.TrimSuffix(fpath, checkPointFileSuffix)
+		}
+		if _, err := os.Stat(pair); err == nil {
+			return
+		}
+	}
+	if err := os.Remove(fpath); err == nil {
+		logger.Infof("Remove stale temp file %s", fpath)
+	}
+}
@@ -0,0 +1,482 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+	logger "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 内容定义分块的最小、平均、最大大小
+	backupChunkMin  = 512 * 1024
+	backupChunkAvg  = 1024 * 1024
+	backupChunkMax  = 4 * 1024 * 1024
+	backupChunkMask = backupChunkAvg - 1
+
+	// 备份仓库内的目录及对象
+	backupChunksDir    = "chunks/"
+	backupSnapshotsDir = "snapshots/"
+	backupPruneIdKey   = "prune-id"
+	backupLocksDir     = "locks/"
+	backupSnapshotExt  = ".json"
+
+	// 本地索引中记录仓库清理标识的key，其余key为分块hash
+	backupIndexPruneId = "!prune-id"
+
+	metaBackupFiles  = "x-cos-meta-backup-files"
+	metaBackupSize   = "x-cos-meta-backup-size"
+	metaBackupSource = "x-cos-meta-backup-source"
+	metaBackupHost   = "x-cos-meta-backup-host"
+)
+
+// gearTable 分块使用的gear hash表，由固定种子生成，修改会导致分块边界变化、无法去重
+var gearTable = func() (table [256]uint64) {
+	seed := uint64(0x636f73636c69)
+	for i := range table {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// BackupChunk 快照中文件的分块
+type BackupChunk struct {
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+}
+
+// BackupFile 快照中的文件或目录
+type BackupFile struct {
+	Path    string        `json:"path"`
+	Size    int64         `json:"size"`
+	Mode    uint32        `json:"mode"`
+	ModTime int64         `json:"mtime"`
+	IsDir   bool          `json:"dir,omitempty"`
+	Chunks  []BackupChunk `json:"chunks,omitempty"`
+}
+
+// BackupSnapshot 快照清单，id以UTC时间开头，按id排序即按时间排序
+type BackupSnapshot struct {
+	Id     string       `json:"id"`
+	Time   string       `json:"time"`
+	Source string       `json:"source"`
+	Host   string       `json:"host"`
+	Size   int64        `json:"size"`
+	Files  []BackupFile `json:"files"`
+}
+
+// contentChunker 基于gear hash的内容定义分块，相同内容在不同文件、不同位置产生相同分块
+type contentChunker struct {
+	r   io.Reader
+	buf []byte
+	n   int
+	eof bool
+}
+
+func newContentChunker(r io.Reader) *contentChunker {
+	return &contentChunker{r: r, buf: make([]byte, backupChunkMax)}
+}
+
+// next 返回下一个分块，读取结束时返回io.EOF
+func (ck *contentChunker) next() ([]byte, error) {
+	if !ck.eof && ck.n < len(ck.buf) {
+		m, err := io.ReadFull(ck.r, ck.buf[ck.n:])
+		ck.n += m
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			ck.eof = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if ck.n == 0 {
+		return nil, io.EOF
+	}
+	cut := chunkCutPoint(ck.buf[:ck.n])
+	chunk := make([]byte, cut)
+	copy(chunk, ck.buf[:cut])
+	ck.n = copy(ck.buf, ck.buf[cut:ck.n])
+	return chunk, nil
+}
+
+// chunkCutPoint 在最小分块大小之后寻找hash满足掩码的位置作为分块边界
+func chunkCutPoint(data []byte) int {
+	if len(data) <= backupChunkMin {
+		return len(data)
+	}
+	var h uint64
+	for i := backupChunkMin; i < len(data); i++ {
+		h = (h << 1) + gearTable[data[i]]
+		if h&backupChunkMask == 0 {
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+func backupChunkKey(repo, hash string) string {
+	return repo + backupChunksDir + hash[:2] + "/" + hash
+}
+
+func backupSnapshotKey(repo, id string) string {
+	return repo + backupSnapshotsDir + id + backupSnapshotExt
+}
+
+// backupRepoPrefix 仓库路径统一以/结尾
+func backupRepoPrefix(cosUrl StorageUrl) string {
+	repo := cosUrl.(*CosUrl).Object
+	if repo != "" && !strings.HasSuffix(repo, "/") {
+		repo += "/"
+	}
+	return repo
+}
+
+// chunkUpload 同一分块并发上传时，只由一个协程上传，其余等待结果
+type chunkUpload struct {
+	done chan struct{}
+	err  error
+}
+
+// backupIndex 本地记录仓库已有分块的leveldb索引
+type backupIndex struct {
+	db       *leveldb.DB
+	inflight sync.Map
+}
+
+// defaultBackupIndexPath 默认索引路径，按仓库区分
+func defaultBackupIndexPath(bucket, repo string) string {
+	home, _ := homedir.Dir()
+	sum := sha256.Sum256([]byte(bucket + "/" + repo))
+	return filepath.Join(home, ".coscli", "backup-index", hex.EncodeToString(sum[:8]))
+}
+
+// openBackupIndex 打开本地索引，仓库清理过时清空索引，避免引用已删除的分块
+func openBackupIndex(ctx context.Context, c *cos.Client, bucket, repo, indexPath string) (*backupIndex, error) {
+	if indexPath == "" {
+		indexPath = defaultBackupIndexPath(bucket, repo)
+	}
+	pruneId, err := getBackupPruneId(ctx, c, repo)
+	if err != nil {
+		return nil, err
+	}
+	db, err := leveldb.OpenFile(indexPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open backup index error: %v", err)
+	}
+	recorded, err := db.Get([]byte(backupIndexPruneId), nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		db.Close()
+		return nil, err
+	}
+	if err == leveldb.ErrNotFound || string(recorded) != pruneId {
+		if err == nil {
+			logger.Infof("Backup repository was pruned, reset local chunk index %s", indexPath)
+		}
+		iter := db.NewIterator(nil, nil)
+		batch := new(leveldb.Batch)
+		for iter.Next() {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+		iter.Release()
+		batch.Put([]byte(backupIndexPruneId), []byte(pruneId))
+		if err = db.Write(batch, nil); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return &backupIndex{db: db}, nil
+}
+
+func (idx *backupIndex) close() {
+	idx.db.Close()
+}
+
+func (idx *backupIndex) known(hash string) bool {
+	ok, _ := idx.db.Has([]byte(hash), nil)
+	return ok
+}
+
+// ensure 确保分块已在仓库中，返回是否由本次上传
+func (idx *backupIndex) ensure(hash string, upload func() (bool, error)) (bool, error) {
+	if idx.known(hash) {
+		return false, nil
+	}
+	v, loaded := idx.inflight.LoadOrStore(hash, &chunkUpload{done: make(chan struct{})})
+	cu := v.(*chunkUpload)
+	if loaded {
+		<-cu.done
+		return false, cu.err
+	}
+	uploaded, err := upload()
+	if err == nil {
+		err = idx.db.Put([]byte(hash), nil, nil)
+	}
+	cu.err = err
+	if err != nil {
+		idx.inflight.Delete(hash)
+	}
+	close(cu.done)
+	return uploaded, err
+}
+
+// getBackupPruneId 获取仓库清理标识，未清理过时为空
+func getBackupPruneId(ctx context.Context, c *cos.Client, repo string) (string, error) {
+	resp, err := c.Object.Get(ctx, repo+backupPruneIdKey, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return "", nil
+		}
+		return "", err
+	}
+	defer resp.Body.Close()
+	id, err := io.ReadAll(resp.Body)
+	return strings.TrimSpace(string(id)), err
+}
+
+// backupStat 备份统计
+type backupStat struct {
+	newChunks   int64
+	newSize     int64
+	dedupChunks int64
+	dedupSize   int64
+}
+
+// Backup 将本地目录按内容定义分块去重备份到cos仓库，并写入快照清单
+func Backup(c *cos.Client, fileUrl StorageUrl, cosUrl StorageUrl, fo *FileOperations, indexPath string) (*BackupSnapshot, error) {
+	startT := time.Now().UnixNano() / 1000 / 1000
+	localPath := fileUrl.ToString()
+	repo := backupRepoPrefix(cosUrl)
+
+	// 持有仓库锁直到快照写入，避免清理删除本次引用的分块
+	lock, err := acquireBackupLock(c, repo, backupLockBackup)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.release()
+
+	idx, err := openBackupIndex(fo.Context(), c, cosUrl.(*CosUrl).Bucket, repo, indexPath)
+	if err != nil {
+		return nil, err
+	}
+	defer idx.close()
+
+	fo.Monitor.init(fo.CpType)
+	chProgressSignal = make(chan chProgressSignalType, 10)
+	go progressBar(fo)
+
+	chFiles := make(chan fileInfoType, ChannelSize)
+	chError := make(chan error, fo.Operation.Routines)
+	chLog := make(chan string, fo.Operation.Routines)
+	chListError := make(chan error, 1)
+
+	var wgLogger sync.WaitGroup
+	wgLogger.Add(1)
+	go func() {
+		defer wgLogger.Done()
+		for processMsg := range chLog {
+			writeProcessLog(processMsg, fo)
+		}
+	}()
+
+	go fileStatistic(localPath, fo)
+	go generateFileList(localPath, chFiles, chListError, fo)
+
+	// 复用上传工作协程池，分块进度由backupSingleFile更新，文件完成时不再重复计入大小
+	var files []BackupFile
+	var stat backupStat
+	var resultMu sync.Mutex
+	for i := 0; i < fo.Operation.Routines; i++ {
+		go uploadFilesWith(fo, chFiles, chError, chLog, func(file fileInfoType) (bool, error, bool, int64, int64, string) {
+			localFilePath := filepath.Join(file.dir, file.filePath)
+			msg := fmt.Sprintf("Backup %s to %s", localFilePath, getCosUrl(cosUrl.(*CosUrl).Bucket, repo))
+			entry, fileStat, err := backupSingleFile(c, repo, file, idx, fo)
+			if err != nil {
+				return false, err, file.isDir, 0, fileStat.newSize + fileStat.dedupSize, msg
+			}
+			resultMu.Lock()
+			files = append(files, entry)
+			stat.newChunks += fileStat.newChunks
+			stat.newSize += fileStat.newSize
+			stat.dedupChunks += fileStat.dedupChunks
+			stat.dedupSize += fileStat.dedupSize
+			resultMu.Unlock()
+			return false, nil, file.isDir, 0, 0, msg
+		})
+	}
+
+	var failed int64
+	var listErr error
+	completed := 0
+	for completed <= fo.Operation.Routines {
+		select {
+		case err := <-chListError:
+			listErr = err
+			completed++
+		case err := <-chError:
+			if err == nil {
+				completed++
+			} else {
+				failed++
+				logger.Error(strings.TrimSpace(err.Error()))
+				if fo.Operation.FailOutput {
+					writeError(err.Error(), fo)
+				}
+			}
+		}
+	}
+
+	close(chLog)
+	wgLogger.Wait()
+
+	closeProgress()
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
+	endT := time.Now().UnixNano() / 1000 / 1000
+	PrintTransferStats(startT, endT, fo)
+
+	// 快照须完整，有文件失败或被中断时不写入清单
+	if listErr != nil {
+		return nil, fmt.Errorf("list local files error: %v", listErr)
+	}
+	if fo.Interrupted() {
+		return nil, ErrInterrupted
+	}
+	if failed > 0 {
+		return nil, fmt.Errorf("%d files failed to back up, snapshot is not written", failed)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	absPath, _ := filepath.Abs(localPath)
+	host, _ := os.Hostname()
+	now := time.Now().UTC()
+	snapshot := &BackupSnapshot{
+		Id:     fmt.Sprintf("%s-%04x", now.Format("20060102T150405.000000Z"), rand.Intn(0x10000)),
+		Time:   now.Format(time.RFC3339),
+		Source: absPath,
+		Host:   host,
+		Files:  files,
+	}
+	for _, f := range files {
+		snapshot.Size += f.Size
+	}
+	if err = putBackupSnapshot(fo.Context(), c, repo, snapshot); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Snapshot %s saved, files: %d, size: %s", snapshot.Id, len(files), FormatSize(snapshot.Size))
+	logger.Infof("New chunks: %d (%s), deduplicated chunks: %d (%s)", stat.newChunks, FormatSize(stat.newSize),
+		stat.dedupChunks, FormatSize(stat.dedupSize))
+	return snapshot, nil
+}
+
+// backupSingleFile 分块上传单个文件，返回清单中的文件记录
+func backupSingleFile(c *cos.Client, repo string, file fileInfoType, idx *backupIndex, fo *FileOperations) (BackupFile, backupStat, error) {
+	var stat backupStat
+	localFilePath := filepath.Join(file.dir, file.filePath)
+	info, err := os.Stat(localFilePath)
+	if err != nil {
+		return BackupFile{}, stat, err
+	}
+	entry := BackupFile{
+		Path:    filepath.ToSlash(file.filePath),
+		Mode:    uint32(info.Mode().Perm()),
+		ModTime: info.ModTime().Unix(),
+		IsDir:   info.IsDir(),
+	}
+	if info.IsDir() {
+		return entry, stat, nil
+	}
+
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return entry, stat, err
+	}
+	defer f.Close()
+
+	ck := newContentChunker(f)
+	for {
+		data, err := ck.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return entry, stat, err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])
+		uploaded, err := idx.ensure(hash, func() (bool, error) {
+			return putBackupChunk(fo.Context(), c, backupChunkKey(repo, hash), data)
+		})
+		if err != nil {
+			return entry, stat, fmt.Errorf("upload chunk %s error: %v", hash, err)
+		}
+		size := int64(len(data))
+		if uploaded {
+			stat.newChunks++
+			stat.newSize += size
+			fo.Monitor.updateTransferSize(size)
+		} else {
+			stat.dedupChunks++
+			stat.dedupSize += size
+		}
+		fo.Monitor.updateDealSize(size)
+		freshProgress()
+		entry.Chunks = append(entry.Chunks, BackupChunk{Hash: hash, Size: size})
+		entry.Size += size
+	}
+	return entry, stat, nil
+}
+
+// putBackupChunk 上传分块，仓库中已存在时跳过
+func putBackupChunk(ctx context.Context, c *cos.Client, key string, data []byte) (bool, error) {
+	resp, err := c.Object.Head(ctx, key, nil)
+	if err == nil {
+		if resp.ContentLength == int64(len(data)) {
+			return false, nil
+		}
+	} else if resp == nil || resp.StatusCode != 404 {
+		return false, err
+	}
+	_, err = c.Object.Put(ctx, key, bytes.NewReader(data), nil)
+	return err == nil, err
+}
+
+// putBackupSnapshot 上传快照清单，摘要写入自定义元数据便于列出
+func putBackupSnapshot(ctx context.Context, c *cos.Client, repo string, snapshot *BackupSnapshot) error {
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	meta := &http.Header{}
+	meta.Set(metaBackupFiles, strconv.Itoa(len(snapshot.Files)))
+	meta.Set(metaBackupSize, strconv.FormatInt(snapshot.Size, 10))
+	meta.Set(metaBackupSource, url.PathEscape(snapshot.Source))
+	meta.Set(metaBackupHost, url.PathEscape(snapshot.Host))
+	_, err = c.Object.Put(ctx, backupSnapshotKey(repo, snapshot.Id), bytes.NewReader(content), &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
+			ContentType: "application/json",
+			XCosMetaXXX: meta,
+		},
+	})
+	return err
+}
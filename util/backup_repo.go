@@ -0,0 +1,482 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 快照id为latest时恢复最新快照
+	backupLatestSnapshot = "latest"
+
+	// 仓库锁类型，备份之间可并发，清理与其他任何操作互斥
+	backupLockBackup = "backup"
+	backupLockPrune  = "prune"
+	// 持有锁期间定期刷新，超过backupLockStale未刷新的锁视为进程已退出
+	backupLockRefresh = 10 * time.Minute
+	backupLockStale   = time.Hour
+)
+
+// backupLock 仓库锁对象<repo>/locks/<type>-<id>
+type backupLock struct {
+	c    *cos.Client
+	key  string
+	stop chan struct{}
+	done chan struct{}
+}
+
+// acquireBackupLock 先写入锁对象再检查冲突的锁，备份与清理并发开始时至少一方能看到对方的锁
+func acquireBackupLock(c *cos.Client, repo, lockType string) (*backupLock, error) {
+	host, _ := os.Hostname()
+	key := fmt.Sprintf("%s%s%s-%d-%04x", repo, backupLocksDir, lockType, time.Now().UnixNano(), rand.Intn(0x10000))
+	lock := &backupLock{c: c, key: key, stop: make(chan struct{}), done: make(chan struct{})}
+	if err := lock.put(host); err != nil {
+		return nil, fmt.Errorf("put repository lock error: %v", err)
+	}
+
+	marker := ""
+	isTruncated := true
+	for isTruncated {
+		objects, truncated, nextMarker, _, err := GetObjectsListIterator(c, repo+backupLocksDir, marker, "", "")
+		if err != nil {
+			lock.remove()
+			return nil, err
+		}
+		for _, object := range objects {
+			if object.Key == key {
+				continue
+			}
+			if lastModified, err := time.Parse(time.RFC3339, object.LastModified); err == nil && time.Since(lastModified) > backupLockStale {
+				continue
+			}
+			other := strings.TrimPrefix(object.Key, repo+backupLocksDir)
+			if lockType == backupLockPrune || strings.HasPrefix(other, backupLockPrune+"-") {
+				lock.remove()
+				return nil, fmt.Errorf("repository %s is locked by %s since %s, please try again later", repo, other, object.LastModified)
+			}
+		}
+		isTruncated, marker = truncated, nextMarker
+	}
+
+	go func() {
+		defer close(lock.done)
+		ticker := time.NewTicker(backupLockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lock.stop:
+				return
+			case <-ticker.C:
+				if err := lock.put(host); err != nil {
+					logger.Warningf("Refresh repository lock %s error: %v", key, err)
+				}
+			}
+		}
+	}()
+	return lock, nil
+}
+
+func (l *backupLock) put(host string) error {
+	_, err := l.c.Object.Put(context.Background(), l.key, strings.NewReader(host), nil)
+	return err
+}
+
+func (l *backupLock) remove() {
+	if _, err := l.c.Object.Delete(context.Background(), l.key); err != nil {
+		logger.Warningf("Delete repository lock %s error: %v", l.key, err)
+	}
+}
+
+// release 停止刷新并删除锁对象
+func (l *backupLock) release() {
+	close(l.stop)
+	<-l.done
+	l.remove()
+}
+
+// listBackupSnapshotKeys 列出仓库中的快照清单对象，按快照id升序
+func listBackupSnapshotKeys(c *cos.Client, repo string) ([]cos.Object, error) {
+	var snapshots []cos.Object
+	marker := ""
+	isTruncated := true
+	for isTruncated {
+		objects, truncated, nextMarker, _, err := GetObjectsListIterator(c, repo+backupSnapshotsDir, marker, "", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range objects {
+			if strings.HasSuffix(object.Key, backupSnapshotExt) {
+				snapshots = append(snapshots, object)
+			}
+		}
+		isTruncated, marker = truncated, nextMarker
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Key < snapshots[j].Key })
+	return snapshots, nil
+}
+
+func backupSnapshotId(repo, key string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(key, repo+backupSnapshotsDir), backupSnapshotExt)
+}
+
+// ParseBackupSnapshotUrl 解析快照路径cos://<bucket>/<repo>/snapshots/<id>，返回仓库前缀和快照id
+func ParseBackupSnapshotUrl(cosUrl StorageUrl) (repo string, id string, err error) {
+	object := cosUrl.(*CosUrl).Object
+	i := strings.LastIndex(object, backupSnapshotsDir)
+	if i < 0 || (i > 0 && object[i-1] != '/') || i+len(backupSnapshotsDir) == len(object) {
+		return "", "", fmt.Errorf("snapshot path must be like cos://<bucket>/<repo>/%s<snapshot-id>", backupSnapshotsDir)
+	}
+	return object[:i], strings.TrimSuffix(object[i+len(backupSnapshotsDir):], backupSnapshotExt), nil
+}
+
+// loadBackupSnapshot 读取快照清单，id为latest时读取最新快照
+func loadBackupSnapshot(ctx context.Context, c *cos.Client, repo, id string) (*BackupSnapshot, error) {
+	if id == backupLatestSnapshot {
+		snapshots, err := listBackupSnapshotKeys(c, repo)
+		if err != nil {
+			return nil, err
+		}
+		if len(snapshots) == 0 {
+			return nil, fmt.Errorf("no snapshot found in %s", repo)
+		}
+		id = backupSnapshotId(repo, snapshots[len(snapshots)-1].Key)
+	}
+	resp, err := c.Object.Get(ctx, backupSnapshotKey(repo, id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get snapshot %s error: %v", id, err)
+	}
+	defer resp.Body.Close()
+	snapshot := &BackupSnapshot{}
+	if err = json.NewDecoder(resp.Body).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("parse snapshot %s error: %v", id, err)
+	}
+	return snapshot, nil
+}
+
+// ListBackupSnapshots 列出仓库中的快照
+func ListBackupSnapshots(ctx context.Context, c *cos.Client, cosUrl StorageUrl) error {
+	repo := backupRepoPrefix(cosUrl)
+	snapshots, err := listBackupSnapshotKeys(c, repo)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Snapshot", "Time", "Host", "Source", "Files", "Size"})
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	for _, object := range snapshots {
+		resp, err := c.Object.Head(ctx, object.Key, nil)
+		if err != nil {
+			return err
+		}
+		size, _ := strconv.ParseInt(resp.Header.Get(metaBackupSize), 10, 64)
+		host, _ := url.PathUnescape(resp.Header.Get(metaBackupHost))
+		source, _ := url.PathUnescape(resp.Header.Get(metaBackupSource))
+		table.Append([]string{
+			getCosUrl(cosUrl.(*CosUrl).Bucket, object.Key),
+			object.LastModified,
+			host,
+			source,
+			resp.Header.Get(metaBackupFiles),
+			FormatSize(size),
+		})
+	}
+	table.Render()
+	logger.Infof("Total snapshots: %d", len(snapshots))
+	return nil
+}
+
+// backupRestorePath 快照中的路径对应的本地路径，不允许跳出恢复目录
+func backupRestorePath(localDir, path string) (string, error) {
+	local := filepath.Join(localDir, filepath.FromSlash(path))
+	rel, err := filepath.Rel(localDir, local)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid path in snapshot: %s", path)
+	}
+	return local, nil
+}
+
+// RestoreBackup 将快照恢复到本地目录
+func RestoreBackup(c *cos.Client, snapshotUrl StorageUrl, fileUrl StorageUrl, fo *FileOperations) error {
+	startT := time.Now().UnixNano() / 1000 / 1000
+	repo, id, err := ParseBackupSnapshotUrl(snapshotUrl)
+	if err != nil {
+		return err
+	}
+	snapshot, err := loadBackupSnapshot(fo.Context(), c, repo, id)
+	if err != nil {
+		return err
+	}
+	localDir := fileUrl.ToString()
+	if err = os.MkdirAll(localDir, 0755); err != nil {
+		return err
+	}
+	logger.Infof("Restore snapshot %s (%d files, %s) to %s", snapshot.Id, len(snapshot.Files), FormatSize(snapshot.Size), localDir)
+
+	fo.Monitor.init(fo.CpType)
+	chProgressSignal = make(chan chProgressSignalType, 10)
+	go progressBar(fo)
+	for _, f := range snapshot.Files {
+		fo.Monitor.updateScanSizeNum(f.Size, 1)
+	}
+	fo.Monitor.setScanEnd()
+
+	chFiles := make(chan BackupFile, ChannelSize)
+	go func() {
+		defer close(chFiles)
+		for _, f := range snapshot.Files {
+			chFiles <- f
+		}
+	}()
+
+	var failed int64
+	var failedMu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < fo.Operation.Routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range chFiles {
+				// 中断后不再处理新的文件
+				if fo.Interrupted() {
+					continue
+				}
+				err := restoreBackupFile(c, repo, localDir, f, fo)
+				fo.Monitor.updateMonitor(false, err, f.IsDir, 0)
+				if err != nil {
+					failedMu.Lock()
+					failed++
+					failedMu.Unlock()
+					logger.Errorf("Restore %s failed: %v", f.Path, err)
+					if fo.Operation.FailOutput {
+						writeError(err.Error(), fo)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 写入文件会改变目录的修改时间，最后统一设置目录的权限及修改时间
+	for _, f := range snapshot.Files {
+		if f.IsDir {
+			if local, err := backupRestorePath(localDir, f.Path); err == nil {
+				modTime := time.Unix(f.ModTime, 0)
+				os.Chmod(local, os.FileMode(f.Mode).Perm())
+				os.Chtimes(local, modTime, modTime)
+			}
+		}
+	}
+
+	closeProgress()
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
+	endT := time.Now().UnixNano() / 1000 / 1000
+	PrintTransferStats(startT, endT, fo)
+
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d files failed to restore", failed)
+	}
+	return nil
+}
+
+// restoreBackupFile 按分块下载并校验，写入临时文件后重命名
+func restoreBackupFile(c *cos.Client, repo, localDir string, f BackupFile, fo *FileOperations) error {
+	local, err := backupRestorePath(localDir, f.Path)
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(f.Mode).Perm()
+	modTime := time.Unix(f.ModTime, 0)
+	// 目录权限在所有文件恢复后设置，避免只读目录导致其中的文件无法写入
+	if f.IsDir {
+		return os.MkdirAll(local, 0755)
+	}
+
+	if err = createParentDirectory(local); err != nil {
+		return err
+	}
+	tempPath := atomicTempPath(local, false)
+	out, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	var written int64
+	for _, chunk := range f.Chunks {
+		if err = restoreBackupChunk(fo.Context(), c, repo, chunk, out); err != nil {
+			break
+		}
+		written += chunk.Size
+		fo.Monitor.updateTransferSize(chunk.Size)
+		fo.Monitor.updateDealSize(chunk.Size)
+		freshProgress()
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fo.Monitor.updateDealSize(-written)
+		os.Remove(tempPath)
+		return err
+	}
+	if err = os.Chmod(tempPath, mode); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	os.Chtimes(tempPath, modTime, modTime)
+	return os.Rename(tempPath, local)
+}
+
+// restoreBackupChunk 下载分块并校验sha256
+func restoreBackupChunk(ctx context.Context, c *cos.Client, repo string, chunk BackupChunk, w io.Writer) error {
+	resp, err := c.Object.Get(ctx, backupChunkKey(repo, chunk.Hash), nil)
+	if err != nil {
+		return fmt.Errorf("get chunk %s error: %v", chunk.Hash, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read chunk %s error: %v", chunk.Hash, err)
+	}
+	sum := sha256.Sum256(data)
+	if int64(len(data)) != chunk.Size || hex.EncodeToString(sum[:]) != chunk.Hash {
+		return fmt.Errorf("chunk %s is corrupted", chunk.Hash)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// PruneBackup 删除多余的快照及不再被任何快照引用的分块，ctx取消时停止删除
+func PruneBackup(ctx context.Context, c *cos.Client, cosUrl StorageUrl, keepLast int, dryRun bool) error {
+	repo := backupRepoPrefix(cosUrl)
+	bucket := cosUrl.(*CosUrl).Bucket
+
+	// 清理期间不允许备份，进行中的备份可能引用本地索引中已有、但未被任何快照引用的分块
+	if !dryRun {
+		lock, err := acquireBackupLock(c, repo, backupLockPrune)
+		if err != nil {
+			return err
+		}
+		defer lock.release()
+	}
+
+	snapshots, err := listBackupSnapshotKeys(c, repo)
+	if err != nil {
+		return err
+	}
+
+	// 保留最新的keepLast个快照，为0时保留全部
+	var removeSnapshots []cos.Object
+	if keepLast > 0 && len(snapshots) > keepLast {
+		removeSnapshots = snapshots[:len(snapshots)-keepLast]
+		snapshots = snapshots[len(snapshots)-keepLast:]
+	}
+
+	referenced := make(map[string]bool)
+	for _, object := range snapshots {
+		snapshot, err := loadBackupSnapshot(ctx, c, repo, backupSnapshotId(repo, object.Key))
+		if err != nil {
+			return err
+		}
+		for _, f := range snapshot.Files {
+			for _, chunk := range f.Chunks {
+				referenced[chunk.Hash] = true
+			}
+		}
+	}
+
+	var removeChunks []cos.Object
+	var removeSize, keptChunks int64
+	marker := ""
+	isTruncated := true
+	for isTruncated {
+		objects, truncated, nextMarker, _, err := GetObjectsListIterator(c, repo+backupChunksDir, marker, "", "")
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			hash := object.Key[strings.LastIndex(object.Key, "/")+1:]
+			if referenced[hash] {
+				keptChunks++
+				continue
+			}
+			removeChunks = append(removeChunks, object)
+			removeSize += object.Size
+		}
+		isTruncated, marker = truncated, nextMarker
+	}
+
+	if dryRun {
+		for _, object := range removeSnapshots {
+			logger.Infof("Prune(dry run) snapshot %s", getCosUrl(bucket, object.Key))
+		}
+		logger.Infof("Prune(dry run) would remove %d snapshots, %d chunks (%s), keep %d snapshots, %d chunks",
+			len(removeSnapshots), len(removeChunks), FormatSize(removeSize), len(snapshots), keptChunks)
+		return nil
+	}
+
+	err = deleteBackupObjects(ctx, c, removeSnapshots)
+	if err == nil {
+		err = deleteBackupObjects(ctx, c, removeChunks)
+	}
+	// 删除后更新清理标识，使各主机的本地分块索引失效；删除失败或中断时部分分块可能已删除，同样需要更新
+	pruneId := fmt.Sprintf("%d", time.Now().UnixNano())
+	if _, putErr := c.Object.Put(context.Background(), repo+backupPruneIdKey, strings.NewReader(pruneId), nil); putErr != nil {
+		return fmt.Errorf("update prune id error: %v", putErr)
+	}
+	if ctx.Err() != nil {
+		return ErrInterrupted
+	}
+	if err != nil {
+		return err
+	}
+	logger.Infof("Pruned %d snapshots, %d chunks (%s), kept %d snapshots, %d chunks",
+		len(removeSnapshots), len(removeChunks), FormatSize(removeSize), len(snapshots), keptChunks)
+	return nil
+}
+
+// deleteBackupObjects 批量删除对象，每次最多1000个
+func deleteBackupObjects(ctx context.Context, c *cos.Client, objects []cos.Object) error {
+	for start := 0; start < len(objects); start += 1000 {
+		end := start + 1000
+		if end > len(objects) {
+			end = len(objects)
+		}
+		opt := &cos.ObjectDeleteMultiOptions{Quiet: true}
+		for _, object := range objects[start:end] {
+			opt.Objects = append(opt.Objects, cos.Object{Key: object.Key})
+		}
+		res, _, err := c.Object.DeleteMulti(ctx, opt)
+		if err != nil {
+			return err
+		}
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("delete %s error: %s", res.Errors[0].Key, res.Errors[0].Message)
+		}
+	}
+	return nil
+}
@@ -1,24 +1,370 @@
 package util
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"github.com/tencentyun/cos-go-sdk-v5"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
 )
 
-// CatObject 查看cos对象内容
-func CatObject(c *cos.Client, cosUrl StorageUrl) error {
-	opt := &cos.ObjectGetOptions{
-		ResponseContentType: "text/html",
+// 按行读取末尾内容时首次下载的长度，行数不足时逐次翻倍
+const catTailWindow = 64 * 1024
+
+// CatObjects 查看cos对象内容，recursive时按key顺序依次输出前缀下匹配include/exclude的所有对象
+func CatObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	if _, _, err := parseCatRange(fo.Operation.Range); err != nil {
+		return err
+	}
+	prefix := cosUrl.(*CosUrl).Object
+	if !fo.Operation.Recursive {
+		return CatObject(c, prefix, fo)
+	}
+
+	bucketName := cosUrl.(*CosUrl).Bucket
+	bucketType, err := GetBucketType(c, fo.Param, fo.Config, bucketName)
+	if err != nil {
+		return err
+	}
+	var catErr error
+	handle := func(object cos.Object) {
+		if catErr != nil {
+			return
+		}
+		if fo.Interrupted() {
+			catErr = ErrInterrupted
+			return
+		}
+		if err := CatObject(c, object.Key, fo); err != nil {
+			catErr = fmt.Errorf("cat %s error: %v", getCosUrl(bucketName, object.Key), err)
+		}
+	}
+	if bucketType == BucketTypeOfs {
+		err = walkOfsObjects(c, prefix, fo, handle)
+	} else {
+		err = walkCosObjects(c, cosUrl, fo, handle)
+	}
+	if err != nil {
+		return err
+	}
+	return catErr
+}
+
+// CatObject 输出单个对象内容，指定主密钥时解密客户端加密对象，指定decompress时解压压缩对象
+// 支持按字节区间、开头或末尾的字节数及行数读取
+func CatObject(c *cos.Client, object string, fo *FileOperations) error {
+	src := &catSource{c: c, object: object, fo: fo}
+	op := fo.Operation
+	// 未指定主密钥时无需解密，读取末尾字节直接使用后缀区间，不查询对象大小
+	if n := catSuffixLength(op); n > 0 && len(op.ClientEncryptKey) == 0 && !op.Decompress {
+		return src.copySuffix(n)
+	}
+	partial := op.Range != "" || op.Head > 0 || op.Tail > 0
+	if partial || len(op.ClientEncryptKey) > 0 {
+		if err := src.head(); err != nil {
+			return err
+		}
+	}
+
+	// 压缩流无法按区间读取，仅支持从头读取
+	if op.Decompress && src.env == nil {
+		r, err := src.openDecompressed()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return catHead(r, op.Head, op.Lines)
+	}
+
+	switch {
+	case op.Range != "":
+		start, end, _ := parseCatRange(op.Range)
+		if start < 0 {
+			start = src.size + start
+		}
+		if end < 0 {
+			end = src.size
+		}
+		return src.copyRange(start, end)
+	case op.Head > 0 && !op.Lines:
+		return src.copyRange(0, op.Head)
+	case op.Tail > 0 && !op.Lines:
+		return src.copyRange(src.size-op.Tail, src.size)
+	case op.Head > 0:
+		r, err := src.open(0, src.size)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return catHead(r, op.Head, true)
+	case op.Tail > 0:
+		return src.tailLines(op.Tail)
+	}
+
+	r, err := src.openFull()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(os.Stdout, r)
+	return err
+}
+
+// parseCatRange 解析start-end、start-及-n格式的字节区间，返回明文区间[start, end)
+// 后缀区间-n返回start为-n；end为-1表示读取到末尾
+func parseCatRange(r string) (start, end int64, err error) {
+	if r == "" {
+		return 0, -1, nil
+	}
+	invalid := fmt.Errorf("invalid range %s, the format is start-end, start- or -n", r)
+	parts := strings.SplitN(r, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, invalid
+	}
+	if parts[0] == "" {
+		n, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, invalid
+		}
+		return -n, -1, nil
+	}
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, invalid
+	}
+	if parts[1] == "" {
+		return start, -1, nil
+	}
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, invalid
+	}
+	return start, end + 1, nil
+}
+
+// catSuffixLength 按末尾字节数读取时返回字节数，否则返回0
+func catSuffixLength(op Operation) int64 {
+	if op.Tail > 0 && !op.Lines {
+		return op.Tail
+	}
+	if start, _, _ := parseCatRange(op.Range); start < 0 {
+		return -start
+	}
+	return 0
+}
+
+// catSource 对象明文内容，客户端加密对象按明文区间换算密文区间读取
+type catSource struct {
+	c      *cos.Client
+	object string
+	fo     *FileOperations
+	size   int64
+	env    *clientEnvelope
+}
+
+func (s *catSource) versionId() []string {
+	if s.fo.Operation.VersionId == "" {
+		return nil
+	}
+	return []string{s.fo.Operation.VersionId}
+}
+
+func (s *catSource) getOptions() *cos.ObjectGetOptions {
+	return &cos.ObjectGetOptions{
+		XCosSSECustomerAglo:   s.fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:    s.fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5: s.fo.Operation.SSECustomerKeyMD5,
+		XOptionHeader:         &http.Header{},
+	}
+}
+
+func (s *catSource) head() error {
+	resp, err := s.c.Object.Head(context.Background(), s.object, &cos.ObjectHeadOptions{
+		XCosSSECustomerAglo:   s.fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:    s.fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5: s.fo.Operation.SSECustomerKeyMD5,
+	}, s.versionId()...)
+	if err != nil {
+		return err
+	}
+	s.size = resp.ContentLength
+	if len(s.fo.Operation.ClientEncryptKey) > 0 && isClientEncrypted(resp.Header) {
+		s.env, err = parseClientEnvelope(resp.Header, s.fo.Operation.ClientEncryptKey)
+		if err != nil {
+			return err
+		}
+		s.size = s.env.plainSize
+	}
+	return nil
+}
+
+// openFull 读取完整内容，客户端加密对象校验末尾分块
+func (s *catSource) openFull() (io.ReadCloser, error) {
+	resp, err := s.c.Object.Get(context.Background(), s.object, s.getOptions(), s.versionId()...)
+	if err != nil {
+		return nil, err
+	}
+	if s.env == nil {
+		return resp.Body, nil
+	}
+	return &catReadCloser{Reader: newClientDecryptReader(resp.Body, s.env, 0, 0, -1), Closer: resp.Body}, nil
+}
+
+func (s *catSource) openDecompressed() (io.ReadCloser, error) {
+	opt := s.getOptions()
+	opt.XOptionHeader.Set("Accept-Encoding", "identity")
+	resp, err := s.c.Object.Get(context.Background(), s.object, opt, s.versionId()...)
+	if err != nil {
+		return nil, err
+	}
+	if !isCompressed(resp.Header) {
+		return resp.Body, nil
+	}
+	zr, err := newDecompressReader(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return &catReadCloser{Reader: zr, Closer: resp.Body}, nil
+}
+
+// open 读取明文区间[start, end)
+func (s *catSource) open(start, end int64) (io.ReadCloser, error) {
+	if start < 0 {
+		start = 0
 	}
-	res, err := c.Object.Get(context.Background(), cosUrl.(*CosUrl).Object, opt)
+	if end > s.size {
+		end = s.size
+	}
+	if end <= start {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+	opt := s.getOptions()
+	if s.env == nil {
+		opt.Range = fmt.Sprintf("bytes=%d-%d", start, end-1)
+		resp, err := s.c.Object.Get(context.Background(), s.object, opt, s.versionId()...)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}
+	cipherStart, cipherEnd, firstChunk, skip := s.env.cipherRange(start, end)
+	opt.Range = fmt.Sprintf("bytes=%d-%d", cipherStart, cipherEnd-1)
+	resp, err := s.c.Object.Get(context.Background(), s.object, opt, s.versionId()...)
+	if err != nil {
+		return nil, err
+	}
+	return &catReadCloser{Reader: newClientDecryptReader(resp.Body, s.env, firstChunk, skip, end-start), Closer: resp.Body}, nil
+}
+
+func (s *catSource) copyRange(start, end int64) error {
+	r, err := s.open(start, end)
 	if err != nil {
 		return err
 	}
+	defer r.Close()
+	_, err = io.Copy(os.Stdout, r)
+	return err
+}
 
-	io.Copy(os.Stdout, res.Body)
-	fmt.Printf("\n")
+// copySuffix 输出末尾n字节，空对象不支持区间读取，返回416时不输出
+func (s *catSource) copySuffix(n int64) error {
+	opt := s.getOptions()
+	opt.Range = fmt.Sprintf("bytes=-%d", n)
+	resp, err := s.c.Object.Get(context.Background(), s.object, opt, s.versionId()...)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			return nil
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(os.Stdout, resp.Body)
+	return err
+}
+
+// tailLines 输出末尾n行，末尾的换行符不计为新的一行
+func (s *catSource) tailLines(n int64) error {
+	for window := int64(catTailWindow); ; window *= 2 {
+		start := s.size - window
+		if start < 0 {
+			start = 0
+		}
+		r, err := s.open(start, s.size)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		body := bytes.TrimSuffix(data, []byte("\n"))
+		pos := len(body)
+		found := int64(0)
+		for found < n && pos > 0 {
+			pos = bytes.LastIndexByte(body[:pos], '\n')
+			if pos < 0 {
+				break
+			}
+			found++
+		}
+		if found == n {
+			_, err = os.Stdout.Write(data[pos+1:])
+			return err
+		}
+		if start == 0 {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+	}
+}
+
+// catHead 输出开头n字节或n行，n为0时输出全部
+func catHead(r io.Reader, n int64, lines bool) error {
+	if n <= 0 {
+		_, err := io.Copy(os.Stdout, r)
+		return err
+	}
+	if !lines {
+		_, err := io.CopyN(os.Stdout, r, n)
+		if err == io.EOF {
+			err = nil
+		}
+		return err
+	}
+	buf := make([]byte, 32*1024)
+	for n > 0 {
+		m, err := r.Read(buf)
+		data := buf[:m]
+		for i, b := range data {
+			if b == '\n' {
+				n--
+				if n == 0 {
+					data = data[:i+1]
+					break
+				}
+			}
+		}
+		if _, werr := os.Stdout.Write(data); werr != nil {
+			return werr
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+type catReadCloser struct {
+	io.Reader
+	io.Closer
+}
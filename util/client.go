@@ -19,6 +19,21 @@ var secretID, secretKey, secretToken string
 // - client *cos.Client: 创建的客户端实例
 // - err error: 错误信息
 func NewClient(config *Config, param *Param, bucketName string, options ...*FileOperations) (client *cos.Client, err error) {
+	// 交互式shell中复用相同存储桶及传输选项的客户端
+	// 临时密钥的客户端缓存至密钥过期前，未缓存过期时间时视为永不过期
+	var expire time.Time
+	if sessionCache != nil {
+		cacheKey := sessionCache.clientKey(param, bucketName, options...)
+		if client = sessionCache.getClient(cacheKey); client != nil {
+			return client, nil
+		}
+		defer func() {
+			if err == nil {
+				sessionCache.putClient(cacheKey, client, expire)
+			}
+		}()
+	}
+
 	if config.Base.Mode == "CvmRole" {
 		// 若使用 CvmRole 方式，则需请求请求CAM的服务，获取临时密钥
 		data, err = CamAuth(config.Base.CvmRoleName)
@@ -28,6 +43,7 @@ func NewClient(config *Config, param *Param, bucketName string, options ...*File
 		secretID = data.TmpSecretId
 		secretKey = data.TmpSecretKey
 		secretToken = data.Token
+		expire = time.Unix(int64(data.ExpiredTime), 0).Add(-sessionCredentialMargin)
 	} else {
 		// SecretKey 方式则直接获取用户配置文件中设置的密钥
 		secretID = config.Base.SecretID
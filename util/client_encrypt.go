@@ -0,0 +1,521 @@
+package util
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// ClientEncryptAlgorithm 客户端加密算法，数据密钥按固定大小分块做AES-256-GCM
+	ClientEncryptAlgorithm = "AES256-GCM-CHUNKED"
+	// 明文分块大小
+	clientEncryptChunkSize = 64 * 1024
+	// GCM认证标签长度
+	clientEncryptTagSize = 16
+	clientEncryptKeySize = 32
+
+	// 客户端加密相关的自定义元数据
+	metaClientEncryptKey        = "x-cos-meta-coscli-cek"
+	metaClientEncryptAlg        = "x-cos-meta-coscli-cek-alg"
+	metaClientEncryptChunkSize  = "x-cos-meta-coscli-chunk-size"
+	metaClientEncryptMasterKey  = "x-cos-meta-coscli-master-key-id"
+	metaClientEncryptPlainSize  = "x-cos-meta-coscli-unencrypted-content-length"
+	metaClientEncryptPlainCrc64 = "x-cos-meta-coscli-unencrypted-crc64"
+)
+
+// LoadMasterKey 读取客户端加密主密钥文件，支持32字节原始密钥及其hex、base64编码
+func LoadMasterKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read master key file error: %v", err)
+	}
+	if len(data) == clientEncryptKeySize {
+		return data, nil
+	}
+	text := strings.TrimSpace(string(data))
+	if key, err := hex.DecodeString(text); err == nil && len(key) == clientEncryptKeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(text); err == nil && len(key) == clientEncryptKeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("master key must be 32 bytes, raw or encoded in hex or base64")
+}
+
+// masterKeyId 主密钥标识，用于区分对象由哪个主密钥加密
+func masterKeyId(masterKey []byte) string {
+	sum := sha256.Sum256(masterKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// clientEnvelope 对象的信封加密信息
+type clientEnvelope struct {
+	aead       cipher.AEAD
+	wrappedKey string
+	keyId      string
+	chunkSize  int64
+	plainSize  int64
+	plainCrc64 string
+}
+
+// newClientEnvelope 为对象生成随机数据密钥，并用主密钥加密
+func newClientEnvelope(masterKey []byte, plainSize int64, plainCrc64 string) (*clientEnvelope, error) {
+	dataKey := make([]byte, clientEncryptKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	kek, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, kek.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &clientEnvelope{
+		aead:       aead,
+		wrappedKey: base64.StdEncoding.EncodeToString(kek.Seal(nonce, nonce, dataKey, nil)),
+		keyId:      masterKeyId(masterKey),
+		chunkSize:  clientEncryptChunkSize,
+		plainSize:  plainSize,
+		plainCrc64: plainCrc64,
+	}, nil
+}
+
+// isClientEncrypted 对象是否由coscli客户端加密
+func isClientEncrypted(header http.Header) bool {
+	return header.Get(metaClientEncryptAlg) != ""
+}
+
+// parseClientEnvelope 从对象元数据中解析信封，并用主密钥解出数据密钥
+func parseClientEnvelope(header http.Header, masterKey []byte) (*clientEnvelope, error) {
+	alg := header.Get(metaClientEncryptAlg)
+	if alg != ClientEncryptAlgorithm {
+		return nil, fmt.Errorf("unsupported client encryption algorithm: %s", alg)
+	}
+	chunkSize, err := strconv.ParseInt(header.Get(metaClientEncryptChunkSize), 10, 64)
+	if err != nil || chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid client encryption chunk size: %s", header.Get(metaClientEncryptChunkSize))
+	}
+	plainSize, err := strconv.ParseInt(header.Get(metaClientEncryptPlainSize), 10, 64)
+	if err != nil || plainSize < 0 {
+		return nil, fmt.Errorf("invalid unencrypted content length: %s", header.Get(metaClientEncryptPlainSize))
+	}
+	keyId := header.Get(metaClientEncryptMasterKey)
+	if keyId != "" && keyId != masterKeyId(masterKey) {
+		return nil, fmt.Errorf("object is encrypted with master key %s, but the given master key is %s", keyId, masterKeyId(masterKey))
+	}
+
+	wrapped, err := base64.StdEncoding.DecodeString(header.Get(metaClientEncryptKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped data key: %v", err)
+	}
+	kek, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < kek.NonceSize() {
+		return nil, fmt.Errorf("invalid wrapped data key")
+	}
+	dataKey, err := kek.Open(nil, wrapped[:kek.NonceSize()], wrapped[kek.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key error, the master key may be wrong: %v", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &clientEnvelope{
+		aead:       aead,
+		wrappedKey: header.Get(metaClientEncryptKey),
+		keyId:      keyId,
+		chunkSize:  chunkSize,
+		plainSize:  plainSize,
+		plainCrc64: header.Get(metaClientEncryptPlainCrc64),
+	}, nil
+}
+
+// setHeader 将信封写入对象元数据
+func (e *clientEnvelope) setHeader(header *http.Header) {
+	header.Set(metaClientEncryptKey, e.wrappedKey)
+	header.Set(metaClientEncryptAlg, ClientEncryptAlgorithm)
+	header.Set(metaClientEncryptChunkSize, strconv.FormatInt(e.chunkSize, 10))
+	header.Set(metaClientEncryptMasterKey, e.keyId)
+	header.Set(metaClientEncryptPlainSize, strconv.FormatInt(e.plainSize, 10))
+	header.Set(metaClientEncryptPlainCrc64, e.plainCrc64)
+}
+
+// chunks 分块数，空对象也有一个空的末尾分块，用于校验完整性
+func (e *clientEnvelope) chunks() int64 {
+	if e.plainSize == 0 {
+		return 1
+	}
+	return (e.plainSize + e.chunkSize - 1) / e.chunkSize
+}
+
+// cipherSize 密文总长度
+func (e *clientEnvelope) cipherSize() int64 {
+	return e.plainSize + e.chunks()*clientEncryptTagSize
+}
+
+// cipherRange 明文区间[start, end)对应的密文区间、起始分块及需要丢弃的前导明文长度
+func (e *clientEnvelope) cipherRange(start, end int64) (cipherStart, cipherEnd, firstChunk, skip int64) {
+	firstChunk = start / e.chunkSize
+	lastChunk := (end - 1) / e.chunkSize
+	if end <= start {
+		lastChunk = firstChunk
+	}
+	sealed := e.chunkSize + clientEncryptTagSize
+	cipherStart = firstChunk * sealed
+	cipherEnd = (lastChunk + 1) * sealed
+	if cipherEnd > e.cipherSize() {
+		cipherEnd = e.cipherSize()
+	}
+	return cipherStart, cipherEnd, firstChunk, start - firstChunk*e.chunkSize
+}
+
+// chunkNonce 分块nonce由分块序号生成，数据密钥每个对象唯一，不会重复
+func chunkNonce(index int64) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[4:], uint64(index))
+	return nonce
+}
+
+// chunkAAD 附加数据包含分块序号和是否末尾分块，防止分块被重排或截断
+func chunkAAD(index int64, last bool) []byte {
+	aad := make([]byte, 9)
+	binary.BigEndian.PutUint64(aad, uint64(index))
+	if last {
+		aad[8] = 1
+	}
+	return aad
+}
+
+// clientEncryptReader 按分块加密本地文件的一段，分块区间为[next, end)
+type clientEncryptReader struct {
+	src  io.ReaderAt
+	env  *clientEnvelope
+	next int64
+	end  int64
+	buf  []byte
+	out  []byte
+}
+
+func newClientEncryptReader(src io.ReaderAt, env *clientEnvelope, firstChunk, endChunk int64) *clientEncryptReader {
+	return &clientEncryptReader{
+		src:  src,
+		env:  env,
+		next: firstChunk,
+		end:  endChunk,
+		buf:  make([]byte, env.chunkSize),
+	}
+}
+
+func (r *clientEncryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.next >= r.end {
+			return 0, io.EOF
+		}
+		offset := r.next * r.env.chunkSize
+		length := r.env.chunkSize
+		if offset+length > r.env.plainSize {
+			length = r.env.plainSize - offset
+		}
+		n, err := r.src.ReadAt(r.buf[:length], offset)
+		if int64(n) != length {
+			if err == nil || err == io.EOF {
+				err = fmt.Errorf("file changed during encryption")
+			}
+			return 0, err
+		}
+		last := r.next == r.env.chunks()-1
+		r.out = r.env.aead.Seal(r.out[:0], chunkNonce(r.next), r.buf[:length], chunkAAD(r.next, last))
+		r.next++
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// clientDecryptReader 按分块解密密文流，firstChunk为密文流起始分块序号
+type clientDecryptReader struct {
+	src   io.Reader
+	env   *clientEnvelope
+	next  int64
+	skip  int64
+	left  int64
+	full  bool
+	buf   []byte
+	out   []byte
+	ended bool
+}
+
+// newClientDecryptReader 解密密文流，丢弃前skip字节明文，最多返回limit字节
+// limit小于0时读取到末尾分块，校验对象完整性
+func newClientDecryptReader(src io.Reader, env *clientEnvelope, firstChunk, skip, limit int64) io.Reader {
+	full := limit < 0
+	if full {
+		limit = env.plainSize
+	}
+	return &clientDecryptReader{
+		src:  src,
+		env:  env,
+		next: firstChunk,
+		skip: skip,
+		left: limit,
+		full: full,
+		buf:  make([]byte, env.chunkSize+clientEncryptTagSize),
+	}
+}
+
+func (r *clientDecryptReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.left <= 0 && (!r.full || r.ended) {
+			return 0, io.EOF
+		}
+		if r.ended || r.next >= r.env.chunks() {
+			return 0, io.ErrUnexpectedEOF
+		}
+		length := r.env.chunkSize
+		if plainLeft := r.env.plainSize - r.next*r.env.chunkSize; plainLeft < length {
+			length = plainLeft
+		}
+		sealed := r.buf[:length+clientEncryptTagSize]
+		if _, err := io.ReadFull(r.src, sealed); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		last := r.next == r.env.chunks()-1
+		plain, err := r.env.aead.Open(sealed[:0], chunkNonce(r.next), sealed, chunkAAD(r.next, last))
+		if err != nil {
+			return 0, fmt.Errorf("decrypt chunk %d error: %v", r.next, err)
+		}
+		r.next++
+		r.ended = last
+		if r.skip > 0 {
+			n := r.skip
+			if n > int64(len(plain)) {
+				n = int64(len(plain))
+			}
+			plain = plain[n:]
+			r.skip -= n
+		}
+		if int64(len(plain)) > r.left {
+			plain = plain[:r.left]
+		}
+		r.out = plain
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	r.left -= int64(n)
+	return n, nil
+}
+
+// clientEncryptUpload 客户端加密上传，不支持断点续传
+// 分块大小按加密分块对齐，各分块可独立加密并发上传
+func clientEncryptUpload(c *cos.Client, fo *FileOperations, localFilePath, cosPath string, opt *cos.MultiUploadOptions) error {
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	plainCrc64, _, err := CalculateHash(localFilePath, "crc64")
+	if err != nil {
+		return err
+	}
+	env, err := newClientEnvelope(fo.Operation.ClientEncryptKey, info.Size(), plainCrc64)
+	if err != nil {
+		return err
+	}
+
+	// 复制请求头，避免并发修改公共元数据
+	headerOpt := *opt.OptIni.ObjectPutHeaderOptions
+	meta := http.Header{}
+	if headerOpt.XCosMetaXXX != nil {
+		meta = headerOpt.XCosMetaXXX.Clone()
+	}
+	env.setHeader(&meta)
+	headerOpt.XCosMetaXXX = &meta
+	// 原始内容的MD5和长度不适用于密文
+	headerOpt.ContentMD5 = ""
+	headerOpt.ContentLength = 0
+
+	// 每个分块包含的加密分块数，分块数不超过10000
+	partChunks := opt.PartSize * 1024 * 1024 / env.chunkSize
+	if partChunks < 1 {
+		partChunks = 1
+	}
+	if min := (env.chunks() + 9999) / 10000; partChunks < min {
+		partChunks = min
+	}
+
+	// 中断时取消请求，中止分块上传时使用独立的context
+	ctx := fo.Context()
+	if env.chunks() <= partChunks {
+		headerOpt.ContentLength = env.cipherSize()
+		_, err = c.Object.Put(ctx, cosPath, newClientEncryptReader(f, env, 0, env.chunks()), &cos.ObjectPutOptions{
+			ACLHeaderOptions:       opt.OptIni.ACLHeaderOptions,
+			ObjectPutHeaderOptions: &headerOpt,
+		})
+		return err
+	}
+
+	res, _, err := c.Object.InitiateMultipartUpload(ctx, cosPath, &cos.InitiateMultipartUploadOptions{
+		ACLHeaderOptions:       opt.OptIni.ACLHeaderOptions,
+		ObjectPutHeaderOptions: &headerOpt,
+	})
+	if err != nil {
+		return err
+	}
+
+	partNum := int((env.chunks() + partChunks - 1) / partChunks)
+	parts := make([]cos.Object, partNum)
+	chParts := make(chan int, partNum)
+	for i := 0; i < partNum; i++ {
+		chParts <- i
+	}
+	close(chParts)
+
+	threadNum := opt.ThreadPoolSize
+	if threadNum < 1 {
+		threadNum = 1
+	}
+	var wg sync.WaitGroup
+	var once sync.Once
+	var partErr error
+	for i := 0; i < threadNum; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range chParts {
+				first := int64(i) * partChunks
+				end := first + partChunks
+				if end > env.chunks() {
+					end = env.chunks()
+				}
+				cipherStart := first * (env.chunkSize + clientEncryptTagSize)
+				cipherEnd := env.cipherSize()
+				if end < env.chunks() {
+					cipherEnd = end * (env.chunkSize + clientEncryptTagSize)
+				}
+				resp, err := c.Object.UploadPart(ctx, cosPath, res.UploadID, i+1, newClientEncryptReader(f, env, first, end), &cos.ObjectUploadPartOptions{
+					ContentLength:         cipherEnd - cipherStart,
+					XCosSSECustomerAglo:   headerOpt.XCosSSECustomerAglo,
+					XCosSSECustomerKey:    headerOpt.XCosSSECustomerKey,
+					XCosSSECustomerKeyMD5: headerOpt.XCosSSECustomerKeyMD5,
+					XCosTrafficLimit:      headerOpt.XCosTrafficLimit,
+				})
+				if err != nil {
+					once.Do(func() { partErr = fmt.Errorf("upload part %d error: %v", i+1, err) })
+					return
+				}
+				parts[i] = cos.Object{PartNumber: i + 1, ETag: resp.Header.Get("ETag")}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if partErr != nil {
+		c.Object.AbortMultipartUpload(context.Background(), cosPath, res.UploadID)
+		return partErr
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	_, _, err = c.Object.CompleteMultipartUpload(ctx, cosPath, res.UploadID, &cos.CompleteMultipartUploadOptions{Parts: parts})
+	if err != nil {
+		c.Object.AbortMultipartUpload(context.Background(), cosPath, res.UploadID)
+		return err
+	}
+	return nil
+}
+
+// clientDecryptDownload 下载并解密客户端加密对象到本地文件
+func clientDecryptDownload(ctx context.Context, c *cos.Client, object, localFilePath string, env *clientEnvelope, opt *cos.ObjectGetOptions,
+	versionId ...string) (*cos.Response, error) {
+	resp, err := c.Object.Get(ctx, object, opt, versionId...)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.OpenFile(localFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return resp, err
+	}
+	n, err := io.Copy(f, newClientDecryptReader(resp.Body, env, 0, 0, -1))
+	if err == nil && n != env.plainSize {
+		err = fmt.Errorf("decrypted size mismatch, expected %d, got %d", env.plainSize, n)
+	}
+	if err == nil {
+		// 密文末尾不应有多余数据
+		var extra [1]byte
+		if m, _ := resp.Body.Read(extra[:]); m > 0 {
+			err = fmt.Errorf("unexpected data after the last encrypted chunk")
+		}
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return resp, err
+}
+
+// syncCrc64 sync比较使用的cos端crc64
+// 压缩或客户端加密对象使用原始内容的crc64；状态与本次操作不一致时返回空，不跳过
+func syncCrc64(header http.Header, fo *FileOperations, upload bool) string {
+	// 压缩对象比较压缩前的crc64，压缩格式与本次操作不一致时不跳过
+	if upload && fo.Operation.Compress != "" {
+		if header.Get("Content-Encoding") != fo.Operation.Compress {
+			return ""
+		}
+		return header.Get(metaOriginalCrc64)
+	}
+	if !upload && fo.Operation.Decompress && isCompressed(header) {
+		return header.Get(metaOriginalCrc64)
+	}
+	encrypted := isClientEncrypted(header)
+	withKey := len(fo.Operation.ClientEncryptKey) > 0
+	if encrypted && withKey {
+		if header.Get(metaClientEncryptMasterKey) != masterKeyId(fo.Operation.ClientEncryptKey) {
+			return ""
+		}
+		return header.Get(metaClientEncryptPlainCrc64)
+	}
+	// 上传时加密状态不一致需要重新上传；未指定密钥下载时本地为密文，比较密文crc64
+	if upload && encrypted != withKey {
+		return ""
+	}
+	return header.Get("x-cos-hash-crc64ecma")
+}
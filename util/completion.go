@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 补全时列出对象的超时时间，避免按下Tab后长时间无响应
+	completionTimeout = 3 * time.Second
+	// 补全结果缓存有效期，连续按Tab时无需重复请求
+	completionCacheTTL = 30 * time.Second
+)
+
+// ListCompletionKeys 列出前缀下一级的对象及目录，用于补全
+func ListCompletionKeys(c *cos.Client, prefix string, limit int) ([]string, error) {
+	cacheFile := completionCacheFile(c, prefix, limit)
+	if keys, ok := readCompletionCache(cacheFile); ok {
+		return keys, nil
+	}
+
+	// 补全时不重试，超时直接返回
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	res, _, err := c.Bucket.Get(ctx, &cos.BucketGetOptions{
+		Prefix:       prefix,
+		Delimiter:    CosSeparator,
+		EncodingType: "url",
+		MaxKeys:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, commonPrefix := range res.CommonPrefixes {
+		commonPrefix, _ = url.QueryUnescape(commonPrefix)
+		keys = append(keys, commonPrefix)
+	}
+	for _, object := range res.Contents {
+		object.Key, _ = url.QueryUnescape(object.Key)
+		if object.Key != prefix {
+			keys = append(keys, object.Key)
+		}
+	}
+
+	writeCompletionCache(cacheFile, keys)
+	return keys, nil
+}
+
+// completionCacheFile 补全缓存文件路径，位于用户缓存目录下，按存储桶地址及前缀区分；无法获取缓存目录时返回空，不使用缓存
+func completionCacheFile(c *cos.Client, prefix string, limit int) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	bucketUrl := ""
+	if c.BaseURL != nil && c.BaseURL.BucketURL != nil {
+		bucketUrl = c.BaseURL.BucketURL.String()
+	}
+	sum := md5.Sum([]byte(bucketUrl + "|" + prefix + "|" + strconv.Itoa(limit)))
+	return filepath.Join(cacheDir, "coscli", "completion", hex.EncodeToString(sum[:]))
+}
+
+func readCompletionCache(cacheFile string) ([]string, bool) {
+	if cacheFile == "" {
+		return nil, false
+	}
+	info, err := os.Stat(cacheFile)
+	if err != nil || time.Since(info.ModTime()) > completionCacheTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return nil, false
+	}
+	var keys []string
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, false
+	}
+	return keys, true
+}
+
+// writeCompletionCache 写入补全缓存，失败时忽略
+func writeCompletionCache(cacheFile string, keys []string) {
+	if cacheFile == "" {
+		return
+	}
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(cacheFile), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(cacheFile, data, 0600)
+}
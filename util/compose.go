@@ -0,0 +1,373 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 除最后一块外，分块大小不能小于1MB
+	composeMinPartSize int64 = 1024 * 1024
+	// 单次UploadPartCopy最大5GB
+	composeMaxCopySize int64 = 5 * 1024 * 1024 * 1024
+	composeMaxParts          = 10000
+)
+
+// composeSource 合并的源对象
+type composeSource struct {
+	url       *CosUrl
+	client    *cos.Client
+	sourceURL string
+	size      int64
+	etag      string
+	crc64     uint64
+	hasCrc64  bool
+}
+
+// composeSegment 源对象中的一段
+type composeSegment struct {
+	source *composeSource
+	offset int64
+	length int64
+}
+
+// composePart 目标对象的一个分块，copy为true时使用UploadPartCopy，否则下载后重新上传
+type composePart struct {
+	number   int
+	copy     bool
+	segments []composeSegment
+	etag     string
+}
+
+// ListComposeSources 按对象键顺序列出前缀下的源对象
+func ListComposeSources(c *cos.Client, cosUrl StorageUrl, bucketType string, fo *FileOperations) ([]StorageUrl, error) {
+	var keys []string
+	handle := func(object cos.Object) {
+		keys = append(keys, object.Key)
+	}
+	var err error
+	if bucketType == BucketTypeOfs {
+		err = walkOfsObjects(c, cosUrl.(*CosUrl).Object, fo, handle)
+		// ofs桶按目录返回，重新按对象键排序
+		sort.Strings(keys)
+	} else {
+		err = walkCosObjects(c, cosUrl, fo, handle)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	srcUrls := make([]StorageUrl, 0, len(keys))
+	for _, key := range keys {
+		srcUrls = append(srcUrls, &CosUrl{Bucket: cosUrl.(*CosUrl).Bucket, Object: key})
+	}
+	return srcUrls, nil
+}
+
+// ComposeObjects 将多个源对象按顺序在服务端合并为目标对象
+func ComposeObjects(c *cos.Client, destUrl StorageUrl, srcUrls []StorageUrl, fo *FileOperations) error {
+	destKey := destUrl.(*CosUrl).Object
+	sources, err := headComposeSources(srcUrls, fo)
+	if err != nil {
+		return err
+	}
+
+	parts := planComposeParts(sources)
+	if len(parts) == 0 {
+		return fmt.Errorf("all source objects are empty")
+	}
+	if len(parts) > composeMaxParts {
+		return fmt.Errorf("too many parts to compose: %d, should not be more than %d", len(parts), composeMaxParts)
+	}
+
+	copyParts := 0
+	for _, part := range parts {
+		if part.copy {
+			copyParts++
+		}
+	}
+	logger.Infof("Compose %d objects into %s with %d parts (%d copied, %d re-uploaded)", len(sources), getCosUrl(destUrl.(*CosUrl).Bucket, destKey), len(parts), copyParts, len(parts)-copyParts)
+
+	initOpt := &cos.InitiateMultipartUploadOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
+			XCosStorageClass: fo.Operation.StorageClass,
+		},
+	}
+	res, _, err := c.Object.InitiateMultipartUpload(fo.Context(), destKey, initOpt)
+	if err != nil {
+		return fmt.Errorf("initiate multipart upload error : %v", err)
+	}
+	uploadId := res.UploadID
+
+	// 中止分块上传时使用独立的context，中断后仍可清理
+	if err = runComposeParts(c, destKey, uploadId, parts, fo); err != nil {
+		_, _ = c.Object.AbortMultipartUpload(context.Background(), destKey, uploadId)
+		return err
+	}
+
+	completeOpt := &cos.CompleteMultipartUploadOptions{}
+	for _, part := range parts {
+		completeOpt.Parts = append(completeOpt.Parts, cos.Object{PartNumber: part.number, ETag: part.etag})
+	}
+	_, _, err = c.Object.CompleteMultipartUpload(fo.Context(), destKey, uploadId, completeOpt)
+	if err != nil {
+		_, _ = c.Object.AbortMultipartUpload(context.Background(), destKey, uploadId)
+		return fmt.Errorf("complete multipart upload error : %v", err)
+	}
+
+	resp, err := GetHead(c, destKey)
+	if err != nil {
+		return fmt.Errorf("head composed object error : %v", err)
+	}
+	return verifyComposeCrc64(sources, resp)
+}
+
+// headComposeSources 并发获取源对象的大小、etag及crc64
+func headComposeSources(srcUrls []StorageUrl, fo *FileOperations) ([]*composeSource, error) {
+	sources := make([]*composeSource, len(srcUrls))
+	clients := make(map[string]*cos.Client)
+	for i, srcUrl := range srcUrls {
+		cosUrl := srcUrl.(*CosUrl)
+		client, ok := clients[cosUrl.Bucket]
+		if !ok {
+			var err error
+			client, err = NewClient(fo.Config, fo.Param, cosUrl.Bucket)
+			if err != nil {
+				return nil, err
+			}
+			clients[cosUrl.Bucket] = client
+		}
+		sources[i] = &composeSource{
+			url:       cosUrl,
+			client:    client,
+			sourceURL: fmt.Sprintf("%s/%s", client.BaseURL.BucketURL.Host, cosUrl.Object),
+		}
+	}
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var headErr error
+	chSources := make(chan *composeSource)
+	for i := 0; i < composeRoutines(fo.Operation.Routines); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for source := range chSources {
+				resp, err := GetHead(source.client, source.url.Object)
+				if err != nil {
+					lock.Lock()
+					headErr = fmt.Errorf("head %s error : %v", getCosUrl(source.url.Bucket, source.url.Object), err)
+					lock.Unlock()
+					continue
+				}
+				source.size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+				source.etag = resp.Header.Get("ETag")
+				if crc := resp.Header.Get("x-cos-hash-crc64ecma"); crc != "" {
+					source.crc64, err = strconv.ParseUint(crc, 10, 64)
+					source.hasCrc64 = err == nil
+				}
+			}
+		}()
+	}
+	for _, source := range sources {
+		chSources <- source
+	}
+	close(chSources)
+	wg.Wait()
+
+	return sources, headErr
+}
+
+// planComposeParts 规划分块：不小于1MB的部分使用UploadPartCopy，过小的部分与相邻数据合并后重新上传
+func planComposeParts(sources []*composeSource) []*composePart {
+	var parts []*composePart
+	var pending []composeSegment
+	var pendingSize int64
+
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		parts = append(parts, &composePart{number: len(parts) + 1, segments: pending})
+		pending = nil
+		pendingSize = 0
+	}
+	addPending := func(segment composeSegment) {
+		pending = append(pending, segment)
+		pendingSize += segment.length
+		if pendingSize >= composeMinPartSize {
+			flush()
+		}
+	}
+
+	for _, source := range sources {
+		if source.size == 0 {
+			continue
+		}
+		offset := int64(0)
+
+		// 待上传的数据不足1MB时，从当前对象中补足
+		if pendingSize > 0 {
+			need := composeMinPartSize - pendingSize
+			if source.size-need < composeMinPartSize {
+				addPending(composeSegment{source: source, offset: 0, length: source.size})
+				continue
+			}
+			addPending(composeSegment{source: source, offset: 0, length: need})
+			offset = need
+		}
+
+		remaining := source.size - offset
+		if remaining < composeMinPartSize {
+			addPending(composeSegment{source: source, offset: offset, length: remaining})
+			continue
+		}
+
+		for remaining > 0 {
+			length := remaining
+			if length > composeMaxCopySize {
+				length = composeMaxCopySize
+				// 避免最后剩余部分不足1MB
+				if remaining-length < composeMinPartSize {
+					length = remaining - composeMinPartSize
+				}
+			}
+			parts = append(parts, &composePart{
+				number:   len(parts) + 1,
+				copy:     true,
+				segments: []composeSegment{{source: source, offset: offset, length: length}},
+			})
+			offset += length
+			remaining -= length
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// runComposeParts 并发合并各分块，出错或中断后不再处理新的分块
+func runComposeParts(c *cos.Client, destKey, uploadId string, parts []*composePart, fo *FileOperations) error {
+	ctx := fo.Context()
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	var partErr error
+	chParts := make(chan *composePart)
+	for i := 0; i < composeRoutines(fo.Operation.Routines); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range chParts {
+				var err error
+				if part.copy {
+					err = copyComposePart(ctx, c, destKey, uploadId, part)
+				} else {
+					err = uploadComposePart(ctx, c, destKey, uploadId, part)
+				}
+				if err != nil && !fo.Interrupted() {
+					lock.Lock()
+					partErr = fmt.Errorf("compose part %d error : %v", part.number, err)
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+	for _, part := range parts {
+		lock.Lock()
+		failed := partErr != nil
+		lock.Unlock()
+		if failed || fo.Interrupted() {
+			break
+		}
+		chParts <- part
+	}
+	close(chParts)
+	wg.Wait()
+
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
+	return partErr
+}
+
+func copyComposePart(ctx context.Context, c *cos.Client, destKey, uploadId string, part *composePart) error {
+	segment := part.segments[0]
+	opt := &cos.ObjectCopyPartOptions{
+		XCosCopySourceRange: fmt.Sprintf("bytes=%d-%d", segment.offset, segment.offset+segment.length-1),
+		// 源对象在合并过程中被修改时失败
+		XCosCopySourceIfMatch: segment.source.etag,
+	}
+	res, _, err := c.Object.CopyPart(ctx, destKey, uploadId, part.number, segment.source.sourceURL, opt)
+	if err != nil {
+		return err
+	}
+	part.etag = res.ETag
+	return nil
+}
+
+func uploadComposePart(ctx context.Context, c *cos.Client, destKey, uploadId string, part *composePart) error {
+	var buf bytes.Buffer
+	for _, segment := range part.segments {
+		header := &http.Header{}
+		header.Set("If-Match", segment.source.etag)
+		opt := &cos.ObjectGetOptions{
+			Range:         fmt.Sprintf("bytes=%d-%d", segment.offset, segment.offset+segment.length-1),
+			XOptionHeader: header,
+		}
+		resp, err := segment.source.client.Object.Get(ctx, segment.source.url.Object, opt)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(&buf, resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.Object.UploadPart(ctx, destKey, uploadId, part.number, bytes.NewReader(buf.Bytes()), nil)
+	if err != nil {
+		return err
+	}
+	part.etag = resp.Header.Get("ETag")
+	return nil
+}
+
+// verifyComposeCrc64 校验目标对象crc64与源对象拼接后的crc64是否一致
+func verifyComposeCrc64(sources []*composeSource, resp *cos.Response) error {
+	var crc uint64
+	for _, source := range sources {
+		if !source.hasCrc64 {
+			logger.Warningf("Source %s has no crc64, skip crc64 verification", getCosUrl(source.url.Bucket, source.url.Object))
+			return nil
+		}
+		crc = crc64Combine(crc, source.crc64, source.size)
+	}
+
+	destCrc := resp.Header.Get("x-cos-hash-crc64ecma")
+	if destCrc == "" {
+		logger.Warningf("Composed object has no crc64, skip crc64 verification")
+		return nil
+	}
+	if destCrc != strconv.FormatUint(crc, 10) {
+		return fmt.Errorf("crc64 verification failed, composed object: %s, expected: %d", destCrc, crc)
+	}
+	logger.Infof("Compose success, crc64: %s", destCrc)
+	return nil
+}
+
+func composeRoutines(routines int) int {
+	if routines <= 0 {
+		return 1
+	}
+	return routines
+}
@@ -0,0 +1,266 @@
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	CompressGzip = "gzip"
+	CompressZstd = "zstd"
+
+	// 压缩前的文件大小和crc64，用于下载校验及sync比较
+	metaOriginalSize  = "x-cos-meta-original-size"
+	metaOriginalCrc64 = "x-cos-meta-original-crc64"
+)
+
+// CheckCompressFormat 校验压缩格式
+func CheckCompressFormat(format string) error {
+	switch format {
+	case CompressGzip, CompressZstd:
+		return nil
+	}
+	return fmt.Errorf("--compress must be either 'gzip' or 'zstd'")
+}
+
+// CompressExt 压缩格式对应的文件扩展名
+func CompressExt(format string) string {
+	if format == CompressZstd {
+		return ".zst"
+	}
+	return ".gz"
+}
+
+func newCompressWriter(w io.Writer, format string) (io.WriteCloser, error) {
+	if format == CompressZstd {
+		return zstd.NewWriter(w)
+	}
+	return gzip.NewWriter(w), nil
+}
+
+// isCompressed 对象是否为支持解压的压缩格式
+func isCompressed(header http.Header) bool {
+	encoding := header.Get("Content-Encoding")
+	return encoding == CompressGzip || encoding == CompressZstd
+}
+
+func newDecompressReader(r io.Reader, encoding string) (io.ReadCloser, error) {
+	if encoding == CompressZstd {
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return gzip.NewReader(r)
+}
+
+// compressUpload 边压缩边上传，压缩后不足一个分块时简单上传，否则分块并发上传
+func compressUpload(c *cos.Client, fo *FileOperations, localFilePath, cosPath string, opt *cos.MultiUploadOptions) error {
+	f, err := os.Open(localFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	originalCrc64, _, err := CalculateHash(localFilePath, "crc64")
+	if err != nil {
+		return err
+	}
+
+	// 复制请求头，避免并发修改公共元数据
+	headerOpt := *opt.OptIni.ObjectPutHeaderOptions
+	meta := http.Header{}
+	if headerOpt.XCosMetaXXX != nil {
+		meta = headerOpt.XCosMetaXXX.Clone()
+	}
+	meta.Set(metaOriginalSize, strconv.FormatInt(info.Size(), 10))
+	meta.Set(metaOriginalCrc64, originalCrc64)
+	headerOpt.XCosMetaXXX = &meta
+	headerOpt.ContentEncoding = fo.Operation.Compress
+	headerOpt.ContentMD5 = ""
+	headerOpt.ContentLength = 0
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		zw, err := newCompressWriter(pw, fo.Operation.Compress)
+		if err == nil {
+			_, err = io.Copy(zw, f)
+			if closeErr := zw.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		pw.CloseWithError(err)
+	}()
+
+	// 压缩后大小未知，按原始大小保证分块数不超过10000
+	partSize := opt.PartSize * 1024 * 1024
+	if min := info.Size()/9000 + 1; partSize < min {
+		partSize = min
+	}
+	return streamUpload(fo.Context(), c, cosPath, pr, opt.OptIni.ACLHeaderOptions, &headerOpt, partSize, opt.ThreadPoolSize)
+}
+
+// streamUpload 上传长度未知的数据流，不足一个分块时简单上传，否则分块并发上传
+// ctx取消时中止上传，中止分块上传时使用独立的context
+func streamUpload(ctx context.Context, c *cos.Client, cosPath string, r io.Reader, aclOpt *cos.ACLHeaderOptions,
+	headerOpt *cos.ObjectPutHeaderOptions, partSize int64, threadNum int) error {
+	buf := make([]byte, partSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		headerOpt.ContentLength = int64(n)
+		_, err = c.Object.Put(ctx, cosPath, bytes.NewReader(buf[:n]), &cos.ObjectPutOptions{
+			ACLHeaderOptions:       aclOpt,
+			ObjectPutHeaderOptions: headerOpt,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	res, _, err := c.Object.InitiateMultipartUpload(ctx, cosPath, &cos.InitiateMultipartUploadOptions{
+		ACLHeaderOptions:       aclOpt,
+		ObjectPutHeaderOptions: headerOpt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if threadNum < 1 {
+		threadNum = 1
+	}
+	// 限制同时在内存中的分块数
+	chBuf := make(chan struct{}, threadNum)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var parts []cos.Object
+	var partErr error
+	uploadPart := func(partNumber int, data []byte) {
+		defer wg.Done()
+		defer func() { <-chBuf }()
+		resp, err := c.Object.UploadPart(ctx, cosPath, res.UploadID, partNumber, bytes.NewReader(data), &cos.ObjectUploadPartOptions{
+			ContentLength:         int64(len(data)),
+			XCosSSECustomerAglo:   headerOpt.XCosSSECustomerAglo,
+			XCosSSECustomerKey:    headerOpt.XCosSSECustomerKey,
+			XCosSSECustomerKeyMD5: headerOpt.XCosSSECustomerKeyMD5,
+			XCosTrafficLimit:      headerOpt.XCosTrafficLimit,
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if partErr == nil {
+				partErr = fmt.Errorf("upload part %d error: %v", partNumber, err)
+			}
+			return
+		}
+		parts = append(parts, cos.Object{PartNumber: partNumber, ETag: resp.Header.Get("ETag")})
+	}
+
+	for partNumber := 1; ; partNumber++ {
+		chBuf <- struct{}{}
+		wg.Add(1)
+		go uploadPart(partNumber, buf[:n])
+
+		mu.Lock()
+		failed := partErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		buf = make([]byte, partSize)
+		n, err = io.ReadFull(r, buf)
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			err = nil
+			chBuf <- struct{}{}
+			wg.Add(1)
+			go uploadPart(partNumber+1, buf[:n])
+			break
+		}
+		if err != nil {
+			break
+		}
+	}
+	wg.Wait()
+
+	if err == nil {
+		err = partErr
+	}
+	if err != nil {
+		c.Object.AbortMultipartUpload(context.Background(), cosPath, res.UploadID)
+		return err
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].PartNumber < parts[j].PartNumber })
+	_, _, err = c.Object.CompleteMultipartUpload(ctx, cosPath, res.UploadID, &cos.CompleteMultipartUploadOptions{Parts: parts})
+	if err != nil {
+		c.Object.AbortMultipartUpload(context.Background(), cosPath, res.UploadID)
+		return err
+	}
+	return nil
+}
+
+// identityGetOptions 关闭传输层自动解压，按对象的Content-Encoding自行解压
+func identityGetOptions(trafficLimit int) *cos.ObjectGetOptions {
+	header := &http.Header{}
+	header.Set("Accept-Encoding", "identity")
+	return &cos.ObjectGetOptions{
+		XOptionHeader:    header,
+		XCosTrafficLimit: trafficLimit,
+	}
+}
+
+// decompressDownload 下载压缩对象并解压到本地文件，返回解压后的大小
+func decompressDownload(ctx context.Context, c *cos.Client, object, localFilePath string, opt *cos.ObjectGetOptions,
+	versionId ...string) (*cos.Response, int64, error) {
+	resp, err := c.Object.Get(ctx, object, opt, versionId...)
+	if err != nil {
+		return resp, 0, err
+	}
+	defer resp.Body.Close()
+
+	f, err := os.OpenFile(localFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return resp, 0, err
+	}
+	n, err := decompressTo(f, resp)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return resp, n, err
+}
+
+// decompressTo 解压响应内容写入w，存在原始大小元数据时校验大小
+func decompressTo(w io.Writer, resp *cos.Response) (int64, error) {
+	zr, err := newDecompressReader(resp.Body, resp.Header.Get("Content-Encoding"))
+	if err != nil {
+		return 0, err
+	}
+	defer zr.Close()
+	n, err := io.Copy(w, zr)
+	if err != nil {
+		return n, fmt.Errorf("decompress error: %v", err)
+	}
+	if originalSize := resp.Header.Get(metaOriginalSize); originalSize != "" && originalSize != strconv.FormatInt(n, 10) {
+		return n, fmt.Errorf("decompressed size mismatch, expected %s, got %d", originalSize, n)
+	}
+	return n, nil
+}
@@ -18,11 +18,12 @@ const (
 )
 
 const (
-	CommandCP      = "cp"
-	CommandSync    = "sync"
-	CommandLs      = "ls"
-	CommandRm      = "rm"
-	CommandRestore = "restore"
+	CommandCP         = "cp"
+	CommandSync       = "sync"
+	CommandLs         = "ls"
+	CommandRm         = "rm"
+	CommandRestore    = "restore"
+	CommandTransition = "transition"
 )
 
 const (
@@ -43,6 +44,7 @@ const (
 	MaxDeleteBatchCount int    = 1000
 	SnapshotConnector          = "==>"
 	OfsMaxRenderNum     int    = 100
+	MaxPostObjectSize   int64  = 5 * 1024 * 1024 * 1024
 )
 
 const (
@@ -73,8 +75,13 @@ const (
 )
 
 const (
-	ContentTypePolicy    = "policy"
-	ContentTypeInventory = "inventory"
+	ContentTypePolicy      = "policy"
+	ContentTypeInventory   = "inventory"
+	ContentTypeLifecycle   = "lifecycle"
+	ContentTypeCors        = "cors"
+	ContentTypeReferer     = "referer"
+	ContentTypeWebsite     = "website"
+	ContentTypeReplication = "replication"
 )
 
 const (
@@ -84,3 +91,34 @@ const (
 	SyncTypeCrc64          = "crc64"
 	SyncTypeSnapshot       = "snapshot"
 )
+
+const (
+	ReplicationStatusPending   = "PENDING"
+	ReplicationStatusCompleted = "COMPLETED"
+	ReplicationStatusFailed    = "FAILED"
+	ReplicationStatusReplica   = "REPLICA"
+	ReplicationStatusNone      = "NONE"
+)
+
+const (
+	ObjectLockModeCompliance = "COMPLIANCE"
+	ObjectLockModeGovernance = "GOVERNANCE"
+)
+
+const (
+	InventoryFormatCSV     = "CSV"
+	InventoryFormatORC     = "ORC"
+	InventoryFormatParquet = "PARQUET"
+)
+
+const (
+	OutputFormatTable = "table"
+	OutputFormatJson  = "json"
+	OutputFormatCsv   = "csv"
+)
+
+const (
+	DuSortBySize  = "size"
+	DuSortByCount = "count"
+	DuSortByName  = "name"
+)
@@ -57,7 +57,7 @@ func CosCopy(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUrl, fo *
 	CloseErrorOutputFile(fo)
 	CloseProcessLoggerFile(fo)
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
@@ -81,7 +81,12 @@ func batchCopyFiles(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUr
 		}
 	}()
 
-	if fo.BucketType == BucketTypeOfs {
+	if fo.Operation.FromInventory != "" {
+		// 扫描清单报告中对象大小及数量
+		go getInventoryObjectList(srcUrl, nil, nil, fo, true, false)
+		// 从清单报告获取对象列表
+		go getInventoryObjectList(srcUrl, chObjects, chListError, fo, false, true)
+	} else if fo.BucketType == BucketTypeOfs {
 		// 扫描ofs对象大小及数量
 		go getOfsObjectList(srcClient, srcUrl, nil, nil, fo, true, false)
 		// 获取ofs对象列表
@@ -124,6 +129,10 @@ func batchCopyFiles(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUr
 
 func copyFiles(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUrl, fo *FileOperations, chObjects <-chan objectInfoType, chError chan<- error, chLog chan<- string) {
 	for object := range chObjects {
+		// 中断后不再处理新的文件
+		if fo.Interrupted() {
+			continue
+		}
 		var skip, isDir bool
 		var err error
 		var size int64
@@ -155,6 +164,10 @@ func copyFiles(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUrl, fo
 			if err == nil {
 				break // Copy succeeded, break the loop
 			} else {
+				// 中断后不再重试
+				if fo.Interrupted() {
+					break
+				}
 				if fo.Operation.ErrRetryInterval == 0 {
 					// If the retry interval is not specified, retry after a random interval of 1~10 seconds.
 					sleepTime = time.Duration(rand.Intn(10)+1) * time.Second
@@ -310,7 +323,7 @@ func CosCopyWithDelete(srcClient, destClient *cos.Client, srcKeys, copyKeys map[
 	CloseErrorOutputFile(fo)
 	CloseProcessLoggerFile(fo)
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PutBucketCors 设置存储桶跨域规则
+func PutBucketCors(c *cos.Client, cors string) error {
+	configurationContent, err := GetContent(cors)
+	if err != nil {
+		return err
+	}
+	var opt cos.BucketPutCORSOptions
+	err = ParseContent(configurationContent, &opt, ContentTypeCors)
+	if err != nil {
+		return err
+	}
+	if len(opt.Rules) == 0 {
+		return fmt.Errorf("no cors rule provided")
+	}
+	_, err = c.Bucket.PutCORS(context.Background(), &opt)
+	if err != nil {
+		return err
+	}
+	logger.Info("Put Bucket Cors Success")
+	return nil
+}
+
+// GetBucketCors 查询存储桶跨域规则
+func GetBucketCors(c *cos.Client) error {
+	res, _, err := c.Bucket.GetCORS(context.Background())
+	if err != nil {
+		return err
+	}
+	// 渲染表格
+	renderCorsTable(res)
+	return nil
+}
+
+// DeleteBucketCors 删除存储桶跨域规则
+func DeleteBucketCors(c *cos.Client) error {
+	_, err := c.Bucket.DeleteCORS(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Delete Bucket Cors Success")
+	return nil
+}
+
+func renderCorsTable(cors *cos.BucketGetCORSResult) {
+	if cors == nil || len(cors.Rules) == 0 {
+		fmt.Println("No cors rule found")
+		return
+	}
+
+	// 创建表格
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Section", "Key", "Value"})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCaption(true, "Bucket Cors Information")
+
+	if cors.ResponseVary != "" {
+		table.Append([]string{"Cors", "ResponseVary", cors.ResponseVary})
+		table.Append([]string{"", "", ""})
+	}
+
+	// 遍历所有规则
+	for i, rule := range cors.Rules {
+		section := fmt.Sprintf("Rule #%d", i+1)
+
+		if rule.ID != "" {
+			table.Append([]string{section, "ID", rule.ID})
+		}
+		table.Append([]string{section, "AllowedOrigin", strings.Join(rule.AllowedOrigins, "\n")})
+		table.Append([]string{section, "AllowedMethod", strings.Join(rule.AllowedMethods, "\n")})
+		if len(rule.AllowedHeaders) > 0 {
+			table.Append([]string{section, "AllowedHeader", strings.Join(rule.AllowedHeaders, "\n")})
+		}
+		if len(rule.ExposeHeaders) > 0 {
+			table.Append([]string{section, "ExposeHeader", strings.Join(rule.ExposeHeaders, "\n")})
+		}
+		if rule.MaxAgeSeconds > 0 {
+			table.Append([]string{section, "MaxAgeSeconds", fmt.Sprintf("%d", rule.MaxAgeSeconds)})
+		}
+
+		// 添加分隔行（最后一个不添加）
+		if i < len(cors.Rules)-1 {
+			table.Append([]string{"", "", ""})
+		}
+	}
+
+	// 渲染表格
+	table.Render()
+}
@@ -192,8 +192,13 @@ func deleteKeys(c *cos.Client, keysToDelete map[string]commonInfoType, destUrl S
 func DeleteCosObjects(c *cos.Client, keysToDelete map[string]commonInfoType, cosUrl StorageUrl, fo *FileOperations) error {
 
 	errCount := 0
+	var lockedKeys []string
 	objects := []cos.Object{}
 	for k, v := range keysToDelete {
+		// 中断后不再提交新的批量删除
+		if fo.Interrupted() {
+			return ErrInterrupted
+		}
 		if len(objects) >= MaxDeleteBatchCount {
 			if confirm(objects, fo, cosUrl) {
 				opt := &cos.ObjectDeleteMultiOptions{
@@ -206,6 +211,12 @@ func DeleteCosObjects(c *cos.Client, keysToDelete map[string]commonInfoType, cos
 				if err != nil {
 					return err
 				}
+				// 记录因对象锁定保留期而删除失败的对象
+				for _, delErr := range res.Errors {
+					if isObjectLockError(delErr.Code) {
+						lockedKeys = append(lockedKeys, delErr.Key)
+					}
+				}
 				// 删除失败的记录写入错误日志
 				if fo.Operation.FailOutput {
 					for _, delErr := range res.Errors {
@@ -240,6 +251,12 @@ func DeleteCosObjects(c *cos.Client, keysToDelete map[string]commonInfoType, cos
 		if err != nil {
 			return err
 		}
+		// 记录因对象锁定保留期而删除失败的对象
+		for _, delErr := range res.Errors {
+			if isObjectLockError(delErr.Code) {
+				lockedKeys = append(lockedKeys, delErr.Key)
+			}
+		}
 		// 删除失败的记录写入错误日志
 		if fo.Operation.FailOutput {
 			for _, delErr := range res.Errors {
@@ -257,6 +274,10 @@ func DeleteCosObjects(c *cos.Client, keysToDelete map[string]commonInfoType, cos
 			fmt.Printf("\rdelete object count:%d", fo.DeleteCount)
 		}
 	}
+
+	if len(lockedKeys) > 0 {
+		return objectLockedError(lockedKeys)
+	}
 	return nil
 }
 
@@ -273,8 +294,13 @@ func DeleteCosObjects(c *cos.Client, keysToDelete map[string]commonInfoType, cos
 func DeleteCosObjectVersions(c *cos.Client, keysToDelete []cos.Object, cosUrl StorageUrl, fo *FileOperations) error {
 
 	errCount := 0
+	var lockedKeys []string
 	objects := []cos.Object{}
 	for _, v := range keysToDelete {
+		// 中断后不再提交新的批量删除
+		if fo.Interrupted() {
+			return ErrInterrupted
+		}
 		if len(objects) >= MaxDeleteBatchCount {
 			if confirm(objects, fo, cosUrl) {
 				opt := &cos.ObjectDeleteMultiOptions{
@@ -287,6 +313,12 @@ func DeleteCosObjectVersions(c *cos.Client, keysToDelete []cos.Object, cosUrl St
 				if err != nil {
 					return err
 				}
+				// 记录因对象锁定保留期而删除失败的对象
+				for _, delErr := range res.Errors {
+					if isObjectLockError(delErr.Code) {
+						lockedKeys = append(lockedKeys, delErr.Key)
+					}
+				}
 				// 删除失败的记录写入错误日志
 				if fo.Operation.FailOutput {
 					for _, delErr := range res.Errors {
@@ -321,6 +353,12 @@ func DeleteCosObjectVersions(c *cos.Client, keysToDelete []cos.Object, cosUrl St
 		if err != nil {
 			return err
 		}
+		// 记录因对象锁定保留期而删除失败的对象
+		for _, delErr := range res.Errors {
+			if isObjectLockError(delErr.Code) {
+				lockedKeys = append(lockedKeys, delErr.Key)
+			}
+		}
 		// 删除失败的记录写入错误日志
 		if fo.Operation.FailOutput {
 			for _, delErr := range res.Errors {
@@ -338,6 +376,10 @@ func DeleteCosObjectVersions(c *cos.Client, keysToDelete []cos.Object, cosUrl St
 			fmt.Printf("\rdelete object versions count:%d", fo.DeleteCount)
 		}
 	}
+
+	if len(lockedKeys) > 0 {
+		return objectLockedError(lockedKeys)
+	}
 	return nil
 }
 
@@ -593,7 +635,12 @@ func RemoveObjects(args []string, fo *FileOperations) error {
 		// 打印一个空行
 		fmt.Println()
 
-		if bucketType == BucketTypeOfs {
+		if fo.Operation.FromInventory != "" {
+			if fo.Operation.AllVersions {
+				return fmt.Errorf("--from-inventory can not be used with --all-versions")
+			}
+			err = RemoveInventoryObjects(c, cosUrl, fo)
+		} else if bucketType == BucketTypeOfs {
 			prefix := cosUrl.(*CosUrl).Object
 
 			if len(fo.Operation.Filters) == 0 {
@@ -784,6 +831,40 @@ func RemoveCosObjects(marker string, c *cos.Client, cosUrl StorageUrl, fo *FileO
 	return nil
 }
 
+// RemoveInventoryObjects 删除清单报告中的cos对象
+func RemoveInventoryObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	var delErr error
+	keysToDelete := make(map[string]commonInfoType)
+	index := strings.LastIndex(cosUrl.(*CosUrl).Object, "/")
+
+	err := walkInventoryObjects(cosUrl, fo, func(object cos.Object) {
+		if delErr != nil {
+			return
+		}
+		objPrefix := ""
+		objKey := object.Key
+		if index > 0 {
+			objPrefix = object.Key[:index+1]
+			objKey = object.Key[index+1:]
+		}
+		keysToDelete[objKey] = commonInfoType{key: objKey, dir: objPrefix}
+
+		// 按批次删除
+		if len(keysToDelete) >= MaxDeleteBatchCount {
+			delErr = DeleteCosObjects(c, keysToDelete, cosUrl, fo)
+			keysToDelete = make(map[string]commonInfoType)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if delErr != nil {
+		return delErr
+	}
+
+	return DeleteCosObjects(c, keysToDelete, cosUrl, fo)
+}
+
 // RemoveCosObjectVersions 删除cos对象历史版本
 func RemoveCosObjectVersions(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
 	var err error
@@ -874,7 +955,10 @@ func RemoveObject(args []string, fo *FileOperations) error {
 		}
 
 		// 删除指定object或其指定版本
-		RemoveObjectOrVersion(c, cosUrl, fo)
+		err = RemoveObjectOrVersion(c, cosUrl, fo)
+		if err != nil {
+			return err
+		}
 
 	}
 	return nil
@@ -910,7 +994,7 @@ func RemoveObjectOrVersion(c *cos.Client, cosUrl StorageUrl, fo *FileOperations)
 		if choice == "" || choice == "y" || choice == "Y" || choice == "yes" || choice == "Yes" || choice == "YES" {
 			_, err = c.Object.Delete(context.Background(), cosUrl.(*CosUrl).Object, opt)
 			if err != nil {
-				return err
+				return checkObjectLockError(cosPath, err)
 			}
 			if fo.Operation.VersionId == "" {
 				logger.Infof("Delete object %s successfully!", cosPath)
@@ -927,7 +1011,7 @@ func RemoveObjectOrVersion(c *cos.Client, cosUrl StorageUrl, fo *FileOperations)
 	} else {
 		_, err = c.Object.Delete(context.Background(), cosUrl.(*CosUrl).Object, opt)
 		if err != nil {
-			return err
+			return checkObjectLockError(cosPath, err)
 		}
 		if fo.Operation.VersionId == "" {
 			logger.Infof("Delete object %s successfully!", cosPath)
@@ -1,7 +1,6 @@
 package util
 
 import (
-	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -64,6 +63,10 @@ func Download(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo *FileOper
 		fo.Monitor.setScanEnd()
 		freshProgress()
 
+		if fo.Operation.Atomic {
+			cleanStaleTempFilesOf(DownloadPathFixed(relativeKey, fileUrl.ToString()), fo)
+		}
+
 		// 下载文件
 		skip, err, isDir, size, _, msg := singleDownload(c, fo, objectInfoType{prefix, relativeKey, resp.ContentLength, resp.Header.Get("Last-Modified"), false}, cosUrl, fileUrl, fo.Operation.VersionId)
 		fo.Monitor.updateMonitor(skip, err, isDir, size)
@@ -72,11 +75,14 @@ func Download(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo *FileOper
 		}
 	} else {
 		// 多对象下载
+		if fo.Operation.Atomic {
+			cleanStaleTempFiles(fileUrl.ToString(), fo)
+		}
 		batchDownloadFiles(c, cosUrl, fileUrl, fo)
 	}
 
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
@@ -100,7 +106,12 @@ func batchDownloadFiles(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo
 		}
 	}()
 
-	if fo.BucketType == BucketTypeOfs {
+	if fo.Operation.FromInventory != "" {
+		// 扫描清单报告中对象大小及数量
+		go getInventoryObjectList(cosUrl, nil, nil, fo, true, false)
+		// 从清单报告获取对象列表
+		go getInventoryObjectList(cosUrl, chObjects, chListError, fo, false, true)
+	} else if fo.BucketType == BucketTypeOfs {
 		// 扫描ofs对象大小及数量
 		go getOfsObjectList(c, cosUrl, nil, nil, fo, true, false)
 		// 获取ofs对象列表
@@ -144,6 +155,10 @@ func batchDownloadFiles(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo
 
 func downloadFiles(c *cos.Client, cosUrl, fileUrl StorageUrl, fo *FileOperations, chObjects <-chan objectInfoType, chError chan<- error, chLog chan<- string) {
 	for object := range chObjects {
+		// 中断后不再处理新的文件
+		if fo.Interrupted() {
+			continue
+		}
 		var skip, isDir bool
 		var err error
 		var size, transferSize int64
@@ -175,6 +190,10 @@ func downloadFiles(c *cos.Client, cosUrl, fileUrl StorageUrl, fo *FileOperations
 			if err == nil {
 				break // Download succeeded, break the loop
 			} else {
+				// 中断后不再重试
+				if fo.Interrupted() {
+					break
+				}
 				if fo.Operation.ErrRetryInterval == 0 {
 					// If the retry interval is not specified, retry after a random interval of 1~10 seconds.
 					sleepTime = time.Duration(rand.Intn(10)+1) * time.Second
@@ -335,21 +354,84 @@ func singleDownload(c *cos.Client, fo *FileOperations, objectInfo objectInfoType
 	size = 0
 
 	var resp *cos.Response
-
+	var env *clientEnvelope
 	if fo.BucketType == BucketTypeOfs {
-		resp, err = c.Object.Download(context.Background(), object, localFilePath, opt)
+		VersionId = nil
+	}
+
+	// 指定主密钥或解压时，先查询对象元数据
+	var decompress bool
+	var decompressedSize int64
+	if len(fo.Operation.ClientEncryptKey) > 0 || fo.Operation.Decompress {
+		head, err := c.Object.Head(fo.Context(), object, nil, VersionId...)
+		if err != nil {
+			rErr = err
+			return
+		}
+		if len(fo.Operation.ClientEncryptKey) > 0 && isClientEncrypted(head.Header) {
+			// 客户端加密对象下载后解密
+			env, err = parseClientEnvelope(head.Header, fo.Operation.ClientEncryptKey)
+			if err != nil {
+				rErr = err
+				return
+			}
+		} else if fo.Operation.Decompress && isCompressed(head.Header) {
+			decompress = true
+		}
+	}
+
+	// 原子下载时先写入临时文件，校验通过后再重命名；仅sdk分块下载支持断点续传
+	downloadPath := localFilePath
+	resumable := fo.Operation.CheckPoint && env == nil && !decompress
+	if fo.Operation.Atomic {
+		downloadPath = atomicTempPath(localFilePath, resumable)
+	}
+
+	if env != nil {
+		// 解密下载不通过sdk监听进度，完成后按对象大小更新
+		size = objectInfo.size
+		resp, err = clientDecryptDownload(fo.Context(), c, object, downloadPath, env, &cos.ObjectGetOptions{
+			XCosTrafficLimit: opt.Opt.XCosTrafficLimit,
+		}, VersionId...)
+	} else if decompress {
+		size = objectInfo.size
+		resp, decompressedSize, err = decompressDownload(fo.Context(), c, object, downloadPath, identityGetOptions(opt.Opt.XCosTrafficLimit), VersionId...)
 	} else {
-		resp, err = c.Object.Download(context.Background(), object, localFilePath, opt, VersionId...)
+		resp, err = c.Object.Download(transferContext(fo), object, downloadPath, opt, VersionId...)
 	}
 
 	if err != nil {
 		if (!isPart) || (isPart && !fo.Operation.CheckPoint) {
 			transferSize = counter.TransferSize
 		}
+		if fo.Operation.Atomic {
+			removeAtomicTemp(downloadPath, resumable)
+		}
 		rErr = err
 		return
 	}
 
+	if fo.Operation.Atomic {
+		// 对象的crc64为密文或压缩数据的校验值，解密及解压时仅校验大小
+		expectSize := objectInfo.size
+		expectCrc := ""
+		if env != nil {
+			expectSize = env.plainSize
+		} else if decompress {
+			expectSize = decompressedSize
+		} else if !fo.Operation.DisableChecksum {
+			expectCrc = resp.Header.Get("x-cos-hash-crc64ecma")
+		}
+		err = commitAtomicDownload(downloadPath, localFilePath, expectSize, expectCrc, resp)
+		if err != nil {
+			os.Remove(downloadPath)
+			os.Remove(downloadPath + checkPointFileSuffix)
+			transferSize = counter.TransferSize
+			rErr = err
+			return
+		}
+	}
+
 	// 下载完成记录快照信息
 	if snapshotKey != "" && fo.Operation.SnapshotPath != "" && fo.Command == CommandSync {
 		lastModified := resp.Header.Get("Last-Modified")
@@ -383,10 +465,13 @@ func DownloadWithDelete(c *cos.Client, srcKeys, downloadKeys map[string]commonIn
 	go progressBar(fo)
 
 	// 多对象下载
+	if fo.Operation.Atomic {
+		cleanStaleTempFiles(fileUrl.ToString(), fo)
+	}
 	batchDownloadFilesWithDelete(c, srcKeys, downloadKeys, cosUrl, fileUrl, fo)
 
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
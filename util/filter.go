@@ -1,8 +1,11 @@
 package util
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func matchPatterns(filename string, filters []FilterOptionType) bool {
@@ -109,3 +112,52 @@ func cosObjectMatchPatterns(object string, filters []FilterOptionType) bool {
 
 	return matchPatterns(object, filters)
 }
+
+// ParseAge 解析对象年龄，支持 time.ParseDuration 的格式，另支持以 d 为单位的天数，如 30d
+func ParseAge(s string) (time.Duration, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, nil
+	}
+	if strings.HasSuffix(str, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(str, "d"), 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid age: %s", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	age, err := time.ParseDuration(str)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("invalid age: %s", s)
+	}
+	return age, nil
+}
+
+// cosObjectMatchPredicates 按对象大小及最后修改时间筛选
+func cosObjectMatchPredicates(size int64, lastModified string, op Operation) bool {
+	if op.MinSize > 0 && size < op.MinSize {
+		return false
+	}
+	if op.MaxSize > 0 && size > op.MaxSize {
+		return false
+	}
+	if op.OlderThan == 0 && op.NewerThan == 0 {
+		return true
+	}
+
+	modifiedTime, err := time.Parse(time.RFC3339, lastModified)
+	if err != nil {
+		modifiedTime, err = time.Parse(time.RFC1123, lastModified)
+		if err != nil {
+			return false
+		}
+	}
+	age := time.Since(modifiedTime)
+	if op.OlderThan > 0 && age < op.OlderThan {
+		return false
+	}
+	if op.NewerThan > 0 && age > op.NewerThan {
+		return false
+	}
+	return true
+}
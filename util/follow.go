@@ -0,0 +1,156 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+const (
+	// 轮询间隔，对象无变化时逐次翻倍，有新数据时恢复为最小间隔
+	followMinInterval = time.Second
+	followMaxInterval = 30 * time.Second
+)
+
+// followStat 追加对象的当前长度及crc64
+type followStat struct {
+	exists bool
+	size   int64
+	crc64  string
+}
+
+func (s *catSource) followStat() (followStat, error) {
+	resp, err := s.c.Object.Head(context.Background(), s.object, &cos.ObjectHeadOptions{
+		XCosSSECustomerAglo:   s.fo.Operation.SSECustomerAlgo,
+		XCosSSECustomerKey:    s.fo.Operation.SSECustomerKey,
+		XCosSSECustomerKeyMD5: s.fo.Operation.SSECustomerKeyMD5,
+	})
+	if err != nil {
+		if cos.IsNotFoundError(err) {
+			return followStat{}, nil
+		}
+		return followStat{}, err
+	}
+	stat := followStat{exists: true, size: resp.ContentLength, crc64: resp.Header.Get("x-cos-hash-crc64ecma")}
+	if pos := resp.Header.Get("x-cos-next-append-position"); pos != "" {
+		if size, err := strconv.ParseInt(pos, 10, 64); err == nil {
+			stat.size = size
+		}
+	}
+	return stat, nil
+}
+
+// crc64Writer 计算已输出内容的crc64，用于判断对象是否被替换
+type crc64Writer struct {
+	w   io.Writer
+	crc uint64
+}
+
+func (cw *crc64Writer) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.crc = crc64.Update(cw.crc, crc64.MakeTable(crc64.ECMA), p[:n])
+	return n, err
+}
+
+// matchCrc64 对象的crc64与已输出内容一致，对象未返回crc64时视为一致
+func (cw *crc64Writer) matchCrc64(crc string) bool {
+	if crc == "" {
+		return true
+	}
+	return crc == strconv.FormatUint(cw.crc, 10)
+}
+
+// FollowObject 输出对象内容后持续轮询，输出新追加的数据，直到被中断
+// 对象被截断、替换或删除后重新创建时从头输出
+func FollowObject(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	src := &catSource{c: c, object: cosUrl.(*CosUrl).Object, fo: fo}
+	stat, err := src.followStat()
+	if err != nil {
+		return err
+	}
+	if !stat.exists {
+		return fmt.Errorf("object %s not found", cosUrl.ToString())
+	}
+
+	// 指定tail时仅输出末尾内容，无法得到已输出内容的crc64，以对象的crc64为准
+	src.size = stat.size
+	offset := stat.size
+	out := &crc64Writer{w: os.Stdout}
+	switch {
+	case fo.Operation.Tail > 0 && fo.Operation.Lines:
+		err = src.tailLines(fo.Operation.Tail)
+	case fo.Operation.Tail > 0:
+		err = src.copyRange(stat.size-fo.Operation.Tail, stat.size)
+	default:
+		offset = 0
+	}
+	if err != nil {
+		return err
+	}
+	if offset > 0 && stat.crc64 != "" {
+		out.crc, _ = strconv.ParseUint(stat.crc64, 10, 64)
+	}
+
+	interval := followMinInterval
+	for {
+		if !stat.exists {
+			offset = 0
+			out.crc = 0
+		} else if stat.size < offset {
+			fmt.Fprintf(os.Stderr, "coscli: %s was truncated, following from the beginning\n", cosUrl.ToString())
+			offset = 0
+			out.crc = 0
+		} else if stat.size == offset && !out.matchCrc64(stat.crc64) {
+			fmt.Fprintf(os.Stderr, "coscli: %s was replaced, following from the beginning\n", cosUrl.ToString())
+			offset = 0
+			out.crc = 0
+		}
+
+		if stat.exists && stat.size > offset {
+			src.size = stat.size
+			r, err := src.open(offset, stat.size)
+			if err == nil {
+				var n int64
+				n, err = io.Copy(out, r)
+				r.Close()
+				offset += n
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "coscli: read %s error: %v\n", cosUrl.ToString(), err)
+			} else if !out.matchCrc64(stat.crc64) {
+				// 已输出的内容属于被替换前的对象，下一轮从头输出
+				fmt.Fprintf(os.Stderr, "coscli: %s was replaced, following from the beginning\n", cosUrl.ToString())
+				offset = 0
+				out.crc = 0
+			} else {
+				interval = followMinInterval
+			}
+		}
+
+		select {
+		case <-fo.Context().Done():
+			return nil
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > followMaxInterval {
+			interval = followMaxInterval
+		}
+
+		existed := stat.exists
+		next, err := src.followStat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "coscli: head %s error: %v\n", cosUrl.ToString(), err)
+			continue
+		}
+		if existed && !next.exists {
+			fmt.Fprintf(os.Stderr, "coscli: %s was deleted, waiting for it to be created again\n", cosUrl.ToString())
+		}
+		stat = next
+	}
+}
@@ -15,6 +15,8 @@ func GetBucketType(c *cos.Client, param *Param, config *Config, bucketName strin
 		if bucketType != "COS" && bucketType != "OFS" {
 			logger.Fatalln("bucket type can only be either COS or OFS ")
 		}
+	} else if cachedType, ok := sessionCache.lookupBucketType(bucketName); ok {
+		bucketType = cachedType
 	} else {
 		if config.Base.DisableAutoFetchBucketType == "true" {
 			bucket, _, err := FindBucket(config, bucketName)
@@ -35,6 +37,9 @@ func GetBucketType(c *cos.Client, param *Param, config *Config, bucketName strin
 				bucketType = BucketTypeOfs
 			}
 		}
+		if sessionCache != nil {
+			sessionCache.putBucketType(bucketName, bucketType)
+		}
 	}
 	//logger.Info("桶类型", bucketType)
 	return bucketType, nil
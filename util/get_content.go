@@ -1,6 +1,7 @@
 package util
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -8,9 +9,19 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// cosXmlConfigTypes SDK结构体只有xml标签的配置，JSON需按COS元素名转换为XML后解析
+var cosXmlConfigTypes = map[string]bool{
+	ContentTypeLifecycle:   true,
+	ContentTypeCors:        true,
+	ContentTypeReferer:     true,
+	ContentTypeWebsite:     true,
+	ContentTypeReplication: true,
+}
+
 // GetContent 获取配置信息（若是本地文件目录则读取文件内容）
 func GetContent(input string) ([]byte, error) {
 	// 处理文件路径
@@ -80,6 +91,8 @@ func ParseContent[T any](content []byte, target *T, contentType string) error {
 		}
 		// 无法识别格式
 		return fmt.Errorf("unrecognized configuration format, must be JSON")
+	} else if cosXmlConfigTypes[contentType] {
+		return parseCosXmlConfig(content, target)
 	} else {
 		// 尝试解析为JSON
 		if err := json.Unmarshal(content, &target); err == nil {
@@ -96,3 +109,85 @@ func ParseContent[T any](content []byte, target *T, contentType string) error {
 	}
 
 }
+
+// parseCosXmlConfig 解析XML配置，或键名与COS XML元素名一致的JSON配置
+// 如 {"Rule": [{"ID": "rule1", "Status": "Enabled"}]}，根元素可省略
+func parseCosXmlConfig[T any](content []byte, target *T) error {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return fmt.Errorf("content is empty")
+	}
+	if content[0] == '{' {
+		root, err := xmlRootName(target)
+		if err != nil {
+			return err
+		}
+		var v map[string]interface{}
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("unrecognized configuration format, invalid JSON: %v", err)
+		}
+		// 兼容带根元素的写法
+		if inner, ok := v[root].(map[string]interface{}); ok && len(v) == 1 {
+			v = inner
+		}
+		var buf bytes.Buffer
+		writeJsonAsXml(&buf, root, v)
+		if err := xml.Unmarshal(buf.Bytes(), target); err != nil {
+			return fmt.Errorf("unrecognized configuration format, JSON keys must match COS XML element names: %v", err)
+		}
+		logger.Info("Detected JSON format")
+		return nil
+	}
+	if err := xml.Unmarshal(content, target); err != nil {
+		return fmt.Errorf("unrecognized configuration format, must be JSON or XML: %v", err)
+	}
+	logger.Info("Detected XML format")
+	return nil
+}
+
+// xmlRootName 获取配置结构体的XML根元素名
+func xmlRootName(target interface{}) (string, error) {
+	data, err := xml.Marshal(target)
+	if err != nil {
+		return "", err
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve configuration root element: %v", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return se.Name.Local, nil
+		}
+	}
+}
+
+// writeJsonAsXml 将JSON值按键名写为XML元素，数组展开为同名的重复元素
+func writeJsonAsXml(buf *bytes.Buffer, name string, value interface{}) {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			writeJsonAsXml(buf, name, item)
+		}
+		return
+	}
+	buf.WriteString("<" + name + ">")
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeJsonAsXml(buf, k, v[k])
+		}
+	case nil:
+	default:
+		xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+	}
+	buf.WriteString("</" + name + ">")
+}
@@ -276,7 +276,8 @@ func getCosObjectList(c *cos.Client, cosUrl StorageUrl, chObjects chan<- objectI
 	}
 
 	isTruncated := true
-	for isTruncated {
+	// 中断后停止列出对象
+	for isTruncated && !fo.Interrupted() {
 		// 实例化请求参数
 		opt := &cos.BucketGetOptions{
 			Prefix:       prefix,
@@ -388,6 +389,78 @@ func getCosObjectListForLs(c *cos.Client, cosUrl StorageUrl, marker string, limi
 	return
 }
 
+// walkCosObjects 遍历前缀下符合筛选条件的对象（不含目录对象）
+func walkCosObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, handle func(object cos.Object)) error {
+	var err error
+	var objects []cos.Object
+	marker := ""
+	isTruncated := true
+
+	for isTruncated {
+		err, objects, _, isTruncated, marker = getCosObjectListForLs(c, cosUrl, marker, 0, true)
+		if err != nil {
+			return fmt.Errorf("list objects error : %v", err)
+		}
+
+		for _, object := range objects {
+			object.Key, _ = url.QueryUnescape(object.Key)
+			if strings.HasSuffix(object.Key, CosSeparator) {
+				continue
+			}
+			if !cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
+				continue
+			}
+			if !cosObjectMatchPredicates(object.Size, object.LastModified, fo.Operation) {
+				continue
+			}
+			handle(object)
+		}
+	}
+
+	return nil
+}
+
+// walkOfsObjects 遍历ofs桶前缀下符合筛选条件的对象（不含目录对象）
+func walkOfsObjects(c *cos.Client, prefix string, fo *FileOperations, handle func(object cos.Object)) error {
+	var err error
+	var objects []cos.Object
+	var commonPrefixes []string
+	marker := ""
+	isTruncated := true
+
+	for isTruncated {
+		err, objects, commonPrefixes, isTruncated, marker = getOfsObjectListForLs(c, prefix, marker, 0, true)
+		if err != nil {
+			return fmt.Errorf("list objects error : %v", err)
+		}
+
+		for _, object := range objects {
+			object.Key, _ = url.QueryUnescape(object.Key)
+			if strings.HasSuffix(object.Key, CosSeparator) {
+				continue
+			}
+			if !cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
+				continue
+			}
+			if !cosObjectMatchPredicates(object.Size, object.LastModified, fo.Operation) {
+				continue
+			}
+			handle(object)
+		}
+
+		for _, commonPrefix := range commonPrefixes {
+			commonPrefix, _ = url.QueryUnescape(commonPrefix)
+			// 递归目录
+			err = walkOfsObjects(c, commonPrefix, fo, handle)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func getCosObjectVersionListForLs(c *cos.Client, cosUrl StorageUrl, versionIdMarker, keyMarker string, limit int, recursive bool) (err error, versions []cos.ListVersionsResultVersion, deleteMarkers []cos.ListVersionsResultDeleteMarker, commonPrefixes []string, isTruncated bool, nextVersionIdMarker, nextKeyMarker string) {
 
 	prefix := cosUrl.(*CosUrl).Object
@@ -2,6 +2,7 @@ package util
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -14,6 +15,9 @@ import (
 
 var once sync.Once
 
+// errWalkInterrupted 中断后结束本地文件遍历
+var errWalkInterrupted = errors.New("walk interrupted")
+
 func fileStatistic(localPath string, fo *FileOperations) {
 	f, err := os.Stat(localPath)
 	if err != nil {
@@ -175,6 +179,10 @@ func getFileList(dpath string, chFiles chan<- fileInfoType, fo *FileOperations)
 	name := dpath
 	symlinkDiretorys := []string{dpath}
 	walkFunc := func(fpath string, f os.FileInfo, err error) error {
+		// 中断后停止遍历
+		if fo.Interrupted() {
+			return errWalkInterrupted
+		}
 		if f == nil {
 			return err
 		}
@@ -232,6 +240,9 @@ func getFileList(dpath string, chFiles chan<- fileInfoType, fo *FileOperations)
 		symlinkDiretorys = []string{}
 		for _, v := range symlinks {
 			err = filepath.Walk(v, walkFunc)
+			if err == errWalkInterrupted {
+				return nil
+			}
 			if err != nil {
 				return err
 			}
@@ -254,6 +265,9 @@ func getCurrentDirFileList(dpath string, chFiles chan<- fileInfoType, fo *FileOp
 	}
 
 	for _, fileInfo := range fileList {
+		if fo.Interrupted() {
+			break
+		}
 		if !fileInfo.IsDir() {
 			realInfo, errF := os.Stat(dpath + fileInfo.Name())
 			if errF == nil && realInfo.IsDir() {
@@ -0,0 +1,307 @@
+package util
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// InventoryManifest 清单报告manifest.json
+type InventoryManifest struct {
+	SourceBucket      string                  `json:"sourceBucket"`
+	DestinationBucket string                  `json:"destinationBucket"`
+	Version           string                  `json:"version"`
+	CreationTimestamp string                  `json:"creationTimestamp"`
+	FileFormat        string                  `json:"fileFormat"`
+	FileSchema        string                  `json:"fileSchema"`
+	Files             []InventoryManifestFile `json:"files"`
+}
+
+// InventoryManifestFile 清单报告数据文件
+type InventoryManifestFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5checksum string `json:"MD5checksum"`
+}
+
+// InventorySource 以清单报告作为对象列表来源
+type InventorySource struct {
+	Client   *cos.Client
+	Manifest *InventoryManifest
+	Format   string
+	Columns  map[string]int
+}
+
+// ORC及Parquet清单文件自带列名，按以下字段组织每一行
+var inventoryColumnarFields = []string{
+	"Bucket", "Key", "VersionId", "IsLatest", "IsDeleteMarker", "Size", "LastModifiedDate", "ETag",
+	"StorageClass", "IsMultipartUploaded", "ReplicationStatus", "EncryptionStatus", "Crc64",
+}
+
+// NewInventorySource 读取清单报告manifest
+func NewInventorySource(config *Config, param *Param, manifestPath string) (*InventorySource, error) {
+	manifestUrl, err := FormatUrl(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("inventory url format error:%v", err)
+	}
+	if !manifestUrl.IsCosUrl() {
+		return nil, fmt.Errorf("inventory manifest needs to contain %s", SchemePrefix)
+	}
+
+	c, err := NewClient(config, param, manifestUrl.(*CosUrl).Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Object.Get(context.Background(), manifestUrl.(*CosUrl).Object, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get inventory manifest error : %v", err)
+	}
+	defer resp.Body.Close()
+
+	manifest := &InventoryManifest{}
+	if err = json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("parse inventory manifest error : %v", err)
+	}
+
+	format := strings.ToUpper(manifest.FileFormat)
+	columns := make(map[string]int)
+	switch format {
+	case InventoryFormatCSV:
+		for i, column := range strings.Split(manifest.FileSchema, ",") {
+			columns[strings.TrimSpace(column)] = i
+		}
+		if _, ok := columns["Key"]; !ok {
+			return nil, fmt.Errorf("inventory file schema does not contain Key")
+		}
+	case InventoryFormatORC, InventoryFormatParquet:
+		// 列式文件按文件内的列名读取
+		for i, column := range inventoryColumnarFields {
+			columns[column] = i
+		}
+	default:
+		return nil, fmt.Errorf("unknown inventory file format %s", manifest.FileFormat)
+	}
+
+	logger.Infof("Load inventory manifest %s, source bucket: %s, files: %d", manifestPath, manifest.SourceBucket, len(manifest.Files))
+	return &InventorySource{Client: c, Manifest: manifest, Format: format, Columns: columns}, nil
+}
+
+// CheckSourceBucket 检查清单报告的源存储桶是否与目标存储桶一致
+func (s *InventorySource) CheckSourceBucket(config *Config, cosUrl StorageUrl) error {
+	bucket, _, _ := FindBucket(config, cosUrl.(*CosUrl).Bucket)
+	if s.Manifest.SourceBucket != "" && s.Manifest.SourceBucket != bucket.Name {
+		return fmt.Errorf("inventory source bucket %s does not match %s", s.Manifest.SourceBucket, bucket.Name)
+	}
+	return nil
+}
+
+// Walk 遍历清单报告中cosUrl前缀下的对象
+func (s *InventorySource) Walk(cosUrl StorageUrl, handle func(object cos.Object) error) error {
+	prefix := cosUrl.(*CosUrl).Object
+	return s.WalkRecords(func(record []string) error {
+		object := s.parseRecord(record)
+		if object.Key == "" || !strings.HasPrefix(object.Key, prefix) {
+			return nil
+		}
+		return handle(object)
+	})
+}
+
+// WalkRecords 遍历清单报告中的每一行
+func (s *InventorySource) WalkRecords(handle func(record []string) error) error {
+	for _, file := range s.Manifest.Files {
+		var err error
+		switch s.Format {
+		case InventoryFormatORC:
+			err = s.walkColumnarFile(file, walkOrcInventory, handle)
+		case InventoryFormatParquet:
+			err = s.walkColumnarFile(file, walkParquetInventory, handle)
+		default:
+			err = s.walkFile(file.Key, handle)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Field 获取清单报告行中指定列的值
+func (s *InventorySource) Field(record []string, name string) string {
+	i, ok := s.Columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+func (s *InventorySource) walkFile(key string, handle func(record []string) error) error {
+	resp, err := s.Client.Object.Get(context.Background(), key, nil)
+	if err != nil {
+		return fmt.Errorf("get inventory file %s error : %v", key, err)
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if strings.HasSuffix(key, ".gz") {
+		gzReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read inventory file %s error : %v", key, err)
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read inventory file %s error : %v", key, err)
+		}
+
+		if err = handle(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkColumnarFile 通过范围下载读取ORC或Parquet清单文件，按列名组织为行
+func (s *InventorySource) walkColumnarFile(file InventoryManifestFile, walk func(r io.ReaderAt, size int64, handle func(columns map[string][]string, rows int) error) error, handle func(record []string) error) error {
+	size := file.Size
+	if size <= 0 {
+		resp, err := s.Client.Object.Head(context.Background(), file.Key, nil)
+		if err != nil {
+			return fmt.Errorf("head inventory file %s error : %v", file.Key, err)
+		}
+		size = resp.ContentLength
+	}
+	reader := &cosReaderAt{ctx: context.Background(), c: s.Client, key: file.Key, size: size, opt: &cos.ObjectGetOptions{}}
+
+	err := walk(reader, size, func(columns map[string][]string, rows int) error {
+		indexes := make(map[string]int, len(columns))
+		for name := range columns {
+			if i, ok := s.columnIndex(name); ok {
+				indexes[name] = i
+			}
+		}
+		for row := 0; row < rows; row++ {
+			record := make([]string, len(inventoryColumnarFields))
+			for name, i := range indexes {
+				record[i] = columns[name][row]
+			}
+			if err := handle(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read inventory file %s error : %v", file.Key, err)
+	}
+	return nil
+}
+
+// columnIndex 匹配列式文件的列名，兼容key、last_modified_date等写法
+func (s *InventorySource) columnIndex(name string) (int, bool) {
+	normalized := strings.ReplaceAll(name, "_", "")
+	for column, i := range s.Columns {
+		if strings.EqualFold(column, normalized) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseRecord 将清单报告中的一行转换为对象信息
+func (s *InventorySource) parseRecord(record []string) cos.Object {
+	var object cos.Object
+	object.Key = s.Field(record, "Key")
+	// CSV中的对象键经过URL编码，列式文件中为原始键
+	if s.Format != InventoryFormatORC && s.Format != InventoryFormatParquet {
+		if key, err := url.QueryUnescape(object.Key); err == nil {
+			object.Key = key
+		}
+	}
+	object.Size, _ = strconv.ParseInt(s.Field(record, "Size"), 10, 64)
+	object.ETag = s.Field(record, "ETag")
+	object.StorageClass = strings.ToUpper(s.Field(record, "StorageClass"))
+	object.LastModified = s.Field(record, "LastModifiedDate")
+	// 兼容时间戳格式的最后修改时间
+	if ts, err := strconv.ParseInt(object.LastModified, 10, 64); err == nil {
+		object.LastModified = time.Unix(ts, 0).UTC().Format(time.RFC3339)
+	}
+	return object
+}
+
+// walkInventoryObjects 遍历清单报告中符合筛选条件的对象
+func walkInventoryObjects(cosUrl StorageUrl, fo *FileOperations, handle func(object cos.Object)) error {
+	source, err := NewInventorySource(fo.Config, fo.Param, fo.Operation.FromInventory)
+	if err != nil {
+		return err
+	}
+	if err = source.CheckSourceBucket(fo.Config, cosUrl); err != nil {
+		return err
+	}
+
+	return source.Walk(cosUrl, func(object cos.Object) error {
+		if cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
+			handle(object)
+		}
+		return nil
+	})
+}
+
+func getInventoryObjectList(cosUrl StorageUrl, chObjects chan<- objectInfoType, chError chan<- error, fo *FileOperations, scanSizeNum bool, withFinishSignal bool) {
+	if chObjects != nil {
+		defer close(chObjects)
+	}
+
+	prefix := cosUrl.(*CosUrl).Object
+	err := walkInventoryObjects(cosUrl, fo, func(object cos.Object) {
+		// 仅处理当前目录下的文件
+		if fo.Operation.OnlyCurrentDir && strings.Contains(object.Key[len(prefix):], CosSeparator) {
+			return
+		}
+		if scanSizeNum {
+			fo.Monitor.updateScanSizeNum(object.Size, 1)
+		} else {
+			objPrefix := ""
+			objKey := object.Key
+			index := strings.LastIndex(prefix, "/")
+			if index > 0 {
+				objPrefix = object.Key[:index+1]
+				objKey = object.Key[index+1:]
+			}
+			chObjects <- objectInfoType{prefix: objPrefix, relativeKey: objKey, size: object.Size, lastModified: object.LastModified}
+		}
+	})
+
+	if err != nil && scanSizeNum {
+		fo.Monitor.setScanError(err)
+	}
+
+	if scanSizeNum {
+		fo.Monitor.setScanEnd()
+		freshProgress()
+	}
+
+	if withFinishSignal {
+		chError <- err
+	}
+}
@@ -38,7 +38,8 @@ func getOfsObjectList(c *cos.Client, cosUrl StorageUrl, chObjects chan<- objectI
 
 func getOfsObjectListRecursion(c *cos.Client, cosUrl StorageUrl, chObjects chan<- objectInfoType, chError chan<- error, fo *FileOperations, scanSizeNum bool, prefix string, marker string, limit int, delimiter string) error {
 	isTruncated := true
-	for isTruncated {
+	// 中断后停止列出对象
+	for isTruncated && !fo.Interrupted() {
 		// 实例化请求参数
 		opt := &cos.BucketGetOptions{
 			Prefix:       prefix,
@@ -90,3 +90,62 @@ func GetHead(c *cos.Client, cosPath string, id ...string) (*cos.Response, error)
 	}
 	return c.Object.Head(context.Background(), cosPath, headOpt, id...)
 }
+
+// crc64Combine 由两段数据的crc64计算拼接后的crc64，len2为第二段数据长度，算法同 zlib crc32_combine
+func crc64Combine(crc1, crc2 uint64, len2 int64) uint64 {
+	if len2 <= 0 {
+		return crc1
+	}
+
+	var even, odd [64]uint64
+	// 单个0比特对应的算子
+	odd[0] = crc64.ECMA
+	row := uint64(1)
+	for n := 1; n < 64; n++ {
+		odd[n] = row
+		row <<= 1
+	}
+	// 2个0比特
+	gf2MatrixSquare(even[:], odd[:])
+	// 4个0比特
+	gf2MatrixSquare(odd[:], even[:])
+
+	// 按len2的二进制位依次追加0字节
+	for {
+		gf2MatrixSquare(even[:], odd[:])
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(even[:], crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+
+		gf2MatrixSquare(odd[:], even[:])
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(odd[:], crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+	}
+	return crc1 ^ crc2
+}
+
+func gf2MatrixTimes(mat []uint64, vec uint64) uint64 {
+	var sum uint64
+	for i := 0; vec != 0; i++ {
+		if vec&1 != 0 {
+			sum ^= mat[i]
+		}
+		vec >>= 1
+	}
+	return sum
+}
+
+func gf2MatrixSquare(square, mat []uint64) {
+	for n := range square {
+		square[n] = gf2MatrixTimes(mat, mat[n])
+	}
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ErrInterrupted 收到中断信号后返回的错误
+var ErrInterrupted = errors.New("interrupted by signal, the remaining files were not processed")
+
+// NewInterruptContext 监听SIGINT/SIGTERM，第一次收到信号时取消context，第二次收到信号时强制退出
+func NewInterruptContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	chSignal := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	signal.Notify(chSignal, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-chSignal:
+		case <-done:
+			return
+		}
+		fmt.Fprintf(os.Stderr, "\nInterrupted, waiting for in-flight transfers to finish or checkpoint, press Ctrl-C again to force quit\n")
+		cancel()
+
+		select {
+		case <-chSignal:
+			fmt.Fprintf(os.Stderr, "\nForce quit\n")
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	// stop 仅停止监听信号，已取消的context保持取消状态
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			signal.Stop(chSignal)
+			close(done)
+		})
+	}
+	return ctx, stop
+}
+
+// Context 获取操作的context，未设置时返回context.Background()
+func (fo *FileOperations) Context() context.Context {
+	if fo.Ctx == nil {
+		return context.Background()
+	}
+	return fo.Ctx
+}
+
+// Interrupted 是否已收到中断信号
+func (fo *FileOperations) Interrupted() bool {
+	return fo.Context().Err() != nil
+}
+
+// transferContext 单个文件传输使用的context
+// 仅开启断点续传时随中断取消，已完成的分块可在下次续传；否则等待当前文件传输完成，避免残留碎片
+func transferContext(fo *FileOperations) context.Context {
+	if fo.Operation.CheckPoint {
+		return fo.Context()
+	}
+	return context.Background()
+}
+
+// finishExitStat 结束时进度条的退出状态
+func finishExitStat(fo *FileOperations) int {
+	if fo.Interrupted() {
+		return interruptExit
+	}
+	return normalExit
+}
@@ -0,0 +1,191 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// 对象年龄分段
+var inventoryAgeBuckets = []struct {
+	Name string
+	Max  time.Duration
+}{
+	{"0-7d", 7 * 24 * time.Hour},
+	{"7-30d", 30 * 24 * time.Hour},
+	{"30-90d", 90 * 24 * time.Hour},
+	{"90-180d", 180 * 24 * time.Hour},
+	{"180-365d", 365 * 24 * time.Hour},
+	{">365d", 0},
+}
+
+const inventoryUnknown = "UNKNOWN"
+
+// InventoryAnalysis 清单报告统计结果
+type InventoryAnalysis struct {
+	SourceBucket      string     `json:"sourceBucket"`
+	CreationTimestamp string     `json:"creationTimestamp"`
+	Depth             int        `json:"depth"`
+	TotalFiles        int        `json:"totalFiles"`
+	TotalSize         int64      `json:"totalSize"`
+	Prefix            []*CosInfo `json:"prefix"`
+	StorageClass      []*CosInfo `json:"storageClass"`
+	Encryption        []*CosInfo `json:"encryption"`
+	Age               []*CosInfo `json:"age"`
+	Replication       []*CosInfo `json:"replication"`
+}
+
+// inventoryHistogram 按维度统计对象数及大小
+type inventoryHistogram map[string]*CosInfo
+
+func (h inventoryHistogram) add(name string, size int64) {
+	info, ok := h[name]
+	if !ok {
+		info = &CosInfo{Name: name}
+		h[name] = info
+	}
+	info.Size += size
+	info.TotalFiles++
+}
+
+// sorted 按大小降序输出
+func (h inventoryHistogram) sorted() []*CosInfo {
+	infos := make([]*CosInfo, 0, len(h))
+	for _, info := range h {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Size == infos[j].Size {
+			return infos[i].Name < infos[j].Name
+		}
+		return infos[i].Size > infos[j].Size
+	})
+	return infos
+}
+
+// AnalyzeInventory 统计清单报告中对象的分布
+func AnalyzeInventory(source *InventorySource, depth int, output string) error {
+	prefixes := inventoryHistogram{}
+	storageClasses := inventoryHistogram{}
+	encryptions := inventoryHistogram{}
+	ages := inventoryHistogram{}
+	replications := inventoryHistogram{}
+	analysis := &InventoryAnalysis{
+		SourceBucket:      source.Manifest.SourceBucket,
+		CreationTimestamp: source.Manifest.CreationTimestamp,
+		Depth:             depth,
+	}
+
+	now := time.Now()
+	err := source.WalkRecords(func(record []string) error {
+		object := source.parseRecord(record)
+		if object.Key == "" {
+			return nil
+		}
+
+		analysis.TotalFiles++
+		analysis.TotalSize += object.Size
+
+		prefixes.add(inventoryPrefix(object.Key, depth), object.Size)
+		storageClasses.add(inventoryValue(object.StorageClass), object.Size)
+		encryptions.add(inventoryValue(source.Field(record, "EncryptionStatus")), object.Size)
+		ages.add(inventoryAge(object.LastModified, now), object.Size)
+		replication := source.Field(record, "ReplicationStatus")
+		if replication == "" {
+			replication = ReplicationStatusNone
+		}
+		replications.add(strings.ToUpper(replication), object.Size)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	analysis.Prefix = prefixes.sorted()
+	analysis.StorageClass = storageClasses.sorted()
+	analysis.Encryption = encryptions.sorted()
+	analysis.Age = ages.sorted()
+	analysis.Replication = replications.sorted()
+
+	if output == OutputFormatJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(analysis)
+	}
+
+	renderInventoryAnalysisTable(analysis)
+	return nil
+}
+
+// inventoryPrefix 截取指定深度的前缀
+func inventoryPrefix(key string, depth int) string {
+	parts := strings.Split(key, CosSeparator)
+	// 最后一段为文件名
+	parts = parts[:len(parts)-1]
+	if len(parts) == 0 {
+		return CosSeparator
+	}
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, CosSeparator) + CosSeparator
+}
+
+func inventoryValue(value string) string {
+	if value == "" {
+		return inventoryUnknown
+	}
+	if v, err := url.QueryUnescape(value); err == nil {
+		value = v
+	}
+	return strings.ToUpper(value)
+}
+
+// inventoryAge 计算对象年龄分段
+func inventoryAge(lastModified string, now time.Time) string {
+	modTime, err := time.Parse(time.RFC3339, lastModified)
+	if err != nil {
+		return inventoryUnknown
+	}
+	age := now.Sub(modTime)
+	for _, bucket := range inventoryAgeBuckets {
+		if bucket.Max == 0 || age < bucket.Max {
+			return bucket.Name
+		}
+	}
+	return inventoryUnknown
+}
+
+func renderInventoryAnalysisTable(analysis *InventoryAnalysis) {
+	sections := []struct {
+		Name  string
+		Infos []*CosInfo
+	}{
+		{"Prefix (depth " + strconv.Itoa(analysis.Depth) + ")", analysis.Prefix},
+		{"Storage Class", analysis.StorageClass},
+		{"Encryption", analysis.Encryption},
+		{"Age", analysis.Age},
+		{"Replication Status", analysis.Replication},
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Dimension", "Value", "Objects Count", "Total Size"})
+	for _, section := range sections {
+		for _, info := range section.Infos {
+			table.Append([]string{section.Name, info.Name, fmt.Sprintf("%d", info.TotalFiles), FormatSize(info.Size)})
+		}
+	}
+	table.SetFooter([]string{"", "Total", fmt.Sprintf("%d", analysis.TotalFiles), FormatSize(analysis.TotalSize)})
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCaption(true, fmt.Sprintf("Inventory analysis of %s", analysis.SourceBucket))
+	table.Render()
+}
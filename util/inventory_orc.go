@@ -0,0 +1,949 @@
+package util
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ORC列类型
+const (
+	orcBoolean   = 0
+	orcByte      = 1
+	orcShort     = 2
+	orcInt       = 3
+	orcLong      = 4
+	orcFloat     = 5
+	orcDouble    = 6
+	orcString    = 7
+	orcTimestamp = 9
+	orcStruct    = 12
+	orcDate      = 15
+	orcVarchar   = 16
+	orcChar      = 17
+
+	orcTimestampInstant = 18
+)
+
+// ORC数据流类型
+const (
+	orcStreamPresent        = 0
+	orcStreamData           = 1
+	orcStreamLength         = 2
+	orcStreamDictionaryData = 3
+	orcStreamSecondary      = 5
+)
+
+// ORC列编码
+const (
+	orcEncodingDirect       = 0
+	orcEncodingDictionary   = 1
+	orcEncodingDirectV2     = 2
+	orcEncodingDictionaryV2 = 3
+)
+
+// ORC压缩方式
+const (
+	orcCompressionNone   = 0
+	orcCompressionZlib   = 1
+	orcCompressionSnappy = 2
+	orcCompressionZstd   = 5
+)
+
+const orcMagic = "ORC"
+
+// ORC时间戳以2015-01-01为基准，TIMESTAMP_INSTANT使用UTC
+var orcTimestampBase = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+// orcColumn 清单需要读取的顶层列
+type orcColumn struct {
+	name string
+	id   uint64
+	kind uint64
+}
+
+// orcStream 条带中的数据流
+type orcStream struct {
+	kind   uint64
+	column uint64
+	offset int64
+	length int64
+}
+
+// walkOrcInventory 按条带读取ORC清单文件，仅支持顶层结构体下的基本类型列
+func walkOrcInventory(r io.ReaderAt, size int64, handle func(columns map[string][]string, rows int) error) error {
+	if size < int64(len(orcMagic))+1 {
+		return fmt.Errorf("invalid orc file, size %d", size)
+	}
+	tailSize := int64(16 * 1024)
+	if tailSize > size {
+		tailSize = size
+	}
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil {
+		return err
+	}
+	psLen := int64(tail[len(tail)-1])
+	if psLen+1 > tailSize {
+		return fmt.Errorf("invalid orc postscript length %d", psLen)
+	}
+	postscript, err := parseProtobuf(tail[tailSize-1-psLen : tailSize-1])
+	if err != nil {
+		return fmt.Errorf("parse orc postscript error : %v", err)
+	}
+	if string(postscript.bytes(8000)) != orcMagic {
+		return fmt.Errorf("invalid orc file, magic number mismatch")
+	}
+	compression := postscript.uint(2)
+
+	footerLen := int64(postscript.uint(1))
+	footerOffset := size - 1 - psLen - footerLen
+	if footerLen <= 0 || footerOffset < 0 {
+		return fmt.Errorf("invalid orc footer length %d", footerLen)
+	}
+	footerData := make([]byte, footerLen)
+	if _, err = r.ReadAt(footerData, footerOffset); err != nil {
+		return err
+	}
+	if footerData, err = orcDecompress(compression, footerData); err != nil {
+		return fmt.Errorf("decompress orc footer error : %v", err)
+	}
+	footer, err := parseProtobuf(footerData)
+	if err != nil {
+		return fmt.Errorf("parse orc footer error : %v", err)
+	}
+
+	types, err := footer.messages(4)
+	if err != nil {
+		return fmt.Errorf("parse orc types error : %v", err)
+	}
+	columns, err := orcColumns(types)
+	if err != nil {
+		return err
+	}
+
+	stripes, err := footer.messages(3)
+	if err != nil {
+		return fmt.Errorf("parse orc stripes error : %v", err)
+	}
+	for _, stripe := range stripes {
+		values, rows, err := readOrcStripe(r, stripe, compression, columns)
+		if err != nil {
+			return err
+		}
+		if err = handle(values, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orcColumns 解析类型树，返回根结构体下的基本类型列
+func orcColumns(types []protobufMessage) ([]*orcColumn, error) {
+	if len(types) == 0 || types[0].uint(1) != orcStruct {
+		return nil, fmt.Errorf("orc file root type is not struct")
+	}
+	root := types[0]
+	subtypes := root.uints(2)
+	names := root.strings(3)
+	if len(subtypes) != len(names) {
+		return nil, fmt.Errorf("invalid orc struct type")
+	}
+	var columns []*orcColumn
+	for i, id := range subtypes {
+		if id >= uint64(len(types)) {
+			return nil, fmt.Errorf("invalid orc column id %d", id)
+		}
+		kind := types[id].uint(1)
+		switch kind {
+		case orcBoolean, orcByte, orcShort, orcInt, orcLong, orcFloat, orcDouble,
+			orcString, orcTimestamp, orcDate, orcVarchar, orcChar, orcTimestampInstant:
+			columns = append(columns, &orcColumn{name: names[i], id: id, kind: kind})
+		}
+	}
+	return columns, nil
+}
+
+// readOrcStripe 读取一个条带中需要的列
+func readOrcStripe(r io.ReaderAt, stripe protobufMessage, compression uint64, columns []*orcColumn) (map[string][]string, int, error) {
+	offset := int64(stripe.uint(1))
+	indexLen := int64(stripe.uint(2))
+	dataLen := int64(stripe.uint(3))
+	footerLen := int64(stripe.uint(4))
+	rows := int(stripe.uint(5))
+
+	data := make([]byte, indexLen+dataLen+footerLen)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return nil, 0, err
+	}
+	footerData, err := orcDecompress(compression, data[indexLen+dataLen:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("decompress orc stripe footer error : %v", err)
+	}
+	footer, err := parseProtobuf(footerData)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse orc stripe footer error : %v", err)
+	}
+
+	// 数据流按顺序紧邻存放
+	streams := make(map[uint64]map[uint64]orcStream)
+	streamList, err := footer.messages(1)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse orc streams error : %v", err)
+	}
+	var pos int64
+	for _, s := range streamList {
+		stream := orcStream{kind: s.uint(1), column: s.uint(2), offset: pos, length: int64(s.uint(3))}
+		pos += stream.length
+		if streams[stream.column] == nil {
+			streams[stream.column] = make(map[uint64]orcStream)
+		}
+		streams[stream.column][stream.kind] = stream
+	}
+	if pos > int64(len(data)) {
+		return nil, 0, fmt.Errorf("invalid orc stripe streams")
+	}
+	encodings, err := footer.messages(2)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse orc column encodings error : %v", err)
+	}
+	// TIMESTAMP以写入时区的2015-01-01为基准
+	timestampBase := orcTimestampBase
+	if tz := string(footer.bytes(3)); tz != "" {
+		if location, err := time.LoadLocation(tz); err == nil {
+			timestampBase = time.Date(2015, 1, 1, 0, 0, 0, 0, location).Unix()
+		}
+	}
+
+	values := make(map[string][]string, len(columns))
+	for _, column := range columns {
+		if column.id >= uint64(len(encodings)) {
+			return nil, 0, fmt.Errorf("orc column %s has no encoding", column.name)
+		}
+		read := func(kind uint64) ([]byte, error) {
+			stream, ok := streams[column.id][kind]
+			if !ok {
+				return nil, nil
+			}
+			return orcDecompress(compression, data[stream.offset:stream.offset+stream.length])
+		}
+		base := orcTimestampBase
+		if column.kind == orcTimestamp {
+			base = timestampBase
+		}
+		values[column.name], err = readOrcColumn(column, encodings[column.id], read, rows, base)
+		if err != nil {
+			return nil, 0, fmt.Errorf("read orc column %s error : %v", column.name, err)
+		}
+	}
+	return values, rows, nil
+}
+
+// readOrcColumn 解码一列数据，空值以空字符串表示
+func readOrcColumn(column *orcColumn, encoding protobufMessage, read func(kind uint64) ([]byte, error), rows int, timestampBase int64) ([]string, error) {
+	present, err := read(orcStreamPresent)
+	if err != nil {
+		return nil, err
+	}
+	count := rows
+	var defined []bool
+	if present != nil {
+		if defined, err = decodeOrcBooleans(present, rows); err != nil {
+			return nil, err
+		}
+		count = 0
+		for _, d := range defined {
+			if d {
+				count++
+			}
+		}
+	}
+
+	dataStream, err := read(orcStreamData)
+	if err != nil {
+		return nil, err
+	}
+	v2 := encoding.uint(1) == orcEncodingDirectV2 || encoding.uint(1) == orcEncodingDictionaryV2
+
+	var decoded []string
+	switch column.kind {
+	case orcBoolean:
+		var bools []bool
+		if bools, err = decodeOrcBooleans(dataStream, count); err == nil {
+			decoded = make([]string, count)
+			for i, b := range bools {
+				decoded[i] = strconv.FormatBool(b)
+			}
+		}
+	case orcByte:
+		var bytes []byte
+		if bytes, err = decodeOrcByteRle(dataStream, count); err == nil {
+			decoded = make([]string, count)
+			for i, b := range bytes {
+				decoded[i] = strconv.Itoa(int(int8(b)))
+			}
+		}
+	case orcShort, orcInt, orcLong, orcDate:
+		var ints []int64
+		if ints, err = decodeOrcIntRle(dataStream, count, true, v2); err == nil {
+			decoded = make([]string, count)
+			for i, v := range ints {
+				if column.kind == orcDate {
+					decoded[i] = time.Unix(v*86400, 0).UTC().Format(time.RFC3339)
+				} else {
+					decoded[i] = strconv.FormatInt(v, 10)
+				}
+			}
+		}
+	case orcFloat, orcDouble:
+		width := 8
+		if column.kind == orcFloat {
+			width = 4
+		}
+		if len(dataStream) < count*width {
+			return nil, fmt.Errorf("float values truncated")
+		}
+		decoded = make([]string, count)
+		for i := 0; i < count; i++ {
+			if width == 4 {
+				f := math.Float32frombits(binary.LittleEndian.Uint32(dataStream[i*4:]))
+				decoded[i] = strconv.FormatFloat(float64(f), 'f', -1, 32)
+			} else {
+				f := math.Float64frombits(binary.LittleEndian.Uint64(dataStream[i*8:]))
+				decoded[i] = strconv.FormatFloat(f, 'f', -1, 64)
+			}
+		}
+	case orcTimestamp, orcTimestampInstant:
+		decoded, err = readOrcTimestamps(dataStream, read, count, v2, timestampBase)
+	case orcString, orcVarchar, orcChar:
+		decoded, err = readOrcStrings(dataStream, encoding, read, count, v2)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < count {
+		return nil, fmt.Errorf("expected %d values, got %d", count, len(decoded))
+	}
+
+	if defined == nil {
+		return decoded[:rows], nil
+	}
+	values := make([]string, rows)
+	next := 0
+	for i, d := range defined {
+		if d {
+			values[i] = decoded[next]
+			next++
+		}
+	}
+	return values, nil
+}
+
+// readOrcStrings 解码直接编码或字典编码的字符串列
+func readOrcStrings(dataStream []byte, encoding protobufMessage, read func(kind uint64) ([]byte, error), count int, v2 bool) ([]string, error) {
+	lengthStream, err := read(orcStreamLength)
+	if err != nil {
+		return nil, err
+	}
+	switch encoding.uint(1) {
+	case orcEncodingDirect, orcEncodingDirectV2:
+		lengths, err := decodeOrcIntRle(lengthStream, count, false, v2)
+		if err != nil {
+			return nil, err
+		}
+		return orcSplitStrings(dataStream, lengths)
+	case orcEncodingDictionary, orcEncodingDictionaryV2:
+		dictionaryData, err := read(orcStreamDictionaryData)
+		if err != nil {
+			return nil, err
+		}
+		lengths, err := decodeOrcIntRle(lengthStream, int(encoding.uint(2)), false, v2)
+		if err != nil {
+			return nil, err
+		}
+		dictionary, err := orcSplitStrings(dictionaryData, lengths)
+		if err != nil {
+			return nil, err
+		}
+		indices, err := decodeOrcIntRle(dataStream, count, false, v2)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, count)
+		for i, index := range indices {
+			if index < 0 || index >= int64(len(dictionary)) {
+				return nil, fmt.Errorf("dictionary index %d out of range", index)
+			}
+			values[i] = dictionary[index]
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported orc column encoding %d", encoding.uint(1))
+}
+
+func orcSplitStrings(data []byte, lengths []int64) ([]string, error) {
+	values := make([]string, len(lengths))
+	var pos int64
+	for i, n := range lengths {
+		if n < 0 || pos+n > int64(len(data)) {
+			return nil, fmt.Errorf("string values truncated")
+		}
+		values[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	return values, nil
+}
+
+// readOrcTimestamps 解码时间戳列，秒数相对基准时间，纳秒存放于SECONDARY流
+func readOrcTimestamps(dataStream []byte, read func(kind uint64) ([]byte, error), count int, v2 bool, base int64) ([]string, error) {
+	seconds, err := decodeOrcIntRle(dataStream, count, true, v2)
+	if err != nil {
+		return nil, err
+	}
+	secondary, err := read(orcStreamSecondary)
+	if err != nil {
+		return nil, err
+	}
+	nanos, err := decodeOrcIntRle(secondary, count, false, v2)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, count)
+	for i := 0; i < count; i++ {
+		// 低3位表示省略的末尾0个数
+		nano := nanos[i] >> 3
+		if zeros := nanos[i] & 7; zeros != 0 {
+			for z := int64(0); z <= zeros; z++ {
+				nano *= 10
+			}
+		}
+		values[i] = time.Unix(base+seconds[i], nano).UTC().Format(time.RFC3339)
+	}
+	return values, nil
+}
+
+// decodeOrcByteRle 解码字节游程编码
+func decodeOrcByteRle(data []byte, count int) ([]byte, error) {
+	values := make([]byte, 0, count)
+	pos := 0
+	for len(values) < count {
+		if pos >= len(data) {
+			return nil, fmt.Errorf("byte rle truncated")
+		}
+		control := int8(data[pos])
+		pos++
+		if control >= 0 {
+			if pos >= len(data) {
+				return nil, fmt.Errorf("byte rle truncated")
+			}
+			for i := 0; i < int(control)+3; i++ {
+				values = append(values, data[pos])
+			}
+			pos++
+		} else {
+			n := -int(control)
+			if pos+n > len(data) {
+				return nil, fmt.Errorf("byte rle truncated")
+			}
+			values = append(values, data[pos:pos+n]...)
+			pos += n
+		}
+	}
+	return values[:count], nil
+}
+
+// decodeOrcBooleans 解码布尔游程编码，高位在前
+func decodeOrcBooleans(data []byte, count int) ([]bool, error) {
+	bytes, err := decodeOrcByteRle(data, (count+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]bool, count)
+	for i := range values {
+		values[i] = bytes[i/8]>>(7-uint(i)%8)&1 == 1
+	}
+	return values, nil
+}
+
+// decodeOrcIntRle 解码整数游程编码（v1或v2）
+func decodeOrcIntRle(data []byte, count int, signed bool, v2 bool) ([]int64, error) {
+	decoder := &orcIntDecoder{data: data, signed: signed}
+	values := make([]int64, 0, count)
+	var err error
+	for len(values) < count {
+		if decoder.pos >= len(data) {
+			return nil, fmt.Errorf("integer rle truncated")
+		}
+		if v2 {
+			values, err = decoder.readV2(values)
+		} else {
+			values, err = decoder.readV1(values)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values[:count], nil
+}
+
+type orcIntDecoder struct {
+	data   []byte
+	pos    int
+	signed bool
+}
+
+func (d *orcIntDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *orcIntDecoder) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	d.pos += n
+	return v, nil
+}
+
+func (d *orcIntDecoder) readVarint() (int64, error) {
+	v, err := d.readUvarint()
+	if err != nil {
+		return 0, err
+	}
+	if d.signed {
+		return zigzagDecode(v), nil
+	}
+	return int64(v), nil
+}
+
+// readV1 解码v1游程：控制字节非负为游程，负数为字面量
+func (d *orcIntDecoder) readV1(values []int64) ([]int64, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	control := int8(b)
+	if control >= 0 {
+		deltaByte, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		base, err := d.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < int(control)+3; i++ {
+			values = append(values, base+int64(i)*int64(int8(deltaByte)))
+		}
+		return values, nil
+	}
+	for i := 0; i < -int(control); i++ {
+		v, err := d.readVarint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// readV2 解码v2游程，首字节高2位表示子编码
+func (d *orcIntDecoder) readV2(values []int64) ([]int64, error) {
+	first, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	switch first >> 6 {
+	case 0:
+		// SHORT_REPEAT
+		width := int(first>>3&7) + 1
+		repeat := int(first&7) + 3
+		if d.pos+width > len(d.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		var v uint64
+		for i := 0; i < width; i++ {
+			v = v<<8 | uint64(d.data[d.pos+i])
+		}
+		d.pos += width
+		value := d.toInt(v)
+		for i := 0; i < repeat; i++ {
+			values = append(values, value)
+		}
+		return values, nil
+	case 1:
+		// DIRECT
+		width := orcDecodeBitWidth(first >> 1 & 0x1f)
+		second, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		length := int(first&1)<<8 | int(second) + 1
+		unpacked, err := d.readBits(length, width)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range unpacked {
+			values = append(values, d.toInt(v))
+		}
+		return values, nil
+	case 2:
+		return d.readPatchedBase(first, values)
+	default:
+		return d.readDelta(first, values)
+	}
+}
+
+// readPatchedBase 解码PATCHED_BASE子编码
+func (d *orcIntDecoder) readPatchedBase(first byte, values []int64) ([]int64, error) {
+	width := orcDecodeBitWidth(first >> 1 & 0x1f)
+	if d.pos+3 > len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	length := int(first&1)<<8 | int(d.data[d.pos]) + 1
+	third, fourth := d.data[d.pos+1], d.data[d.pos+2]
+	d.pos += 3
+	baseWidth := int(third>>5&7) + 1
+	patchWidth := orcDecodeBitWidth(third & 0x1f)
+	gapWidth := int(fourth>>5&7) + 1
+	patchCount := int(fourth & 0x1f)
+
+	if d.pos+baseWidth > len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	var base uint64
+	for i := 0; i < baseWidth; i++ {
+		base = base<<8 | uint64(d.data[d.pos+i])
+	}
+	d.pos += baseWidth
+	// 基准值最高位为符号位
+	mask := uint64(1) << (uint(baseWidth)*8 - 1)
+	baseValue := int64(base)
+	if base&mask != 0 {
+		baseValue = -int64(base &^ mask)
+	}
+
+	unpacked, err := d.readBits(length, width)
+	if err != nil {
+		return nil, err
+	}
+	patches, err := d.readBits(patchCount, orcClosestFixedBits(patchWidth+gapWidth))
+	if err != nil {
+		return nil, err
+	}
+
+	patchMask := uint64(1)<<uint(patchWidth) - 1
+	index := 0
+	for _, patch := range patches {
+		index += int(patch >> uint(patchWidth))
+		if value := patch & patchMask; value != 0 || patch>>uint(patchWidth) != 255 {
+			if index >= len(unpacked) {
+				return nil, fmt.Errorf("invalid patch position %d", index)
+			}
+			unpacked[index] |= value << uint(width)
+		}
+	}
+	for _, v := range unpacked {
+		values = append(values, baseValue+int64(v))
+	}
+	return values, nil
+}
+
+// readDelta 解码DELTA子编码
+func (d *orcIntDecoder) readDelta(first byte, values []int64) ([]int64, error) {
+	width := 0
+	if code := first >> 1 & 0x1f; code != 0 {
+		width = orcDecodeBitWidth(code)
+	}
+	second, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(first&1)<<8 | int(second)
+	base, err := d.readVarint()
+	if err != nil {
+		return nil, err
+	}
+	values = append(values, base)
+	deltaRaw, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	delta := zigzagDecode(deltaRaw)
+	if width == 0 {
+		for i := 0; i < length; i++ {
+			values = append(values, values[len(values)-1]+delta)
+		}
+		return values, nil
+	}
+	previous := base + delta
+	values = append(values, previous)
+	deltas, err := d.readBits(length-1, width)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range deltas {
+		if delta < 0 {
+			previous -= int64(v)
+		} else {
+			previous += int64(v)
+		}
+		values = append(values, previous)
+	}
+	return values, nil
+}
+
+// readBits 读取count个width位的大端位打包值，结束后对齐到字节
+func (d *orcIntDecoder) readBits(count int, width int) ([]uint64, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("invalid run length %d", count)
+	}
+	size := (count*width + 7) / 8
+	if d.pos+size > len(d.data) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	data := d.data[d.pos : d.pos+size]
+	values := make([]uint64, count)
+	bit := 0
+	for i := range values {
+		var v uint64
+		for b := 0; b < width; b++ {
+			v = v<<1 | uint64(data[bit/8]>>(7-uint(bit)%8)&1)
+			bit++
+		}
+		values[i] = v
+	}
+	d.pos += size
+	return values, nil
+}
+
+func (d *orcIntDecoder) toInt(v uint64) int64 {
+	if d.signed {
+		return zigzagDecode(v)
+	}
+	return int64(v)
+}
+
+// orcDecodeBitWidth 将5位编码转换为位宽
+func orcDecodeBitWidth(code byte) int {
+	switch {
+	case code <= 23:
+		return int(code) + 1
+	case code == 24:
+		return 26
+	case code == 25:
+		return 28
+	case code == 26:
+		return 30
+	case code == 27:
+		return 32
+	case code == 28:
+		return 40
+	case code == 29:
+		return 48
+	case code == 30:
+		return 56
+	}
+	return 64
+}
+
+// orcClosestFixedBits 取不小于n的可编码位宽
+func orcClosestFixedBits(n int) int {
+	switch {
+	case n == 0:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 26:
+		return 26
+	case n <= 28:
+		return 28
+	case n <= 30:
+		return 30
+	case n <= 32:
+		return 32
+	case n <= 40:
+		return 40
+	case n <= 48:
+		return 48
+	case n <= 56:
+		return 56
+	}
+	return 64
+}
+
+// orcDecompress 解压按块压缩的数据，每块以3字节头部标识长度及是否为原始数据
+func orcDecompress(compression uint64, data []byte) ([]byte, error) {
+	if compression == orcCompressionNone {
+		return data, nil
+	}
+	var out []byte
+	for pos := 0; pos < len(data); {
+		if pos+3 > len(data) {
+			return nil, fmt.Errorf("compressed chunk header truncated")
+		}
+		header := int(data[pos]) | int(data[pos+1])<<8 | int(data[pos+2])<<16
+		pos += 3
+		n := header >> 1
+		if pos+n > len(data) {
+			return nil, fmt.Errorf("compressed chunk truncated")
+		}
+		chunk := data[pos : pos+n]
+		pos += n
+		if header&1 == 1 {
+			out = append(out, chunk...)
+			continue
+		}
+		switch compression {
+		case orcCompressionZlib:
+			reader := flate.NewReader(bytes.NewReader(chunk))
+			decoded, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, decoded...)
+		case orcCompressionSnappy:
+			decoded, err := snappy.Decode(nil, chunk)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, decoded...)
+		case orcCompressionZstd:
+			decoder, err := zstd.NewReader(nil)
+			if err != nil {
+				return nil, err
+			}
+			decoded, err := decoder.DecodeAll(chunk, nil)
+			decoder.Close()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, decoded...)
+		default:
+			return nil, fmt.Errorf("unsupported orc compression %d", compression)
+		}
+	}
+	return out, nil
+}
+
+// protobufMessage Protobuf消息，按字段号保存原始值
+type protobufMessage map[uint64][]protobufValue
+
+type protobufValue struct {
+	wireType uint64
+	varint   uint64
+	data     []byte
+}
+
+func parseProtobuf(data []byte) (protobufMessage, error) {
+	m := protobufMessage{}
+	for pos := 0; pos < len(data); {
+		key, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid protobuf key")
+		}
+		pos += n
+		value := protobufValue{wireType: key & 7}
+		switch value.wireType {
+		case 0:
+			if value.varint, n = binary.Uvarint(data[pos:]); n <= 0 {
+				return nil, fmt.Errorf("invalid protobuf varint")
+			}
+			pos += n
+		case 1:
+			if pos+8 > len(data) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			value.varint = binary.LittleEndian.Uint64(data[pos:])
+			pos += 8
+		case 2:
+			length, n := binary.Uvarint(data[pos:])
+			if n <= 0 || length > uint64(len(data)-pos-n) {
+				return nil, fmt.Errorf("invalid protobuf length")
+			}
+			pos += n
+			value.data = data[pos : pos+int(length)]
+			pos += int(length)
+		case 5:
+			if pos+4 > len(data) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			value.varint = uint64(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+		default:
+			return nil, fmt.Errorf("unsupported protobuf wire type %d", value.wireType)
+		}
+		m[key>>3] = append(m[key>>3], value)
+	}
+	return m, nil
+}
+
+func (m protobufMessage) uint(field uint64) uint64 {
+	values := m[field]
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1].varint
+}
+
+func (m protobufMessage) bytes(field uint64) []byte {
+	values := m[field]
+	if len(values) == 0 {
+		return nil
+	}
+	return values[len(values)-1].data
+}
+
+// uints 读取重复的整数字段，兼容packed编码
+func (m protobufMessage) uints(field uint64) []uint64 {
+	var result []uint64
+	for _, value := range m[field] {
+		if value.wireType != 2 {
+			result = append(result, value.varint)
+			continue
+		}
+		for pos := 0; pos < len(value.data); {
+			v, n := binary.Uvarint(value.data[pos:])
+			if n <= 0 {
+				break
+			}
+			result = append(result, v)
+			pos += n
+		}
+	}
+	return result
+}
+
+func (m protobufMessage) strings(field uint64) []string {
+	result := make([]string, 0, len(m[field]))
+	for _, value := range m[field] {
+		result = append(result, string(value.data))
+	}
+	return result
+}
+
+func (m protobufMessage) messages(field uint64) ([]protobufMessage, error) {
+	var result []protobufMessage
+	for _, value := range m[field] {
+		message, err := parseProtobuf(value.data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, message)
+	}
+	return result, nil
+}
@@ -0,0 +1,871 @@
+package util
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parquet物理类型
+const (
+	parquetBoolean           = 0
+	parquetInt32             = 1
+	parquetInt64             = 2
+	parquetInt96             = 3
+	parquetFloat             = 4
+	parquetDouble            = 5
+	parquetByteArray         = 6
+	parquetFixedLenByteArray = 7
+)
+
+// Parquet页类型
+const (
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+)
+
+// Parquet编码
+const (
+	parquetPlain                = 0
+	parquetPlainDictionary      = 2
+	parquetRle                  = 3
+	parquetDeltaBinaryPacked    = 5
+	parquetDeltaLengthByteArray = 6
+	parquetDeltaByteArray       = 7
+	parquetRleDictionary        = 8
+)
+
+const parquetMagic = "PAR1"
+
+// parquetColumn 清单需要读取的顶层列
+type parquetColumn struct {
+	name      string
+	index     int
+	typ       int64
+	typeLen   int
+	optional  bool
+	timestamp time.Duration // 时间戳单位，0表示非时间戳
+	date      bool
+}
+
+// walkParquetInventory 按行组读取Parquet清单文件，仅支持非嵌套的顶层列
+func walkParquetInventory(r io.ReaderAt, size int64, handle func(columns map[string][]string, rows int) error) error {
+	if size < 12 {
+		return fmt.Errorf("invalid parquet file, size %d", size)
+	}
+	tail := make([]byte, 8)
+	if _, err := r.ReadAt(tail, size-8); err != nil {
+		return err
+	}
+	if string(tail[4:]) != parquetMagic {
+		return fmt.Errorf("invalid parquet file, magic number mismatch")
+	}
+	footerLen := int64(binary.LittleEndian.Uint32(tail))
+	if footerLen <= 0 || footerLen > size-12 {
+		return fmt.Errorf("invalid parquet footer length %d", footerLen)
+	}
+	footer := make([]byte, footerLen)
+	if _, err := r.ReadAt(footer, size-8-footerLen); err != nil {
+		return err
+	}
+	meta, err := newThriftReader(footer).readStruct()
+	if err != nil {
+		return fmt.Errorf("parse parquet footer error : %v", err)
+	}
+
+	columns, err := parquetColumns(meta.list(2))
+	if err != nil {
+		return err
+	}
+
+	for _, rowGroup := range meta.list(4) {
+		group, ok := rowGroup.(thriftStruct)
+		if !ok {
+			return fmt.Errorf("invalid parquet row group")
+		}
+		rows := int(group.int(3))
+		chunks := group.list(1)
+		values := make(map[string][]string, len(columns))
+		for _, column := range columns {
+			if column.index >= len(chunks) {
+				return fmt.Errorf("parquet column %s has no data in row group", column.name)
+			}
+			chunk, ok := chunks[column.index].(thriftStruct)
+			if !ok {
+				return fmt.Errorf("invalid parquet column chunk")
+			}
+			values[column.name], err = readParquetColumnChunk(r, chunk.child(3), column, rows)
+			if err != nil {
+				return fmt.Errorf("read parquet column %s error : %v", column.name, err)
+			}
+		}
+		if err = handle(values, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parquetColumns 解析schema，返回非嵌套的顶层叶子列及其在列块中的下标
+func parquetColumns(schema []interface{}) ([]*parquetColumn, error) {
+	if len(schema) == 0 {
+		return nil, fmt.Errorf("parquet file has no schema")
+	}
+	var columns []*parquetColumn
+	leaf := 0
+	// 第一个元素为根节点，其后按深度优先顺序排列
+	var walk func(i int, depth int) (int, error)
+	walk = func(i int, depth int) (int, error) {
+		if i >= len(schema) {
+			return i, fmt.Errorf("invalid parquet schema")
+		}
+		element, ok := schema[i].(thriftStruct)
+		if !ok {
+			return i, fmt.Errorf("invalid parquet schema element")
+		}
+		children := int(element.int(5))
+		if children == 0 && i > 0 {
+			// 重复及嵌套列不参与统计
+			if depth == 1 && element.int(3) != 2 {
+				column := &parquetColumn{
+					name:     element.string(4),
+					index:    leaf,
+					typ:      element.int(1),
+					typeLen:  int(element.int(2)),
+					optional: element.int(3) == 1,
+				}
+				parquetLogicalType(element, column)
+				columns = append(columns, column)
+			}
+			leaf++
+			return i + 1, nil
+		}
+		next := i + 1
+		for c := 0; c < children; c++ {
+			var err error
+			if next, err = walk(next, depth+1); err != nil {
+				return next, err
+			}
+		}
+		return next, nil
+	}
+	if _, err := walk(0, 0); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// parquetLogicalType 识别时间戳及日期类型
+func parquetLogicalType(element thriftStruct, column *parquetColumn) {
+	if logical := element.child(10); logical != nil {
+		if ts := logical.child(8); ts != nil {
+			unit := ts.child(2)
+			switch {
+			case unit == nil:
+			case unit.child(1) != nil:
+				column.timestamp = time.Millisecond
+			case unit.child(2) != nil:
+				column.timestamp = time.Microsecond
+			case unit.child(3) != nil:
+				column.timestamp = time.Nanosecond
+			}
+			return
+		}
+		if logical.child(6) != nil {
+			column.date = true
+			return
+		}
+	}
+	if !element.has(6) {
+		return
+	}
+	switch element.int(6) {
+	case 6:
+		column.date = true
+	case 9:
+		column.timestamp = time.Millisecond
+	case 10:
+		column.timestamp = time.Microsecond
+	}
+}
+
+// readParquetColumnChunk 读取列块中的全部数据页，空值以空字符串表示
+func readParquetColumnChunk(r io.ReaderAt, meta thriftStruct, column *parquetColumn, rows int) ([]string, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("column chunk has no metadata")
+	}
+	codec := meta.int(4)
+	offset := meta.int(9)
+	if meta.has(11) && meta.int(11) > 0 && meta.int(11) < offset {
+		offset = meta.int(11)
+	}
+	length := meta.int(7)
+	if length <= 0 {
+		return nil, fmt.Errorf("invalid column chunk size %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := r.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+
+	var dictionary []string
+	values := make([]string, 0, rows)
+	for len(values) < rows {
+		if len(data) == 0 {
+			return nil, fmt.Errorf("column chunk ends after %d of %d values", len(values), rows)
+		}
+		reader := newThriftReader(data)
+		header, err := reader.readStruct()
+		if err != nil {
+			return nil, fmt.Errorf("parse page header error : %v", err)
+		}
+		data = data[reader.pos:]
+		compressedSize := int(header.int(3))
+		if compressedSize < 0 || compressedSize > len(data) {
+			return nil, fmt.Errorf("invalid page size %d", compressedSize)
+		}
+		page := data[:compressedSize]
+		data = data[compressedSize:]
+		uncompressedSize := int(header.int(2))
+
+		switch header.int(1) {
+		case parquetDictionaryPage:
+			page, err = parquetDecompress(codec, page, uncompressedSize)
+			if err != nil {
+				return nil, err
+			}
+			dictHeader := header.child(7)
+			if dictHeader == nil {
+				return nil, fmt.Errorf("dictionary page has no header")
+			}
+			dictionary, _, err = parquetDecodePlain(page, column, int(dictHeader.int(1)))
+			if err != nil {
+				return nil, err
+			}
+		case parquetDataPage:
+			page, err = parquetDecompress(codec, page, uncompressedSize)
+			if err != nil {
+				return nil, err
+			}
+			pageHeader := header.child(5)
+			if pageHeader == nil {
+				return nil, fmt.Errorf("data page has no header")
+			}
+			count := int(pageHeader.int(1))
+			var defined []bool
+			if column.optional {
+				if len(page) < 4 {
+					return nil, fmt.Errorf("invalid definition levels")
+				}
+				n := int(binary.LittleEndian.Uint32(page))
+				if n > len(page)-4 {
+					return nil, fmt.Errorf("invalid definition levels length %d", n)
+				}
+				defined, err = parquetDefinitionLevels(page[4:4+n], count)
+				if err != nil {
+					return nil, err
+				}
+				page = page[4+n:]
+			}
+			values, err = parquetAppendValues(values, page, column, pageHeader.int(2), dictionary, count, defined)
+			if err != nil {
+				return nil, err
+			}
+		case parquetDataPageV2:
+			pageHeader := header.child(8)
+			if pageHeader == nil {
+				return nil, fmt.Errorf("data page v2 has no header")
+			}
+			count := int(pageHeader.int(1))
+			defLen := int(pageHeader.int(5))
+			repLen := int(pageHeader.int(6))
+			if defLen < 0 || repLen < 0 || defLen+repLen > len(page) {
+				return nil, fmt.Errorf("invalid data page v2 levels")
+			}
+			var defined []bool
+			if column.optional {
+				defined, err = parquetDefinitionLevels(page[repLen:repLen+defLen], count)
+				if err != nil {
+					return nil, err
+				}
+			}
+			page = page[repLen+defLen:]
+			// is_compressed缺省为true
+			if !pageHeader.has(7) || pageHeader.bool(7) {
+				page, err = parquetDecompress(codec, page, uncompressedSize-repLen-defLen)
+				if err != nil {
+					return nil, err
+				}
+			}
+			values, err = parquetAppendValues(values, page, column, pageHeader.int(4), dictionary, count, defined)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return values[:rows], nil
+}
+
+// parquetDefinitionLevels 解析定义级别，返回每个值是否非空
+func parquetDefinitionLevels(data []byte, count int) ([]bool, error) {
+	levels, err := decodeParquetHybrid(data, 1, count)
+	if err != nil {
+		return nil, fmt.Errorf("decode definition levels error : %v", err)
+	}
+	defined := make([]bool, count)
+	for i, level := range levels {
+		defined[i] = level == 1
+	}
+	return defined, nil
+}
+
+// parquetAppendValues 解码数据页中的值，并按定义级别补齐空值
+func parquetAppendValues(values []string, page []byte, column *parquetColumn, encoding int64, dictionary []string, count int, defined []bool) ([]string, error) {
+	present := count
+	if defined != nil {
+		present = 0
+		for _, d := range defined {
+			if d {
+				present++
+			}
+		}
+	}
+
+	var decoded []string
+	var err error
+	switch encoding {
+	case parquetPlain:
+		decoded, _, err = parquetDecodePlain(page, column, present)
+	case parquetPlainDictionary, parquetRleDictionary:
+		if dictionary == nil {
+			return nil, fmt.Errorf("dictionary page not found")
+		}
+		if len(page) == 0 {
+			if present > 0 {
+				return nil, fmt.Errorf("invalid dictionary indices")
+			}
+			break
+		}
+		var indices []uint64
+		indices, err = decodeParquetHybrid(page[1:], int(page[0]), present)
+		if err != nil {
+			break
+		}
+		decoded = make([]string, present)
+		for i, index := range indices {
+			if index >= uint64(len(dictionary)) {
+				return nil, fmt.Errorf("dictionary index %d out of range", index)
+			}
+			decoded[i] = dictionary[index]
+		}
+	case parquetDeltaBinaryPacked:
+		var ints []int64
+		ints, _, err = decodeParquetDeltaBinaryPacked(page, present)
+		if err == nil {
+			decoded = make([]string, len(ints))
+			for i, v := range ints {
+				decoded[i] = column.formatInt(v)
+			}
+		}
+	case parquetDeltaLengthByteArray:
+		decoded, err = decodeParquetDeltaLengthByteArray(page, present)
+	case parquetDeltaByteArray:
+		decoded, err = decodeParquetDeltaByteArray(page, present)
+	default:
+		return nil, fmt.Errorf("unsupported parquet encoding %d", encoding)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < present {
+		return nil, fmt.Errorf("expected %d values, got %d", present, len(decoded))
+	}
+
+	if defined == nil {
+		return append(values, decoded[:present]...), nil
+	}
+	next := 0
+	for _, d := range defined {
+		if d {
+			values = append(values, decoded[next])
+			next++
+		} else {
+			values = append(values, "")
+		}
+	}
+	return values, nil
+}
+
+// parquetDecodePlain 按PLAIN编码解码count个值，返回已读取的字节数
+func parquetDecodePlain(data []byte, column *parquetColumn, count int) ([]string, int, error) {
+	values := make([]string, 0, count)
+	pos := 0
+	need := func(n int) error {
+		if n < 0 || pos+n > len(data) {
+			return fmt.Errorf("plain values truncated")
+		}
+		return nil
+	}
+	if column.typ == parquetBoolean {
+		// 布尔值按位打包，低位在前
+		if err := need((count + 7) / 8); err != nil {
+			return nil, 0, err
+		}
+		for i := 0; i < count; i++ {
+			values = append(values, strconv.FormatBool(data[i/8]>>(uint(i)%8)&1 == 1))
+		}
+		return values, (count + 7) / 8, nil
+	}
+	for i := 0; i < count; i++ {
+		switch column.typ {
+		case parquetInt32:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, column.formatInt(int64(int32(binary.LittleEndian.Uint32(data[pos:])))))
+			pos += 4
+		case parquetInt64:
+			if err := need(8); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, column.formatInt(int64(binary.LittleEndian.Uint64(data[pos:]))))
+			pos += 8
+		case parquetInt96:
+			if err := need(12); err != nil {
+				return nil, 0, err
+			}
+			// 前8字节为当天纳秒数，后4字节为儒略日
+			nanos := int64(binary.LittleEndian.Uint64(data[pos:]))
+			days := int64(binary.LittleEndian.Uint32(data[pos+8:])) - 2440588
+			values = append(values, time.Unix(days*86400, nanos).UTC().Format(time.RFC3339))
+			pos += 12
+		case parquetFloat:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			f := math.Float32frombits(binary.LittleEndian.Uint32(data[pos:]))
+			values = append(values, strconv.FormatFloat(float64(f), 'f', -1, 32))
+			pos += 4
+		case parquetDouble:
+			if err := need(8); err != nil {
+				return nil, 0, err
+			}
+			f := math.Float64frombits(binary.LittleEndian.Uint64(data[pos:]))
+			values = append(values, strconv.FormatFloat(f, 'f', -1, 64))
+			pos += 8
+		case parquetByteArray:
+			if err := need(4); err != nil {
+				return nil, 0, err
+			}
+			n := int(binary.LittleEndian.Uint32(data[pos:]))
+			pos += 4
+			if err := need(n); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, string(data[pos:pos+n]))
+			pos += n
+		case parquetFixedLenByteArray:
+			if err := need(column.typeLen); err != nil {
+				return nil, 0, err
+			}
+			values = append(values, string(data[pos:pos+column.typeLen]))
+			pos += column.typeLen
+		default:
+			return nil, 0, fmt.Errorf("unsupported parquet type %d", column.typ)
+		}
+	}
+	return values, pos, nil
+}
+
+// formatInt 将整数列转换为字符串，时间戳转换为RFC3339格式
+func (c *parquetColumn) formatInt(v int64) string {
+	switch {
+	case c.timestamp != 0:
+		return time.Unix(0, 0).Add(time.Duration(v) * c.timestamp).UTC().Format(time.RFC3339)
+	case c.date:
+		return time.Unix(v*86400, 0).UTC().Format(time.RFC3339)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// decodeParquetHybrid 解码RLE与位打包混合编码
+func decodeParquetHybrid(data []byte, bitWidth int, count int) ([]uint64, error) {
+	if bitWidth < 0 || bitWidth > 64 {
+		return nil, fmt.Errorf("invalid bit width %d", bitWidth)
+	}
+	values := make([]uint64, 0, count)
+	pos := 0
+	for len(values) < count {
+		header, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid rle header")
+		}
+		pos += n
+		if header&1 == 0 {
+			// RLE游程
+			width := (bitWidth + 7) / 8
+			if pos+width > len(data) {
+				return nil, fmt.Errorf("rle run truncated")
+			}
+			var value uint64
+			for i := 0; i < width; i++ {
+				value |= uint64(data[pos+i]) << (8 * uint(i))
+			}
+			pos += width
+			for i := uint64(0); i < header>>1 && len(values) < count; i++ {
+				values = append(values, value)
+			}
+			continue
+		}
+		// 位打包，每组8个值，低位在前
+		total := int(header>>1) * 8
+		end := pos + int(header>>1)*bitWidth
+		if end > len(data) {
+			end = len(data)
+		}
+		bit := 0
+		for i := 0; i < total && len(values) < count; i++ {
+			var value uint64
+			for b := 0; b < bitWidth; b++ {
+				byteIndex := pos + (bit+b)/8
+				if byteIndex >= end {
+					return nil, fmt.Errorf("bit packed run truncated")
+				}
+				value |= uint64(data[byteIndex]>>(uint(bit+b)%8)&1) << uint(b)
+			}
+			bit += bitWidth
+			values = append(values, value)
+		}
+		pos += int(header>>1) * bitWidth
+	}
+	return values, nil
+}
+
+// decodeParquetDeltaBinaryPacked 解码DELTA_BINARY_PACKED编码，返回已读取的字节数
+func decodeParquetDeltaBinaryPacked(data []byte, count int) ([]int64, int, error) {
+	pos := 0
+	readUvarint := func() (uint64, error) {
+		v, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return 0, fmt.Errorf("invalid delta header")
+		}
+		pos += n
+		return v, nil
+	}
+	blockSize, err := readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	miniBlocks, err := readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	first, err := readUvarint()
+	if err != nil {
+		return nil, 0, err
+	}
+	if miniBlocks == 0 || blockSize%miniBlocks != 0 {
+		return nil, 0, fmt.Errorf("invalid delta block size")
+	}
+	perMiniBlock := int(blockSize / miniBlocks)
+
+	values := make([]int64, 0, total)
+	last := zigzagDecode(first)
+	if total > 0 {
+		values = append(values, last)
+	}
+	for uint64(len(values)) < total {
+		minDelta, err := readUvarint()
+		if err != nil {
+			return nil, 0, err
+		}
+		if pos+int(miniBlocks) > len(data) {
+			return nil, 0, fmt.Errorf("delta block truncated")
+		}
+		widths := data[pos : pos+int(miniBlocks)]
+		pos += int(miniBlocks)
+		for _, width := range widths {
+			if uint64(len(values)) >= total {
+				break
+			}
+			size := perMiniBlock * int(width) / 8
+			if pos+size > len(data) {
+				return nil, 0, fmt.Errorf("delta mini block truncated")
+			}
+			for i := 0; i < perMiniBlock; i++ {
+				var delta uint64
+				for b := 0; b < int(width); b++ {
+					bit := i*int(width) + b
+					delta |= uint64(data[pos+bit/8]>>(uint(bit)%8)&1) << uint(b)
+				}
+				if uint64(len(values)) < total {
+					last += zigzagDecode(minDelta) + int64(delta)
+					values = append(values, last)
+				}
+			}
+			pos += size
+		}
+	}
+	if len(values) < count {
+		return nil, 0, fmt.Errorf("expected %d values, got %d", count, len(values))
+	}
+	return values, pos, nil
+}
+
+// decodeParquetDeltaLengthByteArray 解码DELTA_LENGTH_BYTE_ARRAY编码
+func decodeParquetDeltaLengthByteArray(data []byte, count int) ([]string, error) {
+	lengths, pos, err := decodeParquetDeltaBinaryPacked(data, count)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, count)
+	for i := 0; i < count; i++ {
+		n := int(lengths[i])
+		if n < 0 || pos+n > len(data) {
+			return nil, fmt.Errorf("delta length byte array truncated")
+		}
+		values[i] = string(data[pos : pos+n])
+		pos += n
+	}
+	return values, nil
+}
+
+// decodeParquetDeltaByteArray 解码DELTA_BYTE_ARRAY编码（前缀长度+后缀）
+func decodeParquetDeltaByteArray(data []byte, count int) ([]string, error) {
+	prefixes, pos, err := decodeParquetDeltaBinaryPacked(data, count)
+	if err != nil {
+		return nil, err
+	}
+	suffixes, err := decodeParquetDeltaLengthByteArray(data[pos:], count)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, count)
+	previous := ""
+	for i := 0; i < count; i++ {
+		n := int(prefixes[i])
+		if n < 0 || n > len(previous) {
+			return nil, fmt.Errorf("invalid delta byte array prefix length %d", n)
+		}
+		values[i] = previous[:n] + suffixes[i]
+		previous = values[i]
+	}
+	return values, nil
+}
+
+// parquetDecompress 解压数据页
+func parquetDecompress(codec int64, data []byte, size int) ([]byte, error) {
+	switch codec {
+	case 0:
+		return data, nil
+	case 1:
+		return snappy.Decode(nil, data)
+	case 2:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	case 6:
+		decoder, err := zstd.NewReader(nil)
+		if err != nil {
+			return nil, err
+		}
+		defer decoder.Close()
+		return decoder.DecodeAll(data, make([]byte, 0, size))
+	}
+	return nil, fmt.Errorf("unsupported parquet compression codec %d", codec)
+}
+
+func zigzagDecode(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// thriftStruct Thrift结构体，按字段ID保存字段值
+type thriftStruct map[int16]interface{}
+
+func (s thriftStruct) has(id int16) bool {
+	_, ok := s[id]
+	return ok
+}
+
+func (s thriftStruct) int(id int16) int64 {
+	v, _ := s[id].(int64)
+	return v
+}
+
+func (s thriftStruct) bool(id int16) bool {
+	v, _ := s[id].(bool)
+	return v
+}
+
+func (s thriftStruct) string(id int16) string {
+	v, _ := s[id].([]byte)
+	return string(v)
+}
+
+func (s thriftStruct) list(id int16) []interface{} {
+	v, _ := s[id].([]interface{})
+	return v
+}
+
+func (s thriftStruct) child(id int16) thriftStruct {
+	v, _ := s[id].(thriftStruct)
+	return v
+}
+
+// thriftReader Thrift Compact协议解码
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func newThriftReader(data []byte) *thriftReader {
+	return &thriftReader{data: data}
+}
+
+func (r *thriftReader) readByte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *thriftReader) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *thriftReader) readStruct() (thriftStruct, error) {
+	s := thriftStruct{}
+	var id int16
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		typ := b & 0x0f
+		if typ == 0 {
+			return s, nil
+		}
+		if delta := b >> 4; delta != 0 {
+			id += int16(delta)
+		} else {
+			v, err := r.readUvarint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(zigzagDecode(v))
+		}
+		switch typ {
+		case 1:
+			s[id] = true
+		case 2:
+			s[id] = false
+		default:
+			if s[id], err = r.readValue(typ); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (r *thriftReader) readValue(typ byte) (interface{}, error) {
+	switch typ {
+	case 1, 2:
+		// 集合中的布尔值占一个字节
+		b, err := r.readByte()
+		return b == 1, err
+	case 3:
+		b, err := r.readByte()
+		return int64(int8(b)), err
+	case 4, 5, 6:
+		v, err := r.readUvarint()
+		return zigzagDecode(v), err
+	case 7:
+		if r.pos+8 > len(r.data) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return v, nil
+	case 8:
+		n, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if n > uint64(len(r.data)-r.pos) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := r.data[r.pos : r.pos+int(n)]
+		r.pos += int(n)
+		return v, nil
+	case 9, 10:
+		b, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		size := uint64(b >> 4)
+		if size == 15 {
+			if size, err = r.readUvarint(); err != nil {
+				return nil, err
+			}
+		}
+		if size > uint64(len(r.data)-r.pos) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		list := make([]interface{}, 0, size)
+		for i := uint64(0); i < size; i++ {
+			v, err := r.readValue(b & 0x0f)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case 11:
+		size, err := r.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		types, err := r.readByte()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < size; i++ {
+			if _, err = r.readValue(types >> 4); err != nil {
+				return nil, err
+			}
+			if _, err = r.readValue(types & 0x0f); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	case 12:
+		return r.readStruct()
+	}
+	return nil, fmt.Errorf("unknown thrift type %d", typ)
+}
@@ -0,0 +1,208 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PutBucketLifecycle 设置存储桶生命周期
+func PutBucketLifecycle(c *cos.Client, lifecycle string) error {
+	configurationContent, err := GetContent(lifecycle)
+	if err != nil {
+		return err
+	}
+	var opt cos.BucketPutLifecycleOptions
+	err = ParseContent(configurationContent, &opt, ContentTypeLifecycle)
+	if err != nil {
+		return err
+	}
+	if len(opt.Rules) == 0 {
+		return fmt.Errorf("no lifecycle rule provided")
+	}
+	_, err = c.Bucket.PutLifecycle(context.Background(), &opt)
+	if err != nil {
+		return err
+	}
+	logger.Info("Put Bucket Lifecycle Success")
+	return nil
+}
+
+// GetBucketLifecycle 查询存储桶生命周期
+func GetBucketLifecycle(c *cos.Client) error {
+	res, _, err := c.Bucket.GetLifecycle(context.Background())
+	if err != nil {
+		return err
+	}
+	// 渲染表格
+	renderLifecycleTable(res)
+	return nil
+}
+
+// DeleteBucketLifecycle 删除存储桶生命周期
+func DeleteBucketLifecycle(c *cos.Client) error {
+	_, err := c.Bucket.DeleteLifecycle(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Delete Bucket Lifecycle Success")
+	return nil
+}
+
+func renderLifecycleTable(lifecycle *cos.BucketGetLifecycleResult) {
+	if lifecycle == nil || len(lifecycle.Rules) == 0 {
+		fmt.Println("No lifecycle rule found")
+		return
+	}
+
+	// 创建表格
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Section", "Key", "Value"})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCaption(true, "Bucket Lifecycle Information")
+
+	// 遍历所有规则
+	for i, rule := range lifecycle.Rules {
+		section := fmt.Sprintf("Rule #%d", i+1)
+
+		table.Append([]string{section, "ID", rule.ID})
+		table.Append([]string{section, "Status", rule.Status})
+
+		// 添加过滤条件
+		table.Append([]string{section, "Filter", formatLifecycleFilter(rule.Filter)})
+
+		// 添加沉降规则
+		if len(rule.Transition) > 0 {
+			var transitions []string
+			for _, transition := range rule.Transition {
+				transitions = append(transitions, formatLifecycleTransition(transition))
+			}
+			table.Append([]string{section, "Transition", strings.Join(transitions, "\n")})
+		}
+
+		// 添加过期规则
+		if rule.Expiration != nil {
+			table.Append([]string{section, "Expiration", formatLifecycleExpiration(rule.Expiration)})
+		}
+
+		// 添加历史版本沉降规则
+		if len(rule.NoncurrentVersionTransition) > 0 {
+			var transitions []string
+			for _, transition := range rule.NoncurrentVersionTransition {
+				transitions = append(transitions, formatLifecycleNoncurrentVersion(transition))
+			}
+			table.Append([]string{section, "NoncurrentVersionTransition", strings.Join(transitions, "\n")})
+		}
+
+		// 添加历史版本过期规则
+		if rule.NoncurrentVersionExpiration != nil {
+			table.Append([]string{section, "NoncurrentVersionExpiration", formatLifecycleNoncurrentVersion(*rule.NoncurrentVersionExpiration)})
+		}
+
+		// 添加碎片清理规则
+		if rule.AbortIncompleteMultipartUpload != nil {
+			table.Append([]string{section, "AbortIncompleteMultipartUpload",
+				fmt.Sprintf("DaysAfterInitiation: %d", rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)})
+		}
+
+		// 添加分隔行（最后一个不添加）
+		if i < len(lifecycle.Rules)-1 {
+			table.Append([]string{"", "", ""})
+		}
+	}
+
+	// 渲染表格
+	table.Render()
+}
+
+// 格式化过滤条件
+func formatLifecycleFilter(filter *cos.BucketLifecycleFilter) string {
+	if filter == nil {
+		return "All objects"
+	}
+
+	var builder strings.Builder
+	if filter.Prefix != "" {
+		builder.WriteString(fmt.Sprintf("Prefix: %s\n", filter.Prefix))
+	}
+	if filter.Tag != nil {
+		builder.WriteString(fmt.Sprintf("Tag: %s=%s\n", filter.Tag.Key, filter.Tag.Value))
+	}
+	if filter.And != nil {
+		builder.WriteString("And:\n")
+		if filter.And.Prefix != "" {
+			builder.WriteString(fmt.Sprintf("  Prefix: %s\n", filter.And.Prefix))
+		}
+		if filter.And.PrefixNotEquals != "" {
+			builder.WriteString(fmt.Sprintf("  PrefixNotEquals: %s\n", filter.And.PrefixNotEquals))
+		}
+		for _, tag := range filter.And.Tag {
+			builder.WriteString(fmt.Sprintf("  Tag: %s=%s\n", tag.Key, tag.Value))
+		}
+		if filter.And.ObjectSizeGreaterThan > 0 {
+			builder.WriteString(fmt.Sprintf("  ObjectSizeGreaterThan: %d\n", filter.And.ObjectSizeGreaterThan))
+		}
+		if filter.And.ObjectSizeLessThan > 0 {
+			builder.WriteString(fmt.Sprintf("  ObjectSizeLessThan: %d\n", filter.And.ObjectSizeLessThan))
+		}
+	}
+
+	if builder.Len() == 0 {
+		return "All objects"
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// 格式化沉降规则
+func formatLifecycleTransition(transition cos.BucketLifecycleTransition) string {
+	var when string
+	if transition.Date != "" {
+		when = fmt.Sprintf("Date: %s", transition.Date)
+	} else {
+		when = fmt.Sprintf("Days: %d", transition.Days)
+	}
+	result := fmt.Sprintf("%s -> %s", when, transition.StorageClass)
+	if transition.AccessFrequency != nil {
+		result += fmt.Sprintf(" (AccessCountLessThan: %d, RecentDays: %d)",
+			transition.AccessFrequency.AccessCountLessThan, transition.AccessFrequency.RecentDays)
+	}
+	return result
+}
+
+// 格式化过期规则
+func formatLifecycleExpiration(expiration *cos.BucketLifecycleExpiration) string {
+	var items []string
+	if expiration.Date != "" {
+		items = append(items, fmt.Sprintf("Date: %s", expiration.Date))
+	}
+	if expiration.Days > 0 {
+		items = append(items, fmt.Sprintf("Days: %d", expiration.Days))
+	}
+	if expiration.ExpiredObjectDeleteMarker {
+		items = append(items, "ExpiredObjectDeleteMarker: true")
+	}
+	return strings.Join(items, "\n")
+}
+
+// 格式化历史版本规则
+func formatLifecycleNoncurrentVersion(version cos.BucketLifecycleNoncurrentVersion) string {
+	result := fmt.Sprintf("NoncurrentDays: %d", version.NoncurrentDays)
+	if version.StorageClass != "" {
+		result += fmt.Sprintf(" -> %s", version.StorageClass)
+	}
+	if version.AccessFrequency != nil {
+		result += fmt.Sprintf(" (AccessCountLessThan: %d, RecentDays: %d)",
+			version.AccessFrequency.AccessCountLessThan, version.AccessFrequency.RecentDays)
+	}
+	return result
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
@@ -231,6 +232,72 @@ func ListObjects(c *cos.Client, cosUrl StorageUrl, limit int, recursive bool, fi
 	return nil
 }
 
+// ListInventoryObjects 以清单报告作为数据源列出对象
+func ListInventoryObjects(source *InventorySource, cosUrl StorageUrl, limit int, recursive bool, filters []FilterOptionType) error {
+	prefix := cosUrl.(*CosUrl).Object
+	total := 0
+	rows := 0
+	dirs := make(map[string]bool)
+	errLimit := fmt.Errorf("limit reached")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Key", "Type", "Last Modified", "Etag", "Size", "RestoreStatus"})
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+
+	err := source.Walk(cosUrl, func(object cos.Object) error {
+		if limit > 0 && total >= limit {
+			return errLimit
+		}
+
+		// 非递归时按目录聚合
+		if !recursive {
+			if index := strings.Index(object.Key[len(prefix):], CosSeparator); index >= 0 {
+				dir := object.Key[:len(prefix)+index+1]
+				if dirs[dir] || !cosObjectMatchPatterns(dir, filters) {
+					return nil
+				}
+				dirs[dir] = true
+				table.Append([]string{dir, "DIR", "", "", "", ""})
+				total++
+				rows++
+				return nil
+			}
+		}
+
+		if !cosObjectMatchPatterns(object.Key, filters) {
+			return nil
+		}
+		lastModified := object.LastModified
+		if utcTime, err := time.Parse(time.RFC3339, object.LastModified); err == nil {
+			lastModified = utcTime.Local().Format(time.RFC3339)
+		}
+		table.Append([]string{object.Key, object.StorageClass, lastModified, object.ETag, formatBytes(float64(object.Size)), ""})
+		total++
+		rows++
+
+		if rows >= 1000 {
+			table.Render()
+			// 重置表格
+			table = tablewriter.NewWriter(os.Stdout)
+			table.SetBorder(false)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetAutoWrapText(false)
+			rows = 0
+		}
+		return nil
+	})
+	if err != nil && err != errLimit {
+		return fmt.Errorf("list inventory objects error : %v", err)
+	}
+
+	table.SetFooter([]string{"", "", "", "", "Total Objects: ", fmt.Sprintf("%d", total)})
+	table.Render()
+
+	return nil
+}
+
 // ListObjectVersions lists the versions of an object in the COS bucket.
 //
 // c: *cos.Client - the COS client to use.
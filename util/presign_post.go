@@ -0,0 +1,126 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PostPolicyOptions 表单上传策略条件
+type PostPolicyOptions struct {
+	// Key 为空或以/结尾时按前缀限制对象键
+	Key         string
+	Expire      time.Duration
+	MinSize     int64
+	MaxSize     int64
+	ContentType string
+}
+
+// PostPolicyForm 浏览器表单上传所需的地址及表单字段
+type PostPolicyForm struct {
+	URL        string            `json:"url"`
+	Expiration string            `json:"expiration"`
+	Fields     map[string]string `json:"fields"`
+	Policy     interface{}       `json:"policy"`
+}
+
+type postPolicy struct {
+	Expiration string        `json:"expiration"`
+	Conditions []interface{} `json:"conditions"`
+}
+
+// GeneratePostPolicy 生成表单上传(POST Object)的策略及签名
+func GeneratePostPolicy(c *cos.Client, bucketName string, opt PostPolicyOptions) (*PostPolicyForm, error) {
+	credential := c.GetCredential()
+	if credential == nil || credential.SecretID == "" || credential.SecretKey == "" {
+		return nil, fmt.Errorf("can not get credential of the client")
+	}
+
+	now := time.Now()
+	expiration := now.Add(opt.Expire)
+	keyTime := fmt.Sprintf("%d;%d", now.Unix(), expiration.Unix())
+
+	fields := map[string]string{
+		"q-sign-algorithm": "sha1",
+		"q-ak":             credential.SecretID,
+		"q-key-time":       keyTime,
+	}
+	conditions := []interface{}{
+		map[string]string{"q-sign-algorithm": "sha1"},
+		map[string]string{"q-ak": credential.SecretID},
+		map[string]string{"q-sign-time": keyTime},
+		map[string]string{"bucket": bucketName},
+	}
+
+	if opt.Key == "" || strings.HasSuffix(opt.Key, CosSeparator) {
+		// ${filename} 由cos替换为浏览器上传的文件名
+		fields["key"] = opt.Key + "${filename}"
+		conditions = append(conditions, []string{"starts-with", "$key", opt.Key})
+	} else {
+		fields["key"] = opt.Key
+		conditions = append(conditions, []string{"eq", "$key", opt.Key})
+	}
+
+	if opt.MinSize > 0 || opt.MaxSize > 0 {
+		maxSize := opt.MaxSize
+		if maxSize == 0 {
+			maxSize = MaxPostObjectSize
+		}
+		conditions = append(conditions, []interface{}{"content-length-range", opt.MinSize, maxSize})
+	}
+
+	if opt.ContentType != "" {
+		// 以*结尾时按前缀限制，由表单自行填写Content-Type
+		if strings.HasSuffix(opt.ContentType, "*") {
+			conditions = append(conditions, []string{"starts-with", "$Content-Type", strings.TrimSuffix(opt.ContentType, "*")})
+		} else {
+			fields["Content-Type"] = opt.ContentType
+			conditions = append(conditions, []string{"eq", "$Content-Type", opt.ContentType})
+		}
+	}
+
+	if credential.SessionToken != "" {
+		fields["x-cos-security-token"] = credential.SessionToken
+		conditions = append(conditions, map[string]string{"x-cos-security-token": credential.SessionToken})
+	}
+
+	policy := postPolicy{
+		Expiration: expiration.UTC().Format("2006-01-02T15:04:05.000Z"),
+		Conditions: conditions,
+	}
+	policyJson, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	fields["policy"] = base64.StdEncoding.EncodeToString(policyJson)
+	fields["q-signature"] = postPolicySignature(credential.SecretKey, keyTime, policyJson)
+
+	return &PostPolicyForm{
+		URL:        c.BaseURL.BucketURL.String() + CosSeparator,
+		Expiration: policy.Expiration,
+		Fields:     fields,
+		Policy:     policy,
+	}, nil
+}
+
+// postPolicySignature 计算表单上传签名
+// SignKey = HMAC-SHA1(SecretKey, KeyTime)，StringToSign = SHA1(Policy)，Signature = HMAC-SHA1(SignKey, StringToSign)
+func postPolicySignature(secretKey, keyTime string, policy []byte) string {
+	signKey := hmacSha1Hex([]byte(secretKey), keyTime)
+	policySum := sha1.Sum(policy)
+	return hmacSha1Hex([]byte(signKey), hex.EncodeToString(policySum[:]))
+}
+
+func hmacSha1Hex(key []byte, data string) string {
+	h := hmac.New(sha1.New, key)
+	h.Write([]byte(data))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -11,6 +11,7 @@ import (
 const (
 	normalExit = iota
 	errExit
+	interruptExit
 )
 
 var (
@@ -193,6 +194,9 @@ func (fpm *FileProcessMonitor) getFinishBar(exitStat int) string {
 	if exitStat == normalExit {
 		return fpm.getWholeFinishBar()
 	}
+	if exitStat == interruptExit {
+		return fpm.getInterruptBar()
+	}
 	return fpm.getDefeatBar()
 }
 
@@ -220,6 +224,15 @@ func (fpm *FileProcessMonitor) getDefeatBar() string {
 	return getClearStr(fmt.Sprintf("Scanned %d %s. Processed num: %d%s%s. When error happens.\n", scanNum, fpm.getSubject(), snap.okNum, fpm.getOKNumDetail(snap), fpm.getSizeDetail(snap)))
 }
 
+func (fpm *FileProcessMonitor) getInterruptBar() string {
+	snap := fpm.getSnapshot()
+	if fpm.seekAheadEnd && fpm.seekAheadError == nil {
+		return getClearStr(fmt.Sprintf("Interrupted: Total num: %d, size: %s. Error num: %d. Processed num: %d%s%s.\n", fpm.totalNum, getSizeString(fpm.TotalSize), snap.errNum, snap.okNum, fpm.getOKNumDetail(snap), fpm.getSizeDetail(snap)))
+	}
+	scanNum := max(fpm.totalNum, snap.dealNum)
+	return getClearStr(fmt.Sprintf("Interrupted: Scanned %d %s. Error num: %d. Processed num: %d%s%s.\n", scanNum, fpm.getSubject(), snap.errNum, snap.okNum, fpm.getOKNumDetail(snap), fpm.getSizeDetail(snap)))
+}
+
 func (fpm *FileProcessMonitor) getSnapshot() *FileProcessMonitorSnap {
 	var snap FileProcessMonitorSnap
 	snap.transferSize = fpm.TransferSize
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PutBucketReferer 设置存储桶防盗链
+func PutBucketReferer(c *cos.Client, referer string) error {
+	configurationContent, err := GetContent(referer)
+	if err != nil {
+		return err
+	}
+	var opt cos.BucketPutRefererOptions
+	err = ParseContent(configurationContent, &opt, ContentTypeReferer)
+	if err != nil {
+		return err
+	}
+	if opt.Status == "" || len(opt.DomainList) == 0 {
+		return fmt.Errorf("no referer status or domain provided")
+	}
+	_, err = c.Bucket.PutReferer(context.Background(), &opt)
+	if err != nil {
+		return err
+	}
+	logger.Info("Put Bucket Referer Success")
+	return nil
+}
+
+// GetBucketReferer 查询存储桶防盗链
+func GetBucketReferer(c *cos.Client) error {
+	res, _, err := c.Bucket.GetReferer(context.Background())
+	if err != nil {
+		return err
+	}
+	// 渲染表格
+	renderRefererTable(res)
+	return nil
+}
+
+// DeleteBucketReferer 删除存储桶防盗链
+func DeleteBucketReferer(c *cos.Client) error {
+	_, err := c.Bucket.DeleteReferer(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Delete Bucket Referer Success")
+	return nil
+}
+
+func renderRefererTable(referer *cos.BucketGetRefererResult) {
+	if referer == nil || referer.Status == "" {
+		fmt.Println("No referer found")
+		return
+	}
+
+	// 创建表格
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Section", "Key", "Value"})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCaption(true, "Bucket Referer Information")
+
+	table.Append([]string{"Referer", "Status", referer.Status})
+	table.Append([]string{"Referer", "RefererType", referer.RefererType})
+	table.Append([]string{"Referer", "DomainList", strings.Join(referer.DomainList, "\n")})
+	if referer.EmptyReferConfiguration != "" {
+		table.Append([]string{"Referer", "EmptyReferConfiguration", referer.EmptyReferConfiguration})
+	}
+	if referer.VerifySignatureURL != "" {
+		table.Append([]string{"Referer", "VerifySignatureURL", referer.VerifySignatureURL})
+	}
+
+	// 渲染表格
+	table.Render()
+}
@@ -0,0 +1,256 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PutBucketReplication 设置存储桶跨地域复制规则
+func PutBucketReplication(c *cos.Client, replication string) error {
+	configurationContent, err := GetContent(replication)
+	if err != nil {
+		return err
+	}
+	var opt cos.PutBucketReplicationOptions
+	err = ParseContent(configurationContent, &opt, ContentTypeReplication)
+	if err != nil {
+		return err
+	}
+	if len(opt.Rule) == 0 {
+		return fmt.Errorf("no replication rule provided")
+	}
+	_, err = c.Bucket.PutBucketReplication(context.Background(), &opt)
+	if err != nil {
+		return err
+	}
+	logger.Info("Put Bucket Replication Success")
+	return nil
+}
+
+// GetBucketReplication 查询存储桶跨地域复制规则
+func GetBucketReplication(c *cos.Client) error {
+	res, _, err := c.Bucket.GetBucketReplication(context.Background())
+	if err != nil {
+		return err
+	}
+	// 渲染表格
+	renderReplicationTable(res)
+	return nil
+}
+
+// DeleteBucketReplication 删除存储桶跨地域复制规则
+func DeleteBucketReplication(c *cos.Client) error {
+	_, err := c.Bucket.DeleteBucketReplication(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Delete Bucket Replication Success")
+	return nil
+}
+
+func renderReplicationTable(replication *cos.GetBucketReplicationResult) {
+	if replication == nil || len(replication.Rule) == 0 {
+		fmt.Println("No replication rule found")
+		return
+	}
+
+	// 创建表格
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Section", "Key", "Value"})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCaption(true, "Bucket Replication Information")
+
+	table.Append([]string{"Replication", "Role", replication.Role})
+	table.Append([]string{"", "", ""})
+
+	// 遍历所有规则
+	for i, rule := range replication.Rule {
+		section := fmt.Sprintf("Rule #%d", i+1)
+
+		table.Append([]string{section, "ID", rule.ID})
+		table.Append([]string{section, "Status", rule.Status})
+		if rule.Priority > 0 {
+			table.Append([]string{section, "Priority", fmt.Sprintf("%d", rule.Priority)})
+		}
+		table.Append([]string{section, "Filter", formatReplicationFilter(rule)})
+		if rule.Destination != nil {
+			table.Append([]string{section, "Destination", rule.Destination.Bucket})
+			if rule.Destination.StorageClass != "" {
+				table.Append([]string{section, "StorageClass", rule.Destination.StorageClass})
+			}
+		}
+		if rule.DeleteMarkerReplication != nil {
+			table.Append([]string{section, "DeleteMarkerReplication", rule.DeleteMarkerReplication.Status})
+		}
+
+		// 添加分隔行（最后一个不添加）
+		if i < len(replication.Rule)-1 {
+			table.Append([]string{"", "", ""})
+		}
+	}
+
+	// 渲染表格
+	table.Render()
+}
+
+// 格式化复制规则过滤条件
+func formatReplicationFilter(rule cos.BucketReplicationRule) string {
+	var builder strings.Builder
+	if rule.Prefix != "" {
+		builder.WriteString(fmt.Sprintf("Prefix: %s\n", rule.Prefix))
+	}
+	if rule.Filter != nil {
+		if rule.Filter.Prefix != "" {
+			builder.WriteString(fmt.Sprintf("Prefix: %s\n", rule.Filter.Prefix))
+		}
+		if rule.Filter.And != nil {
+			builder.WriteString("And:\n")
+			if rule.Filter.And.Prefix != "" {
+				builder.WriteString(fmt.Sprintf("  Prefix: %s\n", rule.Filter.And.Prefix))
+			}
+			for _, tag := range rule.Filter.And.Tag {
+				builder.WriteString(fmt.Sprintf("  Tag: %s=%s\n", tag.Key, tag.Value))
+			}
+		}
+	}
+
+	if builder.Len() == 0 {
+		return "All objects"
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// ReplicationStatus 统计前缀下对象的跨地域复制状态
+func ReplicationStatus(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	bucketName := cosUrl.(*CosUrl).Bucket
+	prefix := cosUrl.(*CosUrl).Object
+
+	var mu sync.Mutex
+	var failedCnt int
+	summary := make(map[string]*CosInfo)
+
+	chObjects := make(chan cos.Object, ChannelSize)
+	var wg sync.WaitGroup
+	for i := 0; i < fo.Operation.Routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range chObjects {
+				status, err := getReplicationStatus(c, object.Key)
+				mu.Lock()
+				if err != nil {
+					failedCnt++
+					mu.Unlock()
+					if fo.Operation.FailOutput {
+						writeError(fmt.Sprintf("head %s failed , errMsg:%v\n", object.Key, err), fo)
+					}
+					continue
+				}
+				info, ok := summary[status]
+				if !ok {
+					info = &CosInfo{Name: status}
+					summary[status] = info
+				}
+				info.TotalFiles++
+				info.Size += object.Size
+				mu.Unlock()
+
+				// 记录未完成复制的对象
+				if fo.Operation.FailOutput && status != ReplicationStatusCompleted && status != ReplicationStatusReplica {
+					writeError(fmt.Sprintf("%s\t%s\n", status, object.Key), fo)
+				}
+			}
+		}()
+	}
+
+	err := walkCosObjects(c, cosUrl, fo, func(object cos.Object) {
+		chObjects <- object
+	})
+	close(chObjects)
+	wg.Wait()
+	CloseErrorOutputFile(fo)
+
+	if err != nil {
+		return err
+	}
+
+	renderReplicationStatusTable(summary, getCosUrl(bucketName, prefix))
+
+	if fo.ErrOutput.Path != "" {
+		absErrOutputPath, _ := filepath.Abs(fo.ErrOutput.Path)
+		if failedCnt > 0 {
+			logger.Warningf("%d objects head failed, please check the detailed information in dir %s.", failedCnt, absErrOutputPath)
+		} else {
+			logger.Infof("Objects not yet replicated are recorded in dir %s.", absErrOutputPath)
+		}
+	}
+	return nil
+}
+
+// SingleReplicationStatus 查询单个对象的跨地域复制状态
+func SingleReplicationStatus(c *cos.Client, cosUrl StorageUrl) error {
+	status, err := getReplicationStatus(c, cosUrl.(*CosUrl).Object)
+	if err != nil {
+		return err
+	}
+	logger.Infof("%s replication status: %s", cosUrl.ToString(), status)
+	return nil
+}
+
+func getReplicationStatus(c *cos.Client, object string) (string, error) {
+	resp, err := GetHead(c, object)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return "", fmt.Errorf("Object not found : %v", err)
+		}
+		return "", fmt.Errorf("Head object err : %v", err)
+	}
+	status := strings.ToUpper(resp.Header.Get("x-cos-replication-status"))
+	if status == "" {
+		status = ReplicationStatusNone
+	}
+	return status, nil
+}
+
+func renderReplicationStatusTable(summary map[string]*CosInfo, path string) {
+	statuses := make([]string, 0, len(summary))
+	for status := range summary {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	var totalCnt int
+	var totalSize int64
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Replication Status", "Objects Count", "Total Size"})
+	for _, status := range statuses {
+		info := summary[status]
+		table.Append([]string{status, fmt.Sprintf("%d", info.TotalFiles), FormatSize(info.Size)})
+		totalCnt += info.TotalFiles
+		totalSize += info.Size
+	}
+	table.SetFooter([]string{"Total", fmt.Sprintf("%d", totalCnt), FormatSize(totalSize)})
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetCaption(true, fmt.Sprintf("Replication status of %s", path))
+	table.SetBorders(tablewriter.Border{
+		Left:   false,
+		Right:  false,
+		Top:    false,
+		Bottom: true,
+	})
+	table.Render()
+}
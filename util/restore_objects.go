@@ -1,47 +1,119 @@
 package util
 
 import (
-	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	logger "github.com/sirupsen/logrus"
+	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/tencentyun/cos-go-sdk-v5"
 	"math/rand"
+	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-var succeedNum, failedNum, errTypeNum int
+// restoreCounter 回热任务统计
+type restoreCounter struct {
+	succeed int64
+	failed  int64
+	errType int64
+	skip    int64
+}
 
 // RestoreObjects 取回cos对象
 func RestoreObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, bucketType string) error {
-	logger.Infof("Start Restore %s", cosUrl.(*CosUrl).Bucket+cosUrl.(*CosUrl).Object)
-	var err error
-	if bucketType == BucketTypeOfs {
-		bucketName := cosUrl.(*CosUrl).Bucket
-		prefix := cosUrl.(*CosUrl).Object
-		err = restoreOfsObjects(c, bucketName, prefix, fo, "")
-	} else {
-		err = restoreCosObjects(c, cosUrl, fo)
+	bucketName := cosUrl.(*CosUrl).Bucket
+	logger.Infof("Start Restore %s", bucketName+cosUrl.(*CosUrl).Object)
+
+	// 初始化回热进度db，用于断点续回热
+	err := initRestoreProgressDb(fo)
+	if err != nil {
+		return err
 	}
+	defer closeRestoreProgressDb(fo)
+
+	counter := &restoreCounter{}
+	chObjects := make(chan string, ChannelSize)
+	var wg sync.WaitGroup
+	for i := 0; i < fo.Operation.Routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			restoreObjectsWorker(c, bucketName, fo, chObjects, counter)
+		}()
+	}
+
+	err = listRestoreObjects(c, cosUrl, fo, bucketType, func(object cos.Object) {
+		if !isRestoreType(object) {
+			atomic.AddInt64(&counter.errType, 1)
+			return
+		}
+		if object.RestoreStatus == "ONGOING" || object.RestoreStatus == "ONGING" {
+			atomic.AddInt64(&counter.succeed, 1)
+			putRestoreProgress(fo, bucketName, object.Key)
+			return
+		}
+		// 已提交过回热的对象直接跳过
+		if hasRestoreProgress(fo, bucketName, object.Key) {
+			atomic.AddInt64(&counter.skip, 1)
+			return
+		}
+		chObjects <- object.Key
+	})
+	close(chObjects)
+	wg.Wait()
 
 	if err != nil {
 		return err
 	}
 
 	absErrOutputPath, _ := filepath.Abs(fo.ErrOutput.Path)
+	total := counter.succeed + counter.failed + counter.errType + counter.skip
 
-	if failedNum > 0 {
-		logger.Warningf("Restore %s completed, total num: %d,success num: %d,restore error num: %d,error type num: %d,Some objects restore failed, please check the detailed information in dir %s.\n", cosUrl.(*CosUrl).Bucket+cosUrl.(*CosUrl).Object, succeedNum+failedNum+errTypeNum, succeedNum, failedNum, errTypeNum, absErrOutputPath)
+	if counter.failed > 0 {
+		logger.Warningf("Restore %s completed, total num: %d,success num: %d,skip num: %d,restore error num: %d,error type num: %d,Some objects restore failed, please check the detailed information in dir %s.\n", bucketName+cosUrl.(*CosUrl).Object, total, counter.succeed, counter.skip, counter.failed, counter.errType, absErrOutputPath)
 	} else {
-		logger.Infof("Restore %s completed,total num: %d,success num: %d,restore error num: %d,error type num: %d", cosUrl.(*CosUrl).Bucket+cosUrl.(*CosUrl).Object, succeedNum+failedNum+errTypeNum, succeedNum, failedNum, errTypeNum)
+		logger.Infof("Restore %s completed,total num: %d,success num: %d,skip num: %d,restore error num: %d,error type num: %d", bucketName+cosUrl.(*CosUrl).Object, total, counter.succeed, counter.skip, counter.failed, counter.errType)
 	}
 
 	return nil
 }
 
-func restoreCosObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+func restoreObjectsWorker(c *cos.Client, bucketName string, fo *FileOperations, chObjects <-chan string, counter *restoreCounter) {
+	for key := range chObjects {
+		// 中断后不再处理新的对象
+		if fo.Interrupted() {
+			continue
+		}
+		resp, err := TryRestoreObject(c, bucketName, key, fo.Operation.Days, fo.Operation.RestoreMode, fo)
+		if err != nil && (resp == nil || resp.StatusCode != 409) {
+			atomic.AddInt64(&counter.failed, 1)
+			writeError(fmt.Sprintf("restore %s failed , errMsg:%v\n", key, err), fo)
+			continue
+		}
+		atomic.AddInt64(&counter.succeed, 1)
+		putRestoreProgress(fo, bucketName, key)
+	}
+}
+
+// listRestoreObjects 遍历前缀下符合筛选条件的对象
+func listRestoreObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, bucketType string, handle func(object cos.Object)) error {
+	if fo.Operation.FromInventory != "" {
+		return walkInventoryObjects(cosUrl, fo, handle)
+	}
+	if bucketType == BucketTypeOfs {
+		return listRestoreOfsObjects(c, cosUrl.(*CosUrl).Object, fo, "", handle)
+	}
+	return listRestoreCosObjects(c, cosUrl, fo, handle)
+}
+
+func listRestoreCosObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, handle func(object cos.Object)) error {
 	var err error
 	var objects []cos.Object
 	marker := ""
@@ -54,30 +126,44 @@ func restoreCosObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) err
 		}
 
 		for _, object := range objects {
-			if isRestoreType(object) {
-				object.Key, _ = url.QueryUnescape(object.Key)
-				if cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
-					if object.RestoreStatus == "ONGOING" || object.RestoreStatus == "ONGING" {
-						succeedNum += 1
-					} else {
-						resp, err := TryRestoreObject(c, cosUrl.(*CosUrl).Bucket, object.Key, fo.Operation.Days, fo.Operation.RestoreMode)
-						if err != nil {
-							if resp != nil && resp.StatusCode == 409 {
-								succeedNum += 1
-							} else {
-								failedNum += 1
-								writeError(fmt.Sprintf("restore %s failed , errMsg:%v\n", object.Key, err), fo)
-							}
-						} else {
-							succeedNum += 1
-						}
-					}
+			object.Key, _ = url.QueryUnescape(object.Key)
+			if cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
+				handle(object)
+			}
+		}
+	}
 
-				}
-			} else {
-				errTypeNum += 1
+	return nil
+}
+
+func listRestoreOfsObjects(c *cos.Client, prefix string, fo *FileOperations, marker string, handle func(object cos.Object)) error {
+	var err error
+	var objects []cos.Object
+	var commonPrefixes []string
+	isTruncated := true
+
+	for isTruncated {
+		err, objects, commonPrefixes, isTruncated, marker = getOfsObjectListForLs(c, prefix, marker, 0, true)
+		if err != nil {
+			return fmt.Errorf("list objects error : %v", err)
+		}
+
+		for _, object := range objects {
+			object.Key, _ = url.QueryUnescape(object.Key)
+			if cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
+				handle(object)
 			}
+		}
 
+		if len(commonPrefixes) > 0 {
+			for _, commonPrefix := range commonPrefixes {
+				commonPrefix, _ = url.QueryUnescape(commonPrefix)
+				// 递归目录
+				err = listRestoreOfsObjects(c, commonPrefix, fo, "", handle)
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -85,7 +171,7 @@ func restoreCosObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) err
 }
 
 // TryRestoreObject 重试回热对象
-func TryRestoreObject(c *cos.Client, bucketName, objectKey string, days int, mode string) (resp *cos.Response, err error) {
+func TryRestoreObject(c *cos.Client, bucketName, objectKey string, days int, mode string, fo *FileOperations) (resp *cos.Response, err error) {
 
 	logger.Infof("Restore cos://%s/%s\n", bucketName, objectKey)
 	opt := &cos.ObjectRestoreOptions{
@@ -96,7 +182,7 @@ func TryRestoreObject(c *cos.Client, bucketName, objectKey string, days int, mod
 	}
 
 	for i := 0; i <= 10; i++ {
-		resp, err = c.Object.PostRestore(context.Background(), objectKey, opt)
+		resp, err = c.Object.PostRestore(fo.Context(), objectKey, opt)
 		if err != nil {
 			if resp != nil && resp.StatusCode == 503 {
 				if i == 10 {
@@ -104,7 +190,11 @@ func TryRestoreObject(c *cos.Client, bucketName, objectKey string, days int, mod
 				} else {
 					fmt.Println("Error 503: Service rate limiting. Retrying...")
 					waitTime := time.Duration(rand.Intn(10)+1) * time.Second
-					time.Sleep(waitTime)
+					select {
+					case <-fo.Context().Done():
+						return resp, ErrInterrupted
+					case <-time.After(waitTime):
+					}
 					continue
 				}
 			} else {
@@ -117,59 +207,177 @@ func TryRestoreObject(c *cos.Client, bucketName, objectKey string, days int, mod
 	return resp, err
 }
 
-func restoreOfsObjects(c *cos.Client, bucketName, prefix string, fo *FileOperations, marker string) error {
-	var err error
-	var objects []cos.Object
-	var commonPrefixes []string
-	isTruncated := true
+// WaitRestoreObjects 轮询前缀下对象的回热状态，直至所有对象可读
+func WaitRestoreObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, bucketType string) error {
+	path := cosUrl.(*CosUrl).Bucket + cosUrl.(*CosUrl).Object
+	ready := make(map[string]bool)
+	failed := make(map[string]bool)
+	startT := time.Now()
 
-	for isTruncated {
-		err, objects, commonPrefixes, isTruncated, marker = getOfsObjectListForLs(c, prefix, marker, 0, true)
+	for {
+		var pending []string
+		err := listRestoreObjects(c, cosUrl, fo, bucketType, func(object cos.Object) {
+			if isRestoreType(object) && !ready[object.Key] && !failed[object.Key] {
+				pending = append(pending, object.Key)
+			}
+		})
 		if err != nil {
-			return fmt.Errorf("list objects error : %v", err)
+			return err
 		}
 
-		for _, object := range objects {
-			if isRestoreType(object) {
-				object.Key, _ = url.QueryUnescape(object.Key)
-				if cosObjectMatchPatterns(object.Key, fo.Operation.Filters) {
-					if object.RestoreStatus == "ONGOING" || object.RestoreStatus == "ONGING" {
-						succeedNum += 1
-					} else {
-						resp, err := TryRestoreObject(c, bucketName, object.Key, fo.Operation.Days, fo.Operation.RestoreMode)
-						if err != nil {
-							if resp != nil && resp.StatusCode == 409 {
-								succeedNum += 1
-							} else {
-								failedNum += 1
-								writeError(fmt.Sprintf("restore %s failed , errMsg:%v\n", object.Key, err), fo)
-							}
-						} else {
-							succeedNum += 1
-						}
-					}
+		readyKeys, failedKeys, ongoing, notRestored := checkRestoreStates(c, pending, fo)
+		for _, key := range readyKeys {
+			ready[key] = true
+		}
+		for _, key := range failedKeys {
+			failed[key] = true
+		}
 
-				}
-			} else {
-				errTypeNum += 1
+		logger.Infof("Wait restore %s, ready num: %d, ongoing num: %d, not restored num: %d, failed num: %d",
+			path, len(ready), ongoing, notRestored, len(failed))
+		if ongoing == 0 {
+			if notRestored > 0 || len(failed) > 0 {
+				return fmt.Errorf("wait restore %s completed, %d objects are not restored, %d objects failed to get restore status",
+					path, notRestored, len(failed))
 			}
+			return nil
 		}
 
-		if len(commonPrefixes) > 0 {
-			for _, commonPrefix := range commonPrefixes {
-				commonPrefix, _ = url.QueryUnescape(commonPrefix)
-				// 递归目录
-				err = restoreOfsObjects(c, bucketName, commonPrefix, fo, "")
+		if err = waitRestoreInterval(startT, fo); err != nil {
+			return err
+		}
+	}
+}
+
+// WaitRestoreObject 轮询单个对象的回热状态，直至对象可读
+func WaitRestoreObject(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	key := cosUrl.(*CosUrl).Object
+	startT := time.Now()
+
+	for {
+		readyKeys, failedKeys, ongoing, _ := checkRestoreStates(c, []string{key}, fo)
+		if len(readyKeys) > 0 {
+			logger.Infof("Object %s is restored and readable", cosUrl.(*CosUrl).Bucket+key)
+			return nil
+		}
+		if len(failedKeys) > 0 {
+			return fmt.Errorf("get restore status of %s failed", cosUrl.(*CosUrl).Bucket+key)
+		}
+		if ongoing == 0 {
+			return fmt.Errorf("object %s is not restored", cosUrl.(*CosUrl).Bucket+key)
+		}
+
+		logger.Infof("Wait restore %s, restore is ongoing", cosUrl.(*CosUrl).Bucket+key)
+		if err := waitRestoreInterval(startT, fo); err != nil {
+			return err
+		}
+	}
+}
+
+func waitRestoreInterval(startT time.Time, fo *FileOperations) error {
+	if fo.Operation.WaitTimeout > 0 && time.Since(startT)+fo.Operation.WaitInterval > fo.Operation.WaitTimeout {
+		return fmt.Errorf("wait restore timeout after %v", fo.Operation.WaitTimeout)
+	}
+	select {
+	case <-fo.Context().Done():
+		return ErrInterrupted
+	case <-time.After(fo.Operation.WaitInterval):
+	}
+	return nil
+}
+
+// checkRestoreStates 并发查询对象的回热状态，查询失败且不可重试的对象计入failedKeys
+func checkRestoreStates(c *cos.Client, keys []string, fo *FileOperations) (readyKeys, failedKeys []string, ongoing, notRestored int) {
+	var mu sync.Mutex
+	chKeys := make(chan string, len(keys))
+	for _, key := range keys {
+		chKeys <- key
+	}
+	close(chKeys)
+
+	var wg sync.WaitGroup
+	for i := 0; i < fo.Operation.Routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range chKeys {
+				resp, err := GetHead(c, key)
+				mu.Lock()
 				if err != nil {
-					return err
+					if isRetryableRestoreError(err) {
+						// 服务端或网络错误视为仍在回热中，下一轮重试
+						ongoing++
+					} else {
+						logger.Warningf("Get restore status of %s failed: %v", key, err)
+						failedKeys = append(failedKeys, key)
+					}
+					mu.Unlock()
+					continue
 				}
+				restore := resp.Header.Get("x-cos-restore")
+				if restore == "" {
+					notRestored++
+				} else if strings.Contains(restore, "ongoing-request=\"true\"") {
+					ongoing++
+				} else {
+					readyKeys = append(readyKeys, key)
+				}
+				mu.Unlock()
 			}
-		}
+		}()
+	}
+	wg.Wait()
+	return
+}
+
+// isRetryableRestoreError 查询回热状态的错误是否可重试，仅服务端错误、限频及网络错误重试
+func isRetryableRestoreError(err error) bool {
+	var errResp *cos.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode >= http.StatusInternalServerError || errResp.Response.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// initRestoreProgressDb 初始化回热进度db
+func initRestoreProgressDb(fo *FileOperations) error {
+	if fo.Operation.ProgressPath == "" {
+		return nil
 	}
 
+	var err error
+	if fo.ProgressDb, err = leveldb.OpenFile(fo.Operation.ProgressPath, nil); err != nil {
+		return fmt.Errorf("Restore load progress error, reason: " + err.Error())
+	}
 	return nil
 }
 
+func closeRestoreProgressDb(fo *FileOperations) {
+	if fo.ProgressDb != nil {
+		fo.ProgressDb.Close()
+		fo.ProgressDb = nil
+	}
+}
+
+func getRestoreProgressKey(bucketName, key string) []byte {
+	return []byte(bucketName + CosSeparator + key)
+}
+
+func hasRestoreProgress(fo *FileOperations, bucketName, key string) bool {
+	if fo.ProgressDb == nil {
+		return false
+	}
+	exist, _ := fo.ProgressDb.Has(getRestoreProgressKey(bucketName, key), nil)
+	return exist
+}
+
+func putRestoreProgress(fo *FileOperations, bucketName, key string) {
+	if fo.ProgressDb == nil {
+		return
+	}
+	fo.ProgressDb.Put(getRestoreProgressKey(bucketName, key), []byte(strconv.FormatInt(time.Now().Unix(), 10)), nil)
+}
+
 // 判断是否是需要回热的文件类型
 func isRestoreType(object cos.Object) bool {
 	if object.StorageClass == Archive || object.StorageClass == MAZArchive || object.StorageClass == DeepArchive {
@@ -0,0 +1,173 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// SelectOptions 对象检索的SQL及输入输出格式，未指定的选项使用COS默认值
+type SelectOptions struct {
+	Expression string
+	// InputFormat 为csv或json，Compression 为none、gzip或bzip2，为空时按对象后缀判断
+	InputFormat        string
+	Compression        string
+	CSVHeader          string
+	CSVDelimiter       string
+	CSVRecordDelimiter string
+	CSVQuote           string
+	CSVComments        string
+	JSONType           string
+	// OutputFormat 为空时与输入格式相同
+	OutputFormat          string
+	OutputDelimiter       string
+	OutputRecordDelimiter string
+	QuoteFields           string
+}
+
+// SelectObject 使用SQL检索CSV或JSON对象，将返回的记录输出到标准输出，结束后在标准错误输出扫描及返回的字节数
+func SelectObject(c *cos.Client, cosUrl StorageUrl, opt SelectOptions, fo *FileOperations) error {
+	object := cosUrl.(*CosUrl).Object
+	selectOpt, err := newObjectSelectOptions(object, opt)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.Object.Select(fo.Context(), object, selectOpt)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+	if _, err = io.Copy(os.Stdout, resp); err != nil {
+		if fo.Interrupted() {
+			return ErrInterrupted
+		}
+		return fmt.Errorf("select %s error: %v", cosUrl.ToString(), err)
+	}
+
+	stats := resp.(*cos.ObjectSelectResponse).Frame.StatsFrame
+	fmt.Fprintf(os.Stderr, "Bytes scanned: %s, processed: %s, returned: %s\n",
+		FormatSize(int64(stats.BytesScanned)), FormatSize(int64(stats.BytesProcessed)), FormatSize(int64(stats.BytesReturned)))
+	return nil
+}
+
+// newObjectSelectOptions 校验选项并生成检索请求
+func newObjectSelectOptions(object string, opt SelectOptions) (*cos.ObjectSelectOptions, error) {
+	if opt.Expression == "" {
+		return nil, fmt.Errorf("sql expression can not be empty")
+	}
+
+	// 按后缀判断压缩及输入格式，如data.csv.gz、data.jsonl
+	name := strings.ToLower(object)
+	compression := opt.Compression
+	switch {
+	case strings.HasSuffix(name, ".gz"):
+		name = strings.TrimSuffix(name, ".gz")
+		if compression == "" {
+			compression = "gzip"
+		}
+	case strings.HasSuffix(name, ".bz2"):
+		name = strings.TrimSuffix(name, ".bz2")
+		if compression == "" {
+			compression = "bzip2"
+		}
+	}
+	inputFormat := opt.InputFormat
+	if inputFormat == "" {
+		inputFormat = "csv"
+		if strings.HasSuffix(name, ".json") || strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".ndjson") {
+			inputFormat = "json"
+		}
+	}
+
+	input := &cos.SelectInputSerialization{}
+	switch compression {
+	case "", "none":
+		input.CompressionType = "NONE"
+	case "gzip", "bzip2":
+		input.CompressionType = strings.ToUpper(compression)
+	default:
+		return nil, fmt.Errorf("invalid compression %s, optional values: none, gzip and bzip2", compression)
+	}
+	switch inputFormat {
+	case "csv":
+		if opt.JSONType != "" {
+			return nil, fmt.Errorf("--json-type only work with json input")
+		}
+		input.CSV = &cos.CSVInputSerialization{
+			FieldDelimiter:  unescapeDelimiter(opt.CSVDelimiter),
+			RecordDelimiter: unescapeDelimiter(opt.CSVRecordDelimiter),
+			QuoteCharacter:  opt.CSVQuote,
+			Comments:        opt.CSVComments,
+		}
+		switch opt.CSVHeader {
+		case "":
+		case "none", "use", "ignore":
+			input.CSV.FileHeaderInfo = strings.ToUpper(opt.CSVHeader)
+		default:
+			return nil, fmt.Errorf("invalid csv header %s, optional values: none, use and ignore", opt.CSVHeader)
+		}
+	case "json":
+		if opt.CSVHeader != "" || opt.CSVDelimiter != "" || opt.CSVRecordDelimiter != "" || opt.CSVQuote != "" || opt.CSVComments != "" {
+			return nil, fmt.Errorf("--csv-* options only work with csv input")
+		}
+		input.JSON = &cos.JSONInputSerialization{Type: "LINES"}
+		switch opt.JSONType {
+		case "", "lines":
+		case "document":
+			input.JSON.Type = "DOCUMENT"
+		default:
+			return nil, fmt.Errorf("invalid json type %s, optional values: lines and document", opt.JSONType)
+		}
+	default:
+		return nil, fmt.Errorf("invalid input format %s, optional values: csv and json", inputFormat)
+	}
+
+	outputFormat := opt.OutputFormat
+	if outputFormat == "" {
+		outputFormat = inputFormat
+	}
+	output := &cos.SelectOutputSerialization{}
+	switch outputFormat {
+	case "csv":
+		output.CSV = &cos.CSVOutputSerialization{
+			FieldDelimiter:  unescapeDelimiter(opt.OutputDelimiter),
+			RecordDelimiter: unescapeDelimiter(opt.OutputRecordDelimiter),
+		}
+		switch opt.QuoteFields {
+		case "":
+		case "always", "asneeded":
+			output.CSV.QuoteFields = strings.ToUpper(opt.QuoteFields)
+		default:
+			return nil, fmt.Errorf("invalid quote fields %s, optional values: always and asneeded", opt.QuoteFields)
+		}
+	case "json":
+		if opt.OutputDelimiter != "" || opt.QuoteFields != "" {
+			return nil, fmt.Errorf("--output-delimiter and --quote-fields only work with csv output")
+		}
+		output.JSON = &cos.JSONOutputSerialization{RecordDelimiter: unescapeDelimiter(opt.OutputRecordDelimiter)}
+	default:
+		return nil, fmt.Errorf("invalid output format %s, optional values: csv and json", outputFormat)
+	}
+
+	// 开启进度事件，避免长时间扫描无匹配记录时读取响应超时
+	return &cos.ObjectSelectOptions{
+		Expression:          opt.Expression,
+		ExpressionType:      "SQL",
+		InputSerialization:  input,
+		OutputSerialization: output,
+		RequestProgress:     "TRUE",
+	}, nil
+}
+
+// unescapeDelimiter 解析命令行中的转义分隔符，如\t、\n
+func unescapeDelimiter(s string) string {
+	if v, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return v
+	}
+	return s
+}
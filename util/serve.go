@@ -0,0 +1,319 @@
+package util
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// ServeOptions 本地HTTP网关参数
+type ServeOptions struct {
+	Listen   string
+	Writable bool
+	User     string
+	Password string
+	Token    string
+}
+
+// 透传给客户端的对象响应头
+var serveObjectHeaders = []string{
+	"Accept-Ranges", "Cache-Control", "Content-Disposition", "Content-Encoding", "Content-Language",
+	"Content-Length", "Content-Range", "Content-Type", "ETag", "Expires", "Last-Modified",
+}
+
+var serveListTemplate = template.Must(template.New("list").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<tr><th align="left">Name</th><th align="left">Last Modified</th><th align="right">Size</th></tr>
+{{if ne .Path "/"}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.Href}}">{{.Name}}</a></td><td>{{.LastModified}}</td><td align="right">{{.Size}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type serveListEntry struct {
+	Name         string
+	Href         string
+	LastModified string
+	Size         string
+}
+
+// ServeHandler 将存储桶前缀映射为本地HTTP服务
+type ServeHandler struct {
+	Client  *cos.Client
+	Prefix  string
+	Options ServeOptions
+}
+
+// NewServeHandler 创建HTTP网关处理器，prefix为空或以/结尾
+func NewServeHandler(c *cos.Client, prefix string, opt ServeOptions) *ServeHandler {
+	return &ServeHandler{Client: c, Prefix: prefix, Options: opt}
+}
+
+// Serve 启动本地HTTP网关，ctx结束时停止接收新请求，等待处理中的请求完成后返回
+func Serve(ctx context.Context, c *cos.Client, prefix string, opt ServeOptions) error {
+	server := &http.Server{
+		Addr:    opt.Listen,
+		Handler: NewServeHandler(c, prefix, opt),
+	}
+	if opt.User == "" && opt.Token == "" {
+		logger.Warningf("Serving without authentication, anyone who can reach %s can access the bucket", opt.Listen)
+	}
+	logger.Infof("Serving %s on %s, writable: %v", c.BaseURL.BucketURL.String()+"/"+prefix, opt.Listen, opt.Writable)
+
+	chErr := make(chan error, 1)
+	go func() {
+		chErr <- server.ListenAndServe()
+	}()
+	select {
+	case err := <-chErr:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Infof("Shutting down the server on %s", opt.Listen)
+	return server.Shutdown(context.Background())
+}
+
+func (h *ServeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		if h.Options.User != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="coscli"`)
+		}
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	// 清理路径，防止越过前缀访问
+	urlPath := path.Clean("/" + r.URL.Path)
+	if strings.HasSuffix(r.URL.Path, "/") && urlPath != "/" {
+		urlPath += "/"
+	}
+	key := h.Prefix + strings.TrimPrefix(urlPath, "/")
+
+	var status int
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if strings.HasSuffix(urlPath, "/") {
+			status = h.list(w, r, urlPath, key)
+		} else {
+			status = h.get(w, r, urlPath, key)
+		}
+	case http.MethodPut, http.MethodDelete:
+		if !h.Options.Writable {
+			status = serveError(w, http.StatusMethodNotAllowed, "serve is read-only, use --writable to enable PUT and DELETE")
+		} else if strings.HasSuffix(urlPath, "/") {
+			status = serveError(w, http.StatusBadRequest, "can not put or delete a directory")
+		} else if r.Method == http.MethodPut {
+			status = h.put(w, r, key)
+		} else {
+			status = h.delete(w, r, key)
+		}
+	default:
+		status = serveError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+	}
+
+	logger.Infof("%s %s %s %d", r.RemoteAddr, r.Method, r.URL.Path, status)
+}
+
+// authorized 校验basic-auth或token，均未配置时不校验
+func (h *ServeHandler) authorized(r *http.Request) bool {
+	if h.Options.User == "" && h.Options.Token == "" {
+		return true
+	}
+	if h.Options.Token != "" {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			token = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.Options.Token)) == 1 {
+			return true
+		}
+	}
+	if h.Options.User != "" {
+		user, password, ok := r.BasicAuth()
+		if ok && subtle.ConstantTimeCompare([]byte(user), []byte(h.Options.User)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(password), []byte(h.Options.Password)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *ServeHandler) get(w http.ResponseWriter, r *http.Request, urlPath, key string) int {
+	optHeader := &http.Header{}
+	for _, name := range []string{"If-Match", "If-None-Match", "If-Unmodified-Since"} {
+		if v := r.Header.Get(name); v != "" {
+			optHeader.Set(name, v)
+		}
+	}
+
+	var resp *cos.Response
+	var err error
+	if r.Method == http.MethodHead {
+		resp, err = h.Client.Object.Head(r.Context(), key, &cos.ObjectHeadOptions{
+			IfModifiedSince: r.Header.Get("If-Modified-Since"),
+			XOptionHeader:   optHeader,
+		})
+	} else {
+		resp, err = h.Client.Object.Get(r.Context(), key, &cos.ObjectGetOptions{
+			Range:           r.Header.Get("Range"),
+			IfModifiedSince: r.Header.Get("If-Modified-Since"),
+			XOptionHeader:   optHeader,
+		})
+	}
+	if err != nil {
+		// 对象不存在但存在同名目录时，跳转到目录
+		if cos.IsNotFoundError(err) && h.isDir(r.Context(), key) {
+			http.Redirect(w, r, path.Base(urlPath)+"/", http.StatusMovedPermanently)
+			return http.StatusMovedPermanently
+		}
+		return serveCosError(w, err)
+	}
+	defer resp.Body.Close()
+
+	for _, name := range serveObjectHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			w.Header().Set(name, v)
+		}
+	}
+	w.Header().Set("Accept-Ranges", "bytes")
+	w.WriteHeader(resp.StatusCode)
+	if r.Method == http.MethodGet {
+		_, _ = io.Copy(w, resp.Body)
+	}
+	return resp.StatusCode
+}
+
+// isDir 判断前缀下是否存在对象
+func (h *ServeHandler) isDir(ctx context.Context, key string) bool {
+	res, err := h.listPrefix(ctx, key+CosSeparator, "", 1)
+	return err == nil && len(res.Contents)+len(res.CommonPrefixes) > 0
+}
+
+// listPrefix 列出前缀下一级的对象及目录，请求失败时不重试，直接返回给客户端
+func (h *ServeHandler) listPrefix(ctx context.Context, prefix, marker string, limit int) (*cos.BucketGetResult, error) {
+	res, _, err := h.Client.Bucket.Get(ctx, &cos.BucketGetOptions{
+		Prefix:       prefix,
+		Delimiter:    CosSeparator,
+		EncodingType: "url",
+		Marker:       marker,
+		MaxKeys:      limit,
+	})
+	return res, err
+}
+
+func (h *ServeHandler) list(w http.ResponseWriter, r *http.Request, urlPath, prefix string) int {
+	var dirs, files []serveListEntry
+	marker := ""
+	isTruncated := true
+
+	for isTruncated {
+		res, err := h.listPrefix(r.Context(), prefix, marker, 0)
+		if err != nil {
+			return serveCosError(w, err)
+		}
+		marker, _ = url.QueryUnescape(res.NextMarker)
+		isTruncated = res.IsTruncated && marker != ""
+		for _, commonPrefix := range res.CommonPrefixes {
+			commonPrefix, _ = url.QueryUnescape(commonPrefix)
+			name := strings.TrimPrefix(commonPrefix, prefix)
+			dirs = append(dirs, serveListEntry{
+				Name: name,
+				Href: url.PathEscape(strings.TrimSuffix(name, CosSeparator)) + CosSeparator,
+			})
+		}
+		for _, object := range res.Contents {
+			object.Key, _ = url.QueryUnescape(object.Key)
+			name := strings.TrimPrefix(object.Key, prefix)
+			if name == "" {
+				continue
+			}
+			files = append(files, serveListEntry{
+				Name:         name,
+				Href:         url.PathEscape(name),
+				LastModified: object.LastModified,
+				Size:         strings.TrimSpace(FormatSize(object.Size)),
+			})
+		}
+	}
+
+	// 前缀下没有任何内容时视为不存在，根目录除外
+	if urlPath != "/" && len(dirs)+len(files) == 0 {
+		return serveError(w, http.StatusNotFound, http.StatusText(http.StatusNotFound))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_ = serveListTemplate.Execute(w, struct {
+			Path    string
+			Entries []serveListEntry
+		}{urlPath, append(dirs, files...)})
+	}
+	return http.StatusOK
+}
+
+func (h *ServeHandler) put(w http.ResponseWriter, r *http.Request, key string) int {
+	if r.ContentLength < 0 {
+		return serveError(w, http.StatusLengthRequired, http.StatusText(http.StatusLengthRequired))
+	}
+	opt := &cos.ObjectPutOptions{
+		ObjectPutHeaderOptions: &cos.ObjectPutHeaderOptions{
+			ContentType:   r.Header.Get("Content-Type"),
+			ContentLength: r.ContentLength,
+		},
+	}
+	// 使用LimitedReader以便SDK获取长度并校验crc64
+	body := &io.LimitedReader{R: r.Body, N: r.ContentLength}
+	resp, err := h.Client.Object.Put(r.Context(), key, body, opt)
+	if err != nil {
+		return serveCosError(w, err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return http.StatusCreated
+}
+
+func (h *ServeHandler) delete(w http.ResponseWriter, r *http.Request, key string) int {
+	_, err := h.Client.Object.Delete(r.Context(), key)
+	if err != nil {
+		return serveCosError(w, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return http.StatusNoContent
+}
+
+// serveCosError 将cos错误转换为对应的HTTP状态码
+func serveCosError(w http.ResponseWriter, err error) int {
+	var errResp *cos.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		status := errResp.Response.StatusCode
+		if status == http.StatusNotModified {
+			w.WriteHeader(status)
+			return status
+		}
+		return serveError(w, status, fmt.Sprintf("%s: %s", errResp.Code, errResp.Message))
+	}
+	return serveError(w, http.StatusBadGateway, err.Error())
+}
+
+func serveError(w http.ResponseWriter, status int, message string) int {
+	http.Error(w, message, status)
+	return status
+}
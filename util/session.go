@@ -0,0 +1,81 @@
+package util
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// sessionCache 交互式shell中复用的客户端及桶类型，为空表示未开启
+var sessionCache *clientCache
+
+// 临时密钥过期前提前该时间重新获取，避免请求过程中密钥过期
+const sessionCredentialMargin = 5 * time.Minute
+
+type clientCache struct {
+	lock        sync.Mutex
+	clients     map[string]cachedClient
+	bucketTypes map[string]string
+}
+
+type cachedClient struct {
+	client *cos.Client
+	expire time.Time
+}
+
+// EnableSessionCache 开启会话缓存，同一会话中相同存储桶复用客户端及桶类型
+func EnableSessionCache() {
+	sessionCache = &clientCache{
+		clients:     make(map[string]cachedClient),
+		bucketTypes: make(map[string]string),
+	}
+}
+
+// clientKey 客户端的连接池及重试配置取决于传输选项，选项不同时不复用
+func (cc *clientCache) clientKey(param *Param, bucketName string, options ...*FileOperations) string {
+	key := fmt.Sprintf("%s|%+v", bucketName, *param)
+	if len(options) > 0 && options[0] != nil {
+		op := options[0].Operation
+		key += fmt.Sprintf("|%t|%d|%d|%d|%d", op.DisableLongLinks, op.LongLinksNums, op.Routines, op.ErrRetryNum, op.ErrRetryInterval)
+	}
+	return key
+}
+
+func (cc *clientCache) getClient(key string) *cos.Client {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cached, ok := cc.clients[key]
+	if !ok {
+		return nil
+	}
+	if !cached.expire.IsZero() && time.Now().After(cached.expire) {
+		delete(cc.clients, key)
+		return nil
+	}
+	return cached.client
+}
+
+func (cc *clientCache) putClient(key string, client *cos.Client, expire time.Time) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.clients[key] = cachedClient{client: client, expire: expire}
+}
+
+// lookupBucketType 查询缓存的桶类型，未开启缓存时返回false
+func (cc *clientCache) lookupBucketType(bucketName string) (string, bool) {
+	if cc == nil {
+		return "", false
+	}
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	bucketType, ok := cc.bucketTypes[bucketName]
+	return bucketType, ok
+}
+
+func (cc *clientCache) putBucketType(bucketName, bucketType string) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.bucketTypes[bucketName] = bucketType
+}
@@ -0,0 +1,127 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// SignURLOptions 签名url参数，Query及Header会一并签入url
+type SignURLOptions struct {
+	Method string
+	Expire time.Duration
+	Query  url.Values
+	Header http.Header
+}
+
+// SignedURL 对象及其签名url
+type SignedURL struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// SignURL 生成对象的签名url
+func SignURL(c *cos.Client, key string, opt SignURLOptions) (*url.URL, error) {
+	query := url.Values{}
+	for k, v := range opt.Query {
+		query[k] = v
+	}
+	header := http.Header{}
+	for k, v := range opt.Header {
+		header[k] = v
+	}
+
+	presignedOpt := &cos.PresignedURLOptions{
+		Query:  &query,
+		Header: &header,
+	}
+	return c.Object.GetPresignedURL2(context.Background(), opt.Method, key, opt.Expire, presignedOpt)
+}
+
+// SignObjectURLs 为前缀下符合筛选条件的对象批量生成签名url，按csv或json格式输出
+func SignObjectURLs(c *cos.Client, cosUrl StorageUrl, bucketType string, filters []FilterOptionType, opt SignURLOptions, output string,
+	w io.Writer) error {
+	var write func(signed SignedURL) error
+	var finish func() error
+	count := 0
+
+	switch output {
+	case OutputFormatCsv:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write([]string{"key", "url"}); err != nil {
+			return err
+		}
+		write = func(signed SignedURL) error {
+			return csvWriter.Write([]string{signed.Key, signed.URL})
+		}
+		finish = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	case OutputFormatJson:
+		// 逐条输出json数组，避免对象较多时占用大量内存
+		if _, err := io.WriteString(w, "["); err != nil {
+			return err
+		}
+		write = func(signed SignedURL) error {
+			// url中的&等字符无需转义
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(signed); err != nil {
+				return err
+			}
+			sep := ",\n  "
+			if count == 0 {
+				sep = "\n  "
+			}
+			_, err := fmt.Fprintf(w, "%s%s", sep, bytes.TrimSpace(buf.Bytes()))
+			return err
+		}
+		finish = func() error {
+			_, err := io.WriteString(w, "\n]\n")
+			return err
+		}
+	default:
+		return fmt.Errorf("output '%s' is not supported, valid outputs are 'csv' and 'json'", output)
+	}
+
+	var signErr error
+	fo := &FileOperations{Operation: Operation{Filters: filters}}
+	handle := func(object cos.Object) {
+		if signErr != nil {
+			return
+		}
+		presignedURL, err := SignURL(c, object.Key, opt)
+		if err != nil {
+			signErr = fmt.Errorf("sign url of %s error : %v", object.Key, err)
+			return
+		}
+		if err = write(SignedURL{Key: object.Key, URL: presignedURL.String()}); err != nil {
+			signErr = err
+			return
+		}
+		count++
+	}
+	var err error
+	if bucketType == BucketTypeOfs {
+		err = walkOfsObjects(c, cosUrl.(*CosUrl).Object, fo, handle)
+	} else {
+		err = walkCosObjects(c, cosUrl, fo, handle)
+	}
+	if err != nil {
+		return err
+	}
+	if signErr != nil {
+		return signErr
+	}
+	return finish()
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +21,44 @@ func FormatSize(b int64) string {
 	}
 }
 
+// ParseSize 解析带单位的文件大小，如 100、512KB、1.5GB，不带单位时按字节计算
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	if str == "" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		size   float64
+	}{
+		{"TB", 1 << 40},
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"T", 1 << 40},
+		{"G", 1 << 30},
+		{"M", 1 << 20},
+		{"K", 1 << 10},
+		{"B", 1},
+	}
+
+	multiple := float64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(str, unit.suffix) {
+			multiple = unit.size
+			str = strings.TrimSpace(strings.TrimSuffix(str, unit.suffix))
+			break
+		}
+	}
+
+	num, err := strconv.ParseFloat(str, 64)
+	if err != nil || num < 0 {
+		return 0, fmt.Errorf("invalid size: %s", s)
+	}
+	return int64(num * multiple), nil
+}
+
 func formatBytes(bytes float64) string {
 	const (
 		KB = 1024
@@ -46,6 +46,29 @@ func DuObjects(c *cos.Client, cosUrl StorageUrl, filters []FilterOptionType, duT
 	return nil
 }
 
+// DuInventoryObjects 以清单报告作为数据源统计cos对象
+func DuInventoryObjects(source *InventorySource, cosUrl StorageUrl, filters []FilterOptionType, duType int) error {
+	err := source.Walk(cosUrl, func(object cos.Object) error {
+		if strings.HasSuffix(object.Key, "/") {
+			return nil
+		}
+		if cosObjectMatchPatterns(object.Key, filters) {
+			statisticObjects(object, duType)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if duType == DU_TYPE_CATEGORIZATION {
+		// 输出最终统计数据
+		printStatistic(false)
+	}
+
+	return nil
+}
+
 func countCosObjects(c *cos.Client, cosUrl StorageUrl, filters []FilterOptionType, duType int) error {
 	var err error
 	var objects []cos.Object
@@ -258,9 +281,9 @@ func printStatistic(allVersions bool) {
 
 // CosInfo cos文件信息
 type CosInfo struct {
-	Name       string
-	Size       int64
-	TotalFiles int
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	TotalFiles int    `json:"totalFiles"`
 }
 
 var dirs []CosInfo
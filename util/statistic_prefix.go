@@ -0,0 +1,181 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// DuPrefixOptions 按前缀统计参数
+type DuPrefixOptions struct {
+	Depth  int
+	Top    int
+	SortBy string
+	Output string
+}
+
+// DuPrefixInfo 前缀统计信息
+type DuPrefixInfo struct {
+	Prefix       string           `json:"prefix"`
+	Depth        int              `json:"depth"`
+	Size         int64            `json:"size"`
+	TotalFiles   int              `json:"totalFiles"`
+	StorageClass map[string]int64 `json:"storageClass"`
+	Children     []*DuPrefixInfo  `json:"children,omitempty"`
+	children     map[string]*DuPrefixInfo
+}
+
+func newDuPrefixInfo(prefix string, depth int) *DuPrefixInfo {
+	return &DuPrefixInfo{
+		Prefix:       prefix,
+		Depth:        depth,
+		StorageClass: make(map[string]int64),
+		children:     make(map[string]*DuPrefixInfo),
+	}
+}
+
+func (info *DuPrefixInfo) add(object cos.Object) {
+	info.Size += object.Size
+	info.TotalFiles++
+	if object.StorageClass != "" {
+		info.StorageClass[object.StorageClass] += object.Size
+	}
+}
+
+// DuPrefixObjects 按前缀层级统计cos对象
+func DuPrefixObjects(c *cos.Client, cosUrl StorageUrl, filters []FilterOptionType, bucketType string, opt DuPrefixOptions) error {
+	fo := &FileOperations{Operation: Operation{Filters: filters}}
+	return duPrefixes(cosUrl, opt, func(handle func(object cos.Object)) error {
+		if bucketType == BucketTypeOfs {
+			return walkOfsObjects(c, cosUrl.(*CosUrl).Object, fo, handle)
+		}
+		return walkCosObjects(c, cosUrl, fo, handle)
+	})
+}
+
+// DuInventoryPrefixes 以清单报告作为数据源按前缀层级统计cos对象
+func DuInventoryPrefixes(source *InventorySource, cosUrl StorageUrl, filters []FilterOptionType, opt DuPrefixOptions) error {
+	return duPrefixes(cosUrl, opt, func(handle func(object cos.Object)) error {
+		return source.Walk(cosUrl, func(object cos.Object) error {
+			if !strings.HasSuffix(object.Key, CosSeparator) && cosObjectMatchPatterns(object.Key, filters) {
+				handle(object)
+			}
+			return nil
+		})
+	})
+}
+
+func duPrefixes(cosUrl StorageUrl, opt DuPrefixOptions, walk func(handle func(object cos.Object)) error) error {
+	base := cosUrl.(*CosUrl).Object
+	root := newDuPrefixInfo(base, 0)
+
+	// 单次遍历，同时累加到各级前缀
+	err := walk(func(object cos.Object) {
+		root.add(object)
+		dirs := strings.Split(object.Key[len(base):], CosSeparator)
+		// 最后一段为文件名
+		dirs = dirs[:len(dirs)-1]
+		node := root
+		for i := 0; i < len(dirs) && i < opt.Depth; i++ {
+			prefix := node.Prefix + dirs[i] + CosSeparator
+			child, ok := node.children[prefix]
+			if !ok {
+				child = newDuPrefixInfo(prefix, i+1)
+				node.children[prefix] = child
+			}
+			child.add(object)
+			node = child
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	sortDuPrefixes(root, opt)
+
+	if opt.Output == OutputFormatJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(root)
+	}
+
+	renderDuPrefixTable(root)
+	return nil
+}
+
+// sortDuPrefixes 排序子前缀并保留前K个
+func sortDuPrefixes(info *DuPrefixInfo, opt DuPrefixOptions) {
+	children := make([]*DuPrefixInfo, 0, len(info.children))
+	for _, child := range info.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		switch opt.SortBy {
+		case DuSortByCount:
+			if children[i].TotalFiles != children[j].TotalFiles {
+				return children[i].TotalFiles > children[j].TotalFiles
+			}
+		case DuSortByName:
+		default:
+			if children[i].Size != children[j].Size {
+				return children[i].Size > children[j].Size
+			}
+		}
+		return children[i].Prefix < children[j].Prefix
+	})
+	if opt.Top > 0 && len(children) > opt.Top {
+		children = children[:opt.Top]
+	}
+
+	info.Children = children
+	for _, child := range children {
+		sortDuPrefixes(child, opt)
+	}
+}
+
+func renderDuPrefixTable(root *DuPrefixInfo) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Prefix", "Objects Count", "Total Size", "Storage Class"})
+
+	var appendRows func(info *DuPrefixInfo)
+	appendRows = func(info *DuPrefixInfo) {
+		prefix := info.Prefix
+		if prefix == "" {
+			prefix = CosSeparator
+		}
+		table.Append([]string{strings.Repeat("  ", info.Depth) + prefix, fmt.Sprintf("%d", info.TotalFiles), FormatSize(info.Size), formatDuStorageClass(info.StorageClass)})
+		for _, child := range info.Children {
+			appendRows(child)
+		}
+	}
+	appendRows(root)
+
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{
+		Left:   false,
+		Right:  false,
+		Top:    false,
+		Bottom: true,
+	})
+	table.Render()
+}
+
+func formatDuStorageClass(classes map[string]int64) string {
+	names := make([]string, 0, len(classes))
+	for name := range classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		items = append(items, fmt.Sprintf("%s:%s", name, strings.TrimSpace(FormatSize(classes[name]))))
+	}
+	return strings.Join(items, ", ")
+}
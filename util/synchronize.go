@@ -26,7 +26,8 @@ func SyncUpload(c *cos.Client, fileUrl StorageUrl, cosUrl StorageUrl, fo *FileOp
 			return fmt.Errorf("get delete keys error : %v", err)
 		}
 		UploadWithDelete(c, cosUrl, srcKeys, uploadKeys, fo)
-		if len(keysToDelete) > 0 {
+		// 中断后源列表可能不完整，不执行删除
+		if len(keysToDelete) > 0 && !fo.Interrupted() {
 			// 删除源位置没有而目标位置有的cos对象或本地文件
 			err = deleteKeys(c, keysToDelete, cosUrl, fo)
 		}
@@ -80,7 +81,7 @@ func skipUpload(snapshotKey string, c *cos.Client, fo *FileOperations, localFile
 					return true, SyncTypeUpdate, nil
 				}
 			} else {
-				cosCrc := resp.Header.Get("x-cos-hash-crc64ecma")
+				cosCrc := syncCrc64(resp.Header, fo, true)
 				localCrc, _, err := CalculateHash(localPath, "crc64")
 				if err != nil {
 					return false, SyncTypeCrc64, err
@@ -156,8 +157,8 @@ func skipDownload(snapshotKey string, c *cos.Client, fo *FileOperations, localPa
 				return false, SyncTypeCrc64, err
 			}
 		} else {
-			cosCrc := resp.Header.Get("x-cos-hash-crc64ecma")
-			if cosCrc == localCrc {
+			cosCrc := syncCrc64(resp.Header, fo, false)
+			if cosCrc != "" && cosCrc == localCrc {
 				// 本地校验通过后，添加快照记录
 				if fo.Operation.SnapshotPath != "" {
 					fo.SnapshotDb.Put([]byte(snapshotKey), []byte(strconv.FormatInt(objectModifiedTime.Unix(), 10)), nil)
@@ -273,6 +274,14 @@ func InitSnapshotDb(srcUrl, destUrl StorageUrl, fo *FileOperations) error {
 	return nil
 }
 
+// CloseSnapshotDb 关闭快照db，确保快照写入磁盘
+func CloseSnapshotDb(fo *FileOperations) {
+	if fo.SnapshotDb != nil {
+		fo.SnapshotDb.Close()
+		fo.SnapshotDb = nil
+	}
+}
+
 // SyncDownload 同步下载
 func SyncDownload(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo *FileOperations) error {
 	var err error
@@ -289,7 +298,8 @@ func SyncDownload(c *cos.Client, cosUrl StorageUrl, fileUrl StorageUrl, fo *File
 			return err
 		}
 
-		if len(keysToDelete) > 0 {
+		// 中断后源列表可能不完整，不执行删除
+		if len(keysToDelete) > 0 && !fo.Interrupted() {
 			// 删除源位置没有而目标位置有的cos对象或本地文件
 			err = deleteKeys(c, keysToDelete, fileUrl, fo)
 		}
@@ -325,7 +335,8 @@ func SyncCosCopy(srcClient, destClient *cos.Client, srcUrl, destUrl StorageUrl,
 			return err
 		}
 
-		if len(keysToDelete) > 0 {
+		// 中断后源列表可能不完整，不执行删除
+		if len(keysToDelete) > 0 && !fo.Interrupted() {
 			// 删除源位置没有而目标位置有的cos对象或本地文件
 			err = deleteKeys(destClient, keysToDelete, destUrl, fo)
 		}
@@ -0,0 +1,294 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// TransitionSummary 按源存储类型统计的待转换对象信息
+type TransitionSummary struct {
+	Classes   map[string]*CosInfo
+	SkipCount int
+	SkipSize  int64
+	// 未回热的归档类型对象无法复制，不计入待转换对象
+	UnrestoredCount int
+	UnrestoredSize  int64
+}
+
+// 遍历时对象的转换状态
+const (
+	transitionReady = iota
+	transitionSkip
+	transitionUnrestored
+)
+
+// TransitionObjects 批量转换cos对象的存储类型
+func TransitionObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	bucketName := cosUrl.(*CosUrl).Bucket
+	prefix := cosUrl.(*CosUrl).Object
+	targetClass := fo.Operation.StorageClass
+
+	// 第一次遍历：统计各存储类型的对象数量和大小
+	summary, err := scanTransitionObjects(c, cosUrl, fo)
+	if err != nil {
+		return err
+	}
+	renderTransitionSummary(summary, targetClass)
+	if summary.UnrestoredCount > 0 {
+		logger.Warningf("Archived objects not restored, skip count: %d, skip size: %s, please restore them first",
+			summary.UnrestoredCount, FormatSize(summary.UnrestoredSize))
+	}
+
+	if len(summary.Classes) == 0 {
+		logger.Infof("No objects need to be transitioned to %s", targetClass)
+		return nil
+	}
+
+	if fo.Operation.DryRun {
+		return nil
+	}
+
+	if !fo.Operation.Force {
+		fmt.Printf("Transition above objects of %s to %s(Y or N)? ", getCosUrl(bucketName, prefix), targetClass)
+		var val string
+		if _, err := fmt.Scanln(&val); err != nil || (strings.ToLower(val) != "yes" && strings.ToLower(val) != "y") {
+			logger.Info("Cancel transition")
+			return nil
+		}
+	}
+
+	logger.Infof("Start transition %s to %s", getCosUrl(bucketName, prefix), targetClass)
+
+	// 第二次遍历：并发转换
+	var succeedCnt, failedCnt int64
+	chObjects := make(chan cos.Object, ChannelSize)
+	var wg sync.WaitGroup
+	for i := 0; i < fo.Operation.Routines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range chObjects {
+				// 中断后不再处理新的对象
+				if fo.Interrupted() {
+					continue
+				}
+				err := TransitionObject(c, fo, bucketName, object.Key, targetClass)
+				if err != nil && fo.Interrupted() {
+					continue
+				}
+				if err != nil {
+					atomic.AddInt64(&failedCnt, 1)
+					if fo.Operation.FailOutput {
+						writeError(fmt.Sprintf("transition %s failed , errMsg:%v\n", object.Key, err), fo)
+					}
+				} else {
+					atomic.AddInt64(&succeedCnt, 1)
+				}
+			}
+		}()
+	}
+
+	err = listTransitionObjects(c, cosUrl, fo, func(object cos.Object) {
+		chObjects <- object
+	})
+	close(chObjects)
+	wg.Wait()
+	CloseErrorOutputFile(fo)
+
+	if err != nil {
+		return err
+	}
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
+
+	if failedCnt > 0 {
+		absErrOutputPath, _ := filepath.Abs(fo.ErrOutput.Path)
+		logger.Warningf("Transition %s completed, success num: %d, failed num: %d, skip num: %d, not restored num: %d, some objects transition failed, please check the detailed information in dir %s.", getCosUrl(bucketName, prefix), succeedCnt, failedCnt, summary.SkipCount, summary.UnrestoredCount, absErrOutputPath)
+	} else {
+		logger.Infof("Transition %s completed, success num: %d, failed num: %d, skip num: %d, not restored num: %d", getCosUrl(bucketName, prefix), succeedCnt, failedCnt, summary.SkipCount, summary.UnrestoredCount)
+	}
+	return nil
+}
+
+// TransitionObject 通过原地复制修改单个对象的存储类型
+func TransitionObject(c *cos.Client, fo *FileOperations, bucketName, object, storageClass string) error {
+	baseUrl, err := GenURL(fo.Config, fo.Param, bucketName)
+	if err != nil {
+		return err
+	}
+	srcURL := fmt.Sprintf("%s/%s", baseUrl.BucketURL.Host, object)
+
+	opt := &cos.MultiCopyOptions{
+		OptCopy: &cos.ObjectCopyOptions{
+			ObjectCopyHeaderOptions: &cos.ObjectCopyHeaderOptions{
+				XCosMetadataDirective: "Copy",
+				XCosStorageClass:      storageClass,
+			},
+		},
+		PartSize:       fo.Operation.PartSize,
+		ThreadPoolSize: fo.Operation.ThreadNum,
+	}
+	_, _, err = c.Object.MultiCopy(fo.Context(), object, srcURL, opt)
+	return err
+}
+
+// TransitionSingleObject 转换单个对象的存储类型，已是目标类型时跳过
+func TransitionSingleObject(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) error {
+	object := cosUrl.(*CosUrl).Object
+	resp, err := GetHead(c, object)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return fmt.Errorf("Object not found : %v", err)
+		}
+		return fmt.Errorf("Head object err : %v", err)
+	}
+
+	storageClass := resp.Header.Get("x-cos-storage-class")
+	if storageClass == "" {
+		storageClass = Standard
+	}
+	if storageClass == fo.Operation.StorageClass {
+		logger.Infof("%s is already in %s, skip", cosUrl.ToString(), storageClass)
+		return nil
+	}
+
+	// 与批量转换使用相同的筛选条件
+	if !cosObjectMatchPatterns(object, fo.Operation.Filters) ||
+		!cosObjectMatchPredicates(resp.ContentLength, resp.Header.Get("Last-Modified"), fo.Operation) {
+		logger.Infof("%s does not match the filters, skip", cosUrl.ToString())
+		return nil
+	}
+
+	archived := isRestoreType(cos.Object{StorageClass: storageClass, StorageTier: resp.Header.Get("x-cos-storage-tier")})
+	if archived && !isRestoredHeader(resp.Header) {
+		return fmt.Errorf("%s is in %s and not restored, please restore it first", cosUrl.ToString(), storageClass)
+	}
+
+	if fo.Operation.DryRun {
+		logger.Infof("Dry run, %s(%s) will be transitioned from %s to %s", cosUrl.ToString(), FormatSize(resp.ContentLength), storageClass, fo.Operation.StorageClass)
+		return nil
+	}
+
+	if !fo.Operation.Force {
+		fmt.Printf("Transition %s from %s to %s(Y or N)? ", cosUrl.ToString(), storageClass, fo.Operation.StorageClass)
+		var val string
+		if _, err := fmt.Scanln(&val); err != nil || (strings.ToLower(val) != "yes" && strings.ToLower(val) != "y") {
+			logger.Info("Cancel transition")
+			return nil
+		}
+	}
+
+	err = TransitionObject(c, fo, cosUrl.(*CosUrl).Bucket, object, fo.Operation.StorageClass)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Transition %s from %s to %s success", cosUrl.ToString(), storageClass, fo.Operation.StorageClass)
+	return nil
+}
+
+func scanTransitionObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations) (*TransitionSummary, error) {
+	summary := &TransitionSummary{Classes: make(map[string]*CosInfo)}
+	err := listObjectsForTransition(c, cosUrl, fo, func(object cos.Object, state int) {
+		switch state {
+		case transitionSkip:
+			summary.SkipCount++
+			summary.SkipSize += object.Size
+			return
+		case transitionUnrestored:
+			summary.UnrestoredCount++
+			summary.UnrestoredSize += object.Size
+			return
+		}
+		info, ok := summary.Classes[object.StorageClass]
+		if !ok {
+			info = &CosInfo{Name: object.StorageClass}
+			summary.Classes[object.StorageClass] = info
+		}
+		info.TotalFiles++
+		info.Size += object.Size
+	})
+	return summary, err
+}
+
+func listTransitionObjects(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, handle func(object cos.Object)) error {
+	return listObjectsForTransition(c, cosUrl, fo, func(object cos.Object, state int) {
+		if state == transitionReady {
+			handle(object)
+		}
+	})
+}
+
+// listObjectsForTransition 遍历前缀下符合筛选条件的对象，已是目标存储类型的对象标记为跳过
+// 归档类型对象查询回热状态，未回热完成的标记为未回热
+func listObjectsForTransition(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, handle func(object cos.Object, state int)) error {
+	return walkCosObjects(c, cosUrl, fo, func(object cos.Object) {
+		if object.StorageClass == "" {
+			object.StorageClass = Standard
+		}
+		switch {
+		case object.StorageClass == fo.Operation.StorageClass:
+			handle(object, transitionSkip)
+		case isRestoreType(object) && !transitionRestored(c, fo, object.Key):
+			handle(object, transitionUnrestored)
+		default:
+			handle(object, transitionReady)
+		}
+	})
+}
+
+// transitionRestored 查询归档类型对象是否已回热完成，查询失败时交由复制报告错误
+func transitionRestored(c *cos.Client, fo *FileOperations, key string) bool {
+	resp, err := c.Object.Head(fo.Context(), key, nil)
+	if err != nil {
+		return true
+	}
+	return isRestoredHeader(resp.Header)
+}
+
+// isRestoredHeader 根据x-cos-restore判断对象是否已回热完成
+func isRestoredHeader(header http.Header) bool {
+	restore := header.Get("x-cos-restore")
+	return restore != "" && !strings.Contains(restore, "ongoing-request=\"true\"")
+}
+
+func renderTransitionSummary(summary *TransitionSummary, targetClass string) {
+	classes := make([]string, 0, len(summary.Classes))
+	for class := range summary.Classes {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	var totalCnt int
+	var totalSize int64
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Source Class", "Objects Count", "Total Size"})
+	for _, class := range classes {
+		info := summary.Classes[class]
+		table.Append([]string{class, fmt.Sprintf("%d", info.TotalFiles), FormatSize(info.Size)})
+		totalCnt += info.TotalFiles
+		totalSize += info.Size
+	}
+	table.SetFooter([]string{"To " + targetClass, fmt.Sprintf("%d", totalCnt), FormatSize(totalSize)})
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetBorders(tablewriter.Border{
+		Left:   false,
+		Right:  false,
+		Top:    false,
+		Bottom: true,
+	})
+	table.Render()
+	if summary.SkipCount > 0 {
+		logger.Infof("Already in %s, skip count: %d, skip size: %s", targetClass, summary.SkipCount, FormatSize(summary.SkipSize))
+	}
+}
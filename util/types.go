@@ -1,10 +1,12 @@
 package util
 
 import (
+	"context"
 	"github.com/olekukonko/tablewriter"
 	"github.com/syndtr/goleveldb/leveldb"
 	"net/http"
 	"os"
+	"time"
 )
 
 // Config coscli配置文件
@@ -94,12 +96,14 @@ type FileOperations struct {
 	Config               *Config
 	Param                *Param
 	SnapshotDb           *leveldb.DB
+	ProgressDb           *leveldb.DB
 	CpType               CpType
 	Command              string
 	DeleteCount          int
 	SyncDeleteObjectInfo SyncDeleteObjectInfo
 	BucketType           string
 	OutPutDirName        string
+	Ctx                  context.Context
 }
 
 // Operation 文件操作参数
@@ -152,6 +156,29 @@ type Operation struct {
 	SSECustomerKey       string
 	SSECustomerKeyMD5    string
 	IgnoreEmptyFile      bool
+	MinSize              int64
+	MaxSize              int64
+	OlderThan            time.Duration
+	NewerThan            time.Duration
+	DryRun               bool
+	ObjectLockMode       string
+	RetainUntil          string
+	ProgressPath         string
+	WaitInterval         time.Duration
+	WaitTimeout          time.Duration
+	FromInventory        string
+	Atomic               bool
+	ExcludeUploadIds     []string
+	ClientEncryptKey     []byte
+	Compress             string
+	CompressExt          bool
+	Decompress           bool
+	Archive              string
+	Extract              bool
+	Range                string
+	Head                 int64
+	Tail                 int64
+	Lines                bool
 }
 
 // ErrOutput 错误输出信息
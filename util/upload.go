@@ -81,14 +81,28 @@ func Upload(c *cos.Client, fileUrl StorageUrl, cosUrl StorageUrl, fo *FileOperat
 	wgLogger.Wait()
 
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
 }
 
 func uploadFiles(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, chFiles <-chan fileInfoType, chError chan<- error, chLog chan<- string) {
+	uploadFilesWith(fo, chFiles, chError, chLog, func(file fileInfoType) (bool, error, bool, int64, int64, string) {
+		return SingleUpload(c, fo, file, cosUrl)
+	})
+}
+
+// singleUploadFunc 处理单个文件，返回值与SingleUpload一致
+type singleUploadFunc func(file fileInfoType) (skip bool, rErr error, isDir bool, size, transferSize int64, msg string)
+
+// uploadFilesWith 上传工作协程，按重试配置逐个处理文件并更新进度及进程日志
+func uploadFilesWith(fo *FileOperations, chFiles <-chan fileInfoType, chError chan<- error, chLog chan<- string, upload singleUploadFunc) {
 	for file := range chFiles {
+		// 中断后不再处理新的文件
+		if fo.Interrupted() {
+			continue
+		}
 		var skip, isDir bool
 		var err error
 		var size, transferSize int64
@@ -97,7 +111,7 @@ func uploadFiles(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, chFiles <
 		var sleepTime time.Duration
 		for retry := 0; retry <= fo.Operation.ErrRetryNum; retry++ {
 			startT := time.Now().UnixNano() / 1000 / 1000
-			skip, err, isDir, size, transferSize, msg = SingleUpload(c, fo, file, cosUrl)
+			skip, err, isDir, size, transferSize, msg = upload(file)
 			endT := time.Now().UnixNano() / 1000 / 1000
 			costTime := int(endT - startT)
 			skipMsg := ""
@@ -120,7 +134,10 @@ func uploadFiles(c *cos.Client, cosUrl StorageUrl, fo *FileOperations, chFiles <
 			if err == nil {
 				break // Upload succeeded, break the loop
 			} else {
-
+				// 中断后不再重试
+				if fo.Interrupted() {
+					break
+				}
 				if fo.Operation.ErrRetryInterval == 0 {
 					// If the retry interval is not specified, retry after a random interval of 1~10 seconds.
 					sleepTime = time.Duration(rand.Intn(10)+1) * time.Second
@@ -162,6 +179,11 @@ func SingleUpload(c *cos.Client, fo *FileOperations, file fileInfoType, cosUrl S
 		return
 	}
 
+	// 压缩上传时按需追加扩展名
+	if fo.Operation.Compress != "" && fo.Operation.CompressExt && !fileInfo.IsDir() {
+		cosPath += CompressExt(fo.Operation.Compress)
+	}
+
 	var snapshotKey string
 
 	msg = fmt.Sprintf("Upload %s to %s", localFilePath, getCosUrl(cosUrl.(*CosUrl).Bucket, cosPath))
@@ -268,25 +290,45 @@ func SingleUpload(c *cos.Client, fo *FileOperations, file fileInfoType, cosUrl S
 		if fo.Operation.ForbidOverWrite {
 			opt.OptIni.XOptionHeader.Add("x-cos-forbid-overwrite", "true")
 		}
-		isPart := true
-		if fo.Operation.PartSize > size {
-			isPart = false
+		if fo.Operation.ObjectLockMode != "" {
+			setObjectLockHeader(opt.OptIni.XOptionHeader, fo.Operation.ObjectLockMode, fo.Operation.RetainUntil)
 		}
-		counter := &Counter{TransferSize: 0}
-		// 未跳过则通过监听更新size
-		opt.OptIni.Listener = &CosListener{fo, counter}
-		// 使用process更新进度，置零size
-		size = 0
+		// 客户端加密上传，完成后按文件大小更新进度
+		if len(fo.Operation.ClientEncryptKey) > 0 {
+			err = clientEncryptUpload(c, fo, localFilePath, cosPath, opt)
+			if err != nil {
+				rErr = err
+				return
+			}
+		} else if fo.Operation.Compress != "" {
+			// 压缩上传，完成后按文件大小更新进度
+			err = compressUpload(c, fo, localFilePath, cosPath, opt)
+			if err != nil {
+				rErr = err
+				return
+			}
+		} else {
+			isPart := true
+			if fo.Operation.PartSize > size {
+				isPart = false
+			}
+			counter := &Counter{TransferSize: 0}
+			// 未跳过则通过监听更新size
+			opt.OptIni.Listener = &CosListener{fo, counter}
+			// 使用process更新进度，置零size
+			size = 0
 
-		_, _, err = c.Object.Upload(context.Background(), cosPath, localFilePath, opt)
+			_, _, err = c.Object.Upload(transferContext(fo), cosPath, localFilePath, opt)
 
-		if err != nil {
-			if (!isPart) || (isPart && !fo.Operation.CheckPoint) {
-				transferSize = counter.TransferSize
+			if err != nil {
+				if (!isPart) || (isPart && !fo.Operation.CheckPoint) {
+					transferSize = counter.TransferSize
+				}
+				rErr = err
+				return
 			}
-			rErr = err
-			return
 		}
+
 	}
 
 	if snapshotKey != "" && fo.Operation.SnapshotPath != "" && fo.Command == CommandSync {
@@ -352,7 +394,7 @@ func UploadWithDelete(c *cos.Client, cosUrl StorageUrl, srcKeys, uploadKeys map[
 	wgLogger.Wait()
 
 	closeProgress()
-	fmt.Printf(fo.Monitor.progressBar(true, normalExit))
+	fmt.Printf(fo.Monitor.progressBar(true, finishExitStat(fo)))
 
 	endT := time.Now().UnixNano() / 1000 / 1000
 	PrintTransferStats(startT, endT, fo)
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 )
@@ -193,11 +194,49 @@ func GetPartsListForLs(c *cos.Client, cosUrl StorageUrl, uploadId, partNumberMar
 	return
 }
 
+// abortReport 待清理上传碎片统计
+type abortReport struct {
+	classes map[string]*abortClassStat
+	uploads int
+	parts   int
+	size    int64
+}
+
+type abortClassStat struct {
+	uploads int
+	parts   int
+	size    int64
+}
+
+func (r *abortReport) add(storageClass string, parts int, size int64) {
+	if storageClass == "" {
+		storageClass = Standard
+	}
+	stat, ok := r.classes[storageClass]
+	if !ok {
+		stat = &abortClassStat{}
+		r.classes[storageClass] = stat
+	}
+	stat.uploads++
+	stat.parts += parts
+	stat.size += size
+	r.uploads++
+	r.parts += parts
+	r.size += size
+}
+
 // AbortUploads 清理上传碎片
 func AbortUploads(args []string, fo *FileOperations) error {
-	for _, arg := range args {
+	excludeIds := make(map[string]bool)
+	for _, id := range fo.Operation.ExcludeUploadIds {
+		excludeIds[id] = true
+	}
 
+	for _, arg := range args {
 		cosUrl, err := FormatUrl(arg)
+		if err != nil {
+			return fmt.Errorf("cos url format error:%v", err)
+		}
 		bucketName := cosUrl.(*CosUrl).Bucket
 		c, err := NewClient(fo.Config, fo.Param, bucketName)
 		if err != nil {
@@ -207,9 +246,10 @@ func AbortUploads(args []string, fo *FileOperations) error {
 		isTruncated := true
 		var keyMarker, uploadIDMarker string
 
-		failCnt, successCnt, total := 0, 0, 0
+		failCnt, successCnt, skipCnt, total := 0, 0, 0, 0
+		report := &abortReport{classes: make(map[string]*abortClassStat)}
 		logger.Infoln("Abort", getCosUrl(cosUrl.(*CosUrl).Bucket, cosUrl.(*CosUrl).Object), "Start")
-		if isTruncated {
+		for isTruncated && !fo.Interrupted() {
 			var uploads []struct {
 				Key          string
 				UploadID     string `xml:"UploadId"`
@@ -223,36 +263,128 @@ func AbortUploads(args []string, fo *FileOperations) error {
 				return fmt.Errorf("list uploads error : %v", err)
 			}
 			for _, upload := range uploads {
+				if fo.Interrupted() {
+					break
+				}
 				upload.Key, _ = url.QueryUnescape(upload.Key)
-				_, err := c.Object.AbortMultipartUpload(context.Background(), upload.Key, upload.UploadID)
+				if !abortUploadMatch(upload.Key, upload.UploadID, upload.Initiated, excludeIds, fo) {
+					skipCnt++
+					continue
+				}
+
+				partCnt, size, err := sumUploadParts(c, bucketName, upload.Key, upload.UploadID)
+				if err != nil {
+					logger.Warningf("List parts fail! UploadID: %s,Key: %s,err: %v", upload.UploadID, upload.Key, err)
+				}
+				report.add(upload.StorageClass, partCnt, size)
+				total++
+
+				if fo.Operation.DryRun {
+					logger.Infof("Abort(dry run) UploadID: %s,Key: %s,Initiated: %s,Parts: %d,Size: %s", upload.UploadID, upload.Key, upload.Initiated, partCnt, FormatSize(size))
+					continue
+				}
+
+				_, err = c.Object.AbortMultipartUpload(fo.Context(), upload.Key, upload.UploadID)
+				if err != nil && fo.Interrupted() {
+					break
+				}
 				if err != nil {
 					logger.Infof("Abort fail! UploadID: %s,Key: %s", upload.UploadID, upload.Key)
 					// 记录错误日志
 					if fo.Operation.FailOutput {
-						writeError(fmt.Sprintln("Abort fail! UploadID: %s,Key: %s,err: %v", upload.UploadID, upload.Key, err), fo)
+						writeError(fmt.Sprintf("Abort fail! UploadID: %s,Key: %s,err: %v\n", upload.UploadID, upload.Key, err), fo)
 					}
 					failCnt++
 				} else {
 					logger.Infof("Abort success! UploadID: %s,Key: %s", upload.UploadID, upload.Key)
 					successCnt++
 				}
-				total++
 			}
 		}
 
-		logger.Infof("Abort %s Completed , Total: %d,%d Success, %d Fail", getCosUrl(cosUrl.(*CosUrl).Bucket, cosUrl.(*CosUrl).Object), total, successCnt, failCnt)
+		printAbortReport(report)
+		if fo.Operation.DryRun {
+			logger.Infof("Abort(dry run) %s Completed , Total: %d,%d Skip", getCosUrl(cosUrl.(*CosUrl).Bucket, cosUrl.(*CosUrl).Object), total, skipCnt)
+			continue
+		}
+		logger.Infof("Abort %s Completed , Total: %d,%d Success, %d Fail, %d Skip", getCosUrl(cosUrl.(*CosUrl).Bucket, cosUrl.(*CosUrl).Object), total, successCnt, failCnt, skipCnt)
 		if failCnt > 0 && fo.Operation.FailOutput {
 			absErrOutputPath, _ := filepath.Abs(fo.ErrOutput.Path)
 			logger.Infof("Some uploads Abort failed, please check the detailed information in dir %s.\n", absErrOutputPath)
 		}
-		return nil
 	}
-	// 打印一个空行
-	fmt.Println()
+	if fo.Interrupted() {
+		return ErrInterrupted
+	}
 
 	return nil
 }
 
+// abortUploadMatch 判断上传任务是否需要清理：匹配include/exclude、不在排除的UploadId中且初始化时间早于--older-than
+func abortUploadMatch(key, uploadId, initiated string, excludeIds map[string]bool, fo *FileOperations) bool {
+	if excludeIds[uploadId] {
+		return false
+	}
+	if !cosObjectMatchPatterns(key, fo.Operation.Filters) {
+		return false
+	}
+	if fo.Operation.OlderThan <= 0 {
+		return true
+	}
+	initiatedTime, err := time.Parse(time.RFC3339, initiated)
+	if err != nil {
+		// 无法确定初始化时间时不清理，避免误删进行中的上传
+		logger.Warningf("Skip upload with invalid initiated time! UploadID: %s,Key: %s,Initiated: %s", uploadId, key, initiated)
+		return false
+	}
+	return time.Since(initiatedTime) >= fo.Operation.OlderThan
+}
+
+// sumUploadParts 统计上传任务已上传分块的数量及大小
+func sumUploadParts(c *cos.Client, bucketName, key, uploadId string) (count int, size int64, err error) {
+	cosUrl := &CosUrl{Bucket: bucketName, Object: key}
+	isTruncated := true
+	partNumberMarker := ""
+	for isTruncated {
+		var parts []cos.Object
+		err, parts, isTruncated, partNumberMarker = GetPartsListForLs(c, cosUrl, uploadId, partNumberMarker, 1000)
+		if err != nil {
+			return
+		}
+		for _, part := range parts {
+			count++
+			size += part.Size
+		}
+	}
+	return
+}
+
+func printAbortReport(report *abortReport) {
+	classes := make([]string, 0, len(report.classes))
+	for class := range report.classes {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Storage Class", "Uploads Count", "Parts Count", "Total Size"})
+	for _, class := range classes {
+		stat := report.classes[class]
+		table.Append([]string{class, fmt.Sprintf("%d", stat.uploads), fmt.Sprintf("%d", stat.parts), FormatSize(stat.size)})
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.SetBorders(tablewriter.Border{
+		Left:   false,
+		Right:  false,
+		Top:    false,
+		Bottom: true,
+	})
+	table.Render()
+	logger.Infof("Total Uploads Count: %d\n", report.uploads)
+	logger.Infof("Total Parts Count:   %d\n", report.parts)
+	logger.Infof("Total Parts Size:    %s\n", FormatSize(report.size))
+}
+
 // GetUploadsListRecursive 获取上传任务列表
 func GetUploadsListRecursive(c *cos.Client, prefix string, limit int, include string, exclude string) (uploads []UploadInfo, err error) {
 	opt := &cos.ListMultipartUploadsOptions{
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	logger "github.com/sirupsen/logrus"
+	"github.com/tencentyun/cos-go-sdk-v5"
+)
+
+// PutBucketWebsite 设置存储桶静态网站
+func PutBucketWebsite(c *cos.Client, website string) error {
+	configurationContent, err := GetContent(website)
+	if err != nil {
+		return err
+	}
+	var opt cos.BucketPutWebsiteOptions
+	err = ParseContent(configurationContent, &opt, ContentTypeWebsite)
+	if err != nil {
+		return err
+	}
+	if opt.Index == "" {
+		return fmt.Errorf("no website index document provided")
+	}
+	_, err = c.Bucket.PutWebsite(context.Background(), &opt)
+	if err != nil {
+		return err
+	}
+	logger.Info("Put Bucket Website Success")
+	return nil
+}
+
+// GetBucketWebsite 查询存储桶静态网站
+func GetBucketWebsite(c *cos.Client) error {
+	res, _, err := c.Bucket.GetWebsite(context.Background())
+	if err != nil {
+		return err
+	}
+	// 渲染表格
+	renderWebsiteTable(res)
+	return nil
+}
+
+// DeleteBucketWebsite 删除存储桶静态网站
+func DeleteBucketWebsite(c *cos.Client) error {
+	_, err := c.Bucket.DeleteWebsite(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Info("Delete Bucket Website Success")
+	return nil
+}
+
+func renderWebsiteTable(website *cos.BucketGetWebsiteResult) {
+	if website == nil || (website.Index == "" && website.RedirectProtocol == nil) {
+		fmt.Println("No website found")
+		return
+	}
+
+	// 创建表格
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Section", "Key", "Value"})
+	table.SetAutoMergeCells(true)
+	table.SetRowLine(true)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.SetCaption(true, "Bucket Website Information")
+
+	table.Append([]string{"Website", "IndexDocument", website.Index})
+	if website.RedirectProtocol != nil {
+		table.Append([]string{"Website", "RedirectAllRequestsTo", website.RedirectProtocol.Protocol})
+	}
+	if website.AutoAddressing != nil {
+		table.Append([]string{"Website", "AutoAddressing", website.AutoAddressing.Status})
+	}
+	if website.Error != nil {
+		table.Append([]string{"Website", "ErrorDocument", website.Error.Key})
+		if website.Error.OriginalHttpStatus != "" {
+			table.Append([]string{"Website", "OriginalHttpStatus", website.Error.OriginalHttpStatus})
+		}
+	}
+
+	// 添加路由规则
+	if website.RoutingRules != nil {
+		for i, rule := range website.RoutingRules.Rules {
+			section := fmt.Sprintf("RoutingRule #%d", i+1)
+			table.Append([]string{"", "", ""})
+			if rule.ConditionErrorCode != "" {
+				table.Append([]string{section, "HttpErrorCodeReturnedEquals", rule.ConditionErrorCode})
+			}
+			if rule.ConditionPrefix != "" {
+				table.Append([]string{section, "KeyPrefixEquals", rule.ConditionPrefix})
+			}
+			if rule.RedirectProtocol != "" {
+				table.Append([]string{section, "Protocol", rule.RedirectProtocol})
+			}
+			if rule.RedirectReplaceKey != "" {
+				table.Append([]string{section, "ReplaceKeyWith", rule.RedirectReplaceKey})
+			}
+			if rule.RedirectReplaceKeyPrefix != "" {
+				table.Append([]string{section, "ReplaceKeyPrefixWith", rule.RedirectReplaceKeyPrefix})
+			}
+		}
+	}
+
+	// 渲染表格
+	table.Render()
+}